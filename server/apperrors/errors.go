@@ -0,0 +1,72 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code classifies an error for API clients
+type Code string
+
+const (
+	CodeNotFound         Code = "NOT_FOUND"
+	CodeInvalidArgument  Code = "INVALID_ARGUMENT"
+	CodeConflict         Code = "CONFLICT"
+	CodePermissionDenied Code = "PERMISSION_DENIED"
+	CodeInternal         Code = "INTERNAL"
+)
+
+// internalMessage is shown to clients instead of the details of an internal error
+const internalMessage = "internal server error"
+
+// Error is a domain error whose message is safe to show to clients
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFound reports a missing resource
+func NotFound(format string, args ...any) error {
+	return &Error{Code: CodeNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// InvalidArgument reports input the request cannot be served with
+func InvalidArgument(format string, args ...any) error {
+	return &Error{Code: CodeInvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict reports a request that clashes with the current state of a resource
+func Conflict(format string, args ...any) error {
+	return &Error{Code: CodeConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// PermissionDenied reports a request the caller is not allowed to make
+func PermissionDenied(format string, args ...any) error {
+	return &Error{Code: CodePermissionDenied, Message: fmt.Sprintf(format, args...)}
+}
+
+// Internal wraps an unexpected error, hiding its details from clients
+func Internal(err error) error {
+	return &Error{Code: CodeInternal, Message: internalMessage, Err: err}
+}
+
+// As returns the domain error in err's chain, treating any other error as internal
+func As(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return &Error{Code: CodeInternal, Message: internalMessage, Err: err}
+}
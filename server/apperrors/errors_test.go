@@ -0,0 +1,55 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAs(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    Code
+		message string
+	}{
+		{
+			name:    "not found",
+			err:     NotFound("quiz %d not found", 1),
+			code:    CodeNotFound,
+			message: "quiz 1 not found",
+		},
+		{
+			name:    "wrapped conflict",
+			err:     fmt.Errorf("submit: %w", Conflict("quiz %d is not published", 1)),
+			code:    CodeConflict,
+			message: "quiz 1 is not published",
+		},
+		{
+			name:    "plain error",
+			err:     errors.New(`pq: relation "quizzes" does not exist`),
+			code:    CodeInternal,
+			message: internalMessage,
+		},
+		{
+			name:    "internal error",
+			err:     Internal(errors.New("connection refused")),
+			code:    CodeInternal,
+			message: internalMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := As(tt.err)
+
+			if appErr.Code != tt.code {
+				t.Errorf("expected code '%s', got '%s'", tt.code, appErr.Code)
+			}
+
+			if appErr.Message != tt.message {
+				t.Errorf("expected message '%s', got '%s'", tt.message, appErr.Message)
+			}
+		})
+	}
+}
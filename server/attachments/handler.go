@@ -0,0 +1,66 @@
+// Package attachments serves the files attached to questions over HTTP
+package attachments
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"quiz-log/apperrors"
+	"quiz-log/models"
+)
+
+// Opener opens an attachment with its file, such as *services.AttachmentService
+type Opener interface {
+	OpenAttachment(ctx context.Context, id string) (*models.Attachment, io.ReadCloser, error)
+}
+
+// Handler serves attachments by the ID in the {id} wildcard of its route
+type Handler struct {
+	Attachments Opener
+}
+
+// NewHandler creates a Handler serving the attachments opened by o
+func NewHandler(o Opener) *Handler {
+	return &Handler{Attachments: o}
+}
+
+// ServeHTTP sends the file with the content type sniffed when it was uploaded. Browsers are told
+// not to sniff again nor run anything in it, so a file passed off as an image stays inert.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	attachment, content, err := h.Attachments.OpenAttachment(ctx, r.PathValue("id"))
+	if err != nil {
+		switch apperrors.As(err).Code {
+		case apperrors.CodeNotFound, apperrors.CodeInvalidArgument:
+			http.NotFound(w, r)
+		default:
+			slog.ErrorContext(ctx, "failed to open attachment", "id", r.PathValue("id"), "error", err)
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	defer content.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", attachment.ContentType)
+	header.Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": attachment.Filename}))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	// Attachments never change, only get deleted
+	header.Set("Cache-Control", "private, max-age=3600")
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	// Never send more than was recorded, even if the stored file grew
+	if _, err := io.CopyN(w, content, attachment.Size); err != nil {
+		slog.WarnContext(ctx, "failed to send attachment", "id", attachment.ID, "error", err)
+	}
+}
@@ -0,0 +1,115 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"quiz-log/apperrors"
+	"quiz-log/models"
+)
+
+type openerFunc func(ctx context.Context, id string) (*models.Attachment, io.ReadCloser, error)
+
+func (f openerFunc) OpenAttachment(ctx context.Context, id string) (*models.Attachment, io.ReadCloser, error) {
+	return f(ctx, id)
+}
+
+func serve(t *testing.T, opener Opener, method string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /attachments/{id}", NewHandler(opener))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(method, "/attachments/7", nil))
+	return rec
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	opener := openerFunc(func(ctx context.Context, id string) (*models.Attachment, io.ReadCloser, error) {
+		if id != "7" {
+			t.Errorf("expected ID '7', got %q", id)
+		}
+		attachment := &models.Attachment{ID: 7, Filename: "water molecule.png", ContentType: "image/png", Size: 4}
+		return attachment, io.NopCloser(strings.NewReader("\x89PNG and more")), nil
+	})
+
+	// Execute
+	rec := serve(t, opener, http.MethodGet)
+
+	// Assert
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if body := rec.Body.String(); body != "\x89PNG" {
+		t.Errorf("expected only the recorded size to be sent, got %q", body)
+	}
+
+	expected := map[string]string{
+		"Content-Type":           "image/png",
+		"Content-Length":         "4",
+		"Content-Disposition":    `inline; filename="water molecule.png"`,
+		"X-Content-Type-Options": "nosniff",
+	}
+	for key, value := range expected {
+		if got := rec.Header().Get(key); got != value {
+			t.Errorf("expected %s %q, got %q", key, value, got)
+		}
+	}
+	if !strings.Contains(rec.Header().Get("Content-Security-Policy"), "sandbox") {
+		t.Errorf("expected a sandboxing policy, got %q", rec.Header().Get("Content-Security-Policy"))
+	}
+}
+
+func TestHandler_ServeHTTP_Head(t *testing.T) {
+	opener := openerFunc(func(ctx context.Context, id string) (*models.Attachment, io.ReadCloser, error) {
+		return &models.Attachment{ID: 7, Filename: "a.pdf", ContentType: "application/pdf", Size: 3}, io.NopCloser(strings.NewReader("PDF")), nil
+	})
+
+	// Execute
+	rec := serve(t, opener, http.MethodHead)
+
+	// Assert
+	if rec.Code != http.StatusOK || rec.Body.Len() != 0 {
+		t.Errorf("expected headers only, got status %d and %d bytes", rec.Code, rec.Body.Len())
+	}
+	if got := rec.Header().Get("Content-Length"); got != "3" {
+		t.Errorf("expected Content-Length '3', got %q", got)
+	}
+}
+
+func TestHandler_ServeHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "missing", err: apperrors.NotFound("attachment 7 not found"), expected: http.StatusNotFound},
+		{name: "invalid ID", err: apperrors.InvalidArgument("invalid attachment ID"), expected: http.StatusNotFound},
+		{name: "storage failure", err: errors.New("connection refused"), expected: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opener := openerFunc(func(ctx context.Context, id string) (*models.Attachment, io.ReadCloser, error) {
+				return nil, nil, tt.err
+			})
+
+			// Execute
+			rec := serve(t, opener, http.MethodGet)
+
+			// Assert
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, rec.Code)
+			}
+			if strings.Contains(rec.Body.String(), "connection refused") {
+				t.Error("expected the cause of the error to be hidden")
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// Package blob stores the files attached to questions, on the local filesystem or in an S3-compatible bucket.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+)
+
+// Storage backends selectable with Config.Storage
+const (
+	StorageFS = "fs"
+	StorageS3 = "s3"
+)
+
+type Config struct {
+	Storage string
+	// Dir is the directory of the filesystem storage
+	Dir string
+	S3  S3Config
+}
+
+// ErrNotFound is returned when no blob is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps blobs under opaque keys chosen by the caller
+type Store interface {
+	// Put stores size bytes read from r under key, replacing any blob stored there
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key, to be closed by the caller
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key, succeeding when there is none
+	Delete(ctx context.Context, key string) error
+}
+
+// Open creates the Store selected by the configuration
+func Open(cfg Config) (Store, error) {
+	switch cfg.Storage {
+	case StorageFS, "":
+		return NewFSStore(cfg.Dir)
+	case StorageS3:
+		if cfg.S3.Endpoint == "" || cfg.S3.Bucket == "" {
+			return nil, errors.New("S3 storage requires an endpoint and a bucket")
+		}
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob storage %q", cfg.Storage)
+	}
+}
+
+// validKey matches the keys both stores accept, a single path segment safe as a file name and an object name
+var validKey = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,199}$`)
+
+func checkKey(key string) error {
+	if !validKey.MatchString(key) {
+		return errors.New("invalid blob key " + key)
+	}
+	return nil
+}
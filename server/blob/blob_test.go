@@ -0,0 +1,210 @@
+package blob
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testStore runs the behaviour every Store shares
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	t.Run("put and get", func(t *testing.T) {
+		if err := store.Put(ctx, "a1.png", strings.NewReader("first"), 5, "image/png"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := read(t, store, "a1.png"); got != "first" {
+			t.Errorf("expected 'first', got %q", got)
+		}
+
+		// Putting again replaces the blob
+		if err := store.Put(ctx, "a1.png", strings.NewReader("second"), 6, "image/png"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := read(t, store, "a1.png"); got != "second" {
+			t.Errorf("expected 'second', got %q", got)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		if err := store.Put(ctx, "b2", strings.NewReader("data"), 4, "text/plain"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := store.Delete(ctx, "b2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if _, err := store.Get(ctx, "b2"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+
+		// Deleting a missing blob succeeds
+		if err := store.Delete(ctx, "b2"); err != nil {
+			t.Errorf("unexpected error deleting twice: %v", err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		if _, err := store.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, key := range []string{"", "../escape", "dir/file", ".hidden"} {
+			if err := store.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+				t.Errorf("expected an error for key %q", key)
+			}
+		}
+	})
+}
+
+func read(t *testing.T, store Store, key string) string {
+	t.Helper()
+
+	rc, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(data)
+}
+
+func TestFSStore(t *testing.T) {
+	store, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer store.Close()
+
+	testStore(t, store)
+
+	t.Run("short write", func(t *testing.T) {
+		err := store.Put(context.Background(), "short", strings.NewReader("abc"), 10, "text/plain")
+		if err == nil {
+			t.Fatal("expected an error when fewer bytes than announced are read")
+		}
+		if _, err := store.Get(context.Background(), "short"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected no blob after a failed put, got %v", err)
+		}
+	})
+}
+
+func TestS3Store(t *testing.T) {
+	server := httptest.NewServer(newFakeS3("attachments"))
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		Bucket:    "attachments",
+		Region:    "us-east-1",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	testStore(t, store)
+}
+
+// fakeS3 is a local stand-in for an S3 service holding a single bucket. It ignores
+// authentication and supports the object operations the store uses.
+type fakeS3 struct {
+	bucket  string
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3(bucket string) *fakeS3 {
+	return &fakeS3{bucket: bucket, objects: make(map[string][]byte)}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		s3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readPayload(r)
+		if err != nil {
+			s3Error(w, http.StatusBadRequest, "IncompleteBody")
+			return
+		}
+		f.objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			s3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 19 Oct 2026 09:00:00 GMT")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// readPayload reads the body of a PUT, decoding the signed chunks of streaming uploads
+func readPayload(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var data []byte
+	body := bufio.NewReader(r.Body)
+	for {
+		header, err := body.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return data, nil
+		}
+
+		chunk := make([]byte, size+2)
+		if _, err := io.ReadFull(body, chunk); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk[:size]...)
+	}
+}
+
+func s3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, _ = fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client sends GraphQL operations to the quiz-log API
+type Client struct {
+	Endpoint string
+	Token    string
+	HTTP     *http.Client
+}
+
+// NewClient creates a Client for the endpoint and token of the config
+func NewClient(cfg *Config) *Client {
+	return &Client{
+		Endpoint: cfg.Endpoint,
+		Token:    cfg.Token,
+		HTTP:     &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// GraphQLError is an error reported by the API for an operation
+type GraphQLError struct {
+	Message    string         `json:"message"`
+	Path       []any          `json:"path"`
+	Extensions map[string]any `json:"extensions"`
+}
+
+// Errors lists the errors of a failed operation
+type Errors []GraphQLError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+		if code, ok := err.Extensions["code"].(string); ok {
+			messages[i] = fmt.Sprintf("%s (%s)", err.Message, code)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Do runs an operation and decodes its data into out
+func (c *Client) Do(ctx context.Context, query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors Errors          `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("unexpected response from %s (%s): %w", c.Endpoint, resp.Status, err)
+	}
+
+	if len(result.Errors) > 0 {
+		return result.Errors
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Data, out)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Do(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("expected bearer token, got '%s'", got)
+		}
+
+		var body struct {
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if body.Variables["id"] != "1" {
+			t.Errorf("expected variable id '1', got %v", body.Variables["id"])
+		}
+
+		w.Write([]byte(`{"data":{"quiz":{"id":"1","title":"Go basics"}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{Endpoint: server.URL, Token: "secret"})
+
+	// Execute
+	var data struct {
+		Quiz quiz `json:"quiz"`
+	}
+	err := client.Do(context.Background(), `query { quiz(id: $id) { id title } }`, map[string]any{"id": "1"}, &data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data.Quiz.Title != "Go basics" {
+		t.Errorf("expected title 'Go basics', got '%s'", data.Quiz.Title)
+	}
+}
+
+func TestClient_Do_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"errors":[{"message":"quiz 9 not found","extensions":{"code":"NOT_FOUND"}}],"data":null}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&Config{Endpoint: server.URL})
+
+	// Execute
+	err := client.Do(context.Background(), `query { quiz(id: "9") { id } }`, nil, nil)
+
+	// Assert
+	var gqlErrs Errors
+	if !errors.As(err, &gqlErrs) {
+		t.Fatalf("expected GraphQL errors, got %v", err)
+	}
+
+	if err.Error() != "quiz 9 not found (NOT_FOUND)" {
+		t.Errorf("unexpected error message '%s'", err.Error())
+	}
+}
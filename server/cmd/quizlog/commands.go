@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type tag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type quiz struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description"`
+	Status      string     `json:"status"`
+	Tags        []tag      `json:"tags"`
+	Questions   []question `json:"questions"`
+}
+
+type question struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	Content       string    `json:"content"`
+	Options       []string  `json:"options"`
+	Matching      *matching `json:"matching"`
+	Numeric       *numeric  `json:"numeric"`
+	Cloze         *cloze    `json:"cloze"`
+	CorrectAnswer string    `json:"correctAnswer"`
+	Explanation   *string   `json:"explanation"`
+	Difficulty    string    `json:"difficulty"`
+}
+
+type matching struct {
+	Prompts []string `json:"prompts"`
+	Answers []string `json:"answers"`
+}
+
+type numeric struct {
+	Units []string `json:"units"`
+}
+
+type cloze struct {
+	Blanks int `json:"blanks"`
+}
+
+type attempt struct {
+	ID             string    `json:"id"`
+	QuizID         string    `json:"quizID"`
+	StartedAt      time.Time `json:"startedAt"`
+	Score          int       `json:"score"`
+	TotalQuestions int       `json:"totalQuestions"`
+}
+
+const questionFields = `id type content options matching { prompts answers } numeric { units } cloze { blanks } correctAnswer explanation difficulty`
+
+func runConfig(ctx context.Context, a *app, args []string) error {
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	endpoint := flags.String("endpoint", a.client.Endpoint, "GraphQL endpoint of the server")
+	token := flags.String("token", a.client.Token, "token sent as a bearer Authorization header")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := &Config{Endpoint: *endpoint, Token: *token}
+	if err := cfg.Save(a.configPath); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "Saved config to %s\n", a.configPath)
+	return nil
+}
+
+func runQuizzes(ctx context.Context, a *app, args []string) error {
+	flags := flag.NewFlagSet("quizzes", flag.ContinueOnError)
+	status := flags.String("status", "PUBLISHED", "only list quizzes with this status (DRAFT, PUBLISHED or ARCHIVED)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var data struct {
+		Quizzes []quiz `json:"quizzes"`
+	}
+	err := a.client.Do(ctx, `query QuizlogQuizzes($status: QuizStatus) {
+		quizzes(status: $status) { id title status tags { name } questions { id } }
+	}`, map[string]any{"status": strings.ToUpper(*status)}, &data)
+	if err != nil {
+		return err
+	}
+
+	if a.json {
+		return a.printJSON(data.Quizzes)
+	}
+
+	rows := make([][]string, len(data.Quizzes))
+	for i, q := range data.Quizzes {
+		names := make([]string, len(q.Tags))
+		for j, t := range q.Tags {
+			names[j] = t.Name
+		}
+		rows[i] = []string{q.ID, q.Title, q.Status, fmt.Sprint(len(q.Questions)), strings.Join(names, ", ")}
+	}
+	return a.printTable([]string{"ID", "TITLE", "STATUS", "QUESTIONS", "TAGS"}, rows)
+}
+
+func runTake(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: quizlog take QUIZ_ID")
+	}
+
+	var data struct {
+		Quiz *quiz `json:"quiz"`
+	}
+	err := a.client.Do(ctx, `query QuizlogTakeQuiz($id: ID!) {
+		quiz(id: $id) { id title description questions { `+questionFields+` } }
+	}`, map[string]any{"id": args[0]}, &data)
+	if err != nil {
+		return err
+	}
+	if data.Quiz == nil {
+		return fmt.Errorf("quiz %s not found", args[0])
+	}
+	if len(data.Quiz.Questions) == 0 {
+		return fmt.Errorf("quiz %s has no questions", args[0])
+	}
+
+	if !a.json {
+		fmt.Fprintf(a.out, "%s (%d questions)\n", data.Quiz.Title, len(data.Quiz.Questions))
+		if data.Quiz.Description != nil {
+			fmt.Fprintln(a.out, *data.Quiz.Description)
+		}
+	}
+
+	answers, err := a.drill(data.Quiz.Questions)
+	if err != nil {
+		return err
+	}
+
+	input := make([]map[string]any, len(answers))
+	for i, answer := range answers {
+		input[i] = map[string]any{"questionID": answer.questionID, "userAnswer": answer.answer}
+	}
+
+	var result struct {
+		SubmitAttempt struct {
+			Attempt        attempt `json:"attempt"`
+			Score          int     `json:"score"`
+			TotalQuestions int     `json:"totalQuestions"`
+			CorrectCount   int     `json:"correctCount"`
+		} `json:"submitAttempt"`
+	}
+	err = a.client.Do(ctx, `mutation QuizlogSubmitAttempt($input: SubmitAttemptInput!) {
+		submitAttempt(input: $input) { score totalQuestions correctCount attempt { id quizID startedAt score totalQuestions } }
+	}`, map[string]any{"input": map[string]any{"quizID": data.Quiz.ID, "answers": input}}, &result)
+	if err != nil {
+		return err
+	}
+
+	if a.json {
+		return a.printJSON(result.SubmitAttempt)
+	}
+
+	r := result.SubmitAttempt
+	fmt.Fprintf(a.out, "\nScore: %d%% (%d/%d correct)\n", r.Score, r.CorrectCount, r.TotalQuestions)
+	return nil
+}
+
+func runReview(ctx context.Context, a *app, args []string) error {
+	var data struct {
+		WrongQuestions []question `json:"wrongQuestions"`
+	}
+	err := a.client.Do(ctx, `query QuizlogReview { wrongQuestions { `+questionFields+` } }`, nil, &data)
+	if err != nil {
+		return err
+	}
+
+	if len(data.WrongQuestions) == 0 {
+		if a.json {
+			return a.printJSON(reviewSummary{})
+		}
+		fmt.Fprintln(a.out, "Nothing to review.")
+		return nil
+	}
+
+	if !a.json {
+		fmt.Fprintf(a.out, "Review queue: %d questions\n", len(data.WrongQuestions))
+	}
+
+	answers, err := a.drill(data.WrongQuestions)
+	if err != nil {
+		return err
+	}
+
+	summary := reviewSummary{Total: len(answers)}
+	for _, answer := range answers {
+		if answer.correct {
+			summary.Correct++
+		}
+	}
+
+	if a.json {
+		return a.printJSON(summary)
+	}
+
+	fmt.Fprintf(a.out, "\nReviewed %d questions, %d correct\n", summary.Total, summary.Correct)
+	return nil
+}
+
+type reviewSummary struct {
+	Total   int `json:"total"`
+	Correct int `json:"correct"`
+}
+
+func runImport(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: quizlog import FILE")
+	}
+
+	content, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		ImportQuestions []question `json:"importQuestions"`
+	}
+	err = a.client.Do(ctx, `mutation QuizlogImport($data: String!) {
+		importQuestions(data: $data) { `+questionFields+` }
+	}`, map[string]any{"data": string(content)}, &data)
+	if err != nil {
+		return err
+	}
+
+	if a.json {
+		return a.printJSON(data.ImportQuestions)
+	}
+
+	fmt.Fprintf(a.out, "Imported %d questions\n", len(data.ImportQuestions))
+	return a.printQuestions(data.ImportQuestions)
+}
+
+func runExport(ctx context.Context, a *app, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	quizID := flags.String("quiz", "", "only export the questions of this quiz")
+	output := flags.String("o", "", "write to this file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	variables := map[string]any{}
+	if *quizID != "" {
+		variables["quizID"] = *quizID
+	}
+
+	var data struct {
+		ExportQuestions string `json:"exportQuestions"`
+	}
+	err := a.client.Do(ctx, `mutation QuizlogExport($quizID: ID) { exportQuestions(quizID: $quizID) }`, variables, &data)
+	if err != nil {
+		return err
+	}
+
+	// The export is JSON already, indent it to keep files diffable
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, []byte(data.ExportQuestions), "", "  "); err != nil {
+		return fmt.Errorf("unexpected export format: %w", err)
+	}
+	indented.WriteByte('\n')
+
+	if *output == "" {
+		_, err := indented.WriteTo(a.out)
+		return err
+	}
+
+	if err := os.WriteFile(*output, indented.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if !a.json {
+		fmt.Fprintf(a.out, "Exported questions to %s\n", *output)
+	}
+	return nil
+}
+
+func runStats(ctx context.Context, a *app, args []string) error {
+	var data struct {
+		Statistics struct {
+			TotalAttempts int     `json:"totalAttempts"`
+			AverageScore  float64 `json:"averageScore"`
+			CategoryStats []struct {
+				TagName        string  `json:"tagName"`
+				CorrectRate    float64 `json:"correctRate"`
+				TotalQuestions int     `json:"totalQuestions"`
+			} `json:"categoryStats"`
+			RecentAttempts []attempt `json:"recentAttempts"`
+		} `json:"statistics"`
+	}
+	err := a.client.Do(ctx, `query QuizlogStatistics {
+		statistics {
+			totalAttempts averageScore
+			categoryStats { tagName correctRate totalQuestions }
+			recentAttempts { id quizID startedAt score totalQuestions }
+		}
+	}`, nil, &data)
+	if err != nil {
+		return err
+	}
+
+	stats := data.Statistics
+	if a.json {
+		return a.printJSON(stats)
+	}
+
+	fmt.Fprintf(a.out, "Attempts: %d\nAverage score: %.1f%%\n\n", stats.TotalAttempts, stats.AverageScore)
+
+	categories := make([][]string, len(stats.CategoryStats))
+	for i, c := range stats.CategoryStats {
+		categories[i] = []string{c.TagName, fmt.Sprintf("%.1f%%", c.CorrectRate), fmt.Sprint(c.TotalQuestions)}
+	}
+	if err := a.printTable([]string{"TAG", "CORRECT", "QUESTIONS"}, categories); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.out)
+
+	attempts := make([][]string, len(stats.RecentAttempts))
+	for i, at := range stats.RecentAttempts {
+		attempts[i] = []string{at.ID, at.QuizID, at.StartedAt.Local().Format("2006-01-02 15:04"), fmt.Sprintf("%d%%", at.Score), fmt.Sprint(at.TotalQuestions)}
+	}
+	return a.printTable([]string{"ATTEMPT", "QUIZ", "STARTED", "SCORE", "QUESTIONS"}, attempts)
+}
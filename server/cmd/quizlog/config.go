@@ -0,0 +1,68 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultEndpoint is the GraphQL endpoint of a server started locally
+const defaultEndpoint = "http://localhost:8080/query"
+
+// Config holds the connection settings stored in the config file
+type Config struct {
+	Endpoint string `json:"endpoint"`
+	Token    string `json:"token,omitempty"`
+}
+
+// defaultConfigPath returns the config file location, which QUIZLOG_CONFIG overrides
+func defaultConfigPath() string {
+	if path := os.Getenv("QUIZLOG_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "quizlog.json"
+	}
+	return filepath.Join(dir, "quizlog", "config.json")
+}
+
+// LoadConfig reads the config file, falling back to the local endpoint when it doesn't exist
+func LoadConfig(path string) (*Config, error) {
+	cfg := &Config{Endpoint: defaultEndpoint}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = defaultEndpoint
+	}
+
+	return cfg, nil
+}
+
+// Save writes the config file, readable by the current user only since it holds the token
+func (c *Config) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
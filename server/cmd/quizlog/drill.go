@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// drillAnswer is the answer given to one question and whether it matched
+type drillAnswer struct {
+	questionID string
+	answer     string
+	correct    bool
+}
+
+// drill asks each question in turn, telling right after each answer whether it was correct
+func (a *app) drill(questions []question) ([]drillAnswer, error) {
+	answers := make([]drillAnswer, 0, len(questions))
+
+	for i, q := range questions {
+		fmt.Fprintf(a.prompt, "\n[%d/%d] %s\n", i+1, len(questions), q.Content)
+
+		answer, err := a.ask(q)
+		if err != nil {
+			return nil, err
+		}
+
+		// The server grades the same way, by exact match, though it may give partial credit for an order.
+		// Matching and cloze questions are not sent with their answers, so only the server can grade them,
+		// and only the server parses numbers to tell whether they are within the tolerance.
+		correct := answer == q.CorrectAnswer
+		switch {
+		case q.Type == "MATCHING", q.Type == "CLOZE":
+			correct = false
+			fmt.Fprintf(a.prompt, "Answer recorded, %s questions are graded by the server\n", strings.ToLower(q.Type))
+		case correct:
+			fmt.Fprintln(a.prompt, "Correct!")
+		case q.Type == "NUMERIC":
+			fmt.Fprintf(a.prompt, "Answer recorded, the server checks it is close enough to: %s\n", q.CorrectAnswer)
+		default:
+			fmt.Fprintf(a.prompt, "Wrong, the answer is: %s\n", formatAnswer(q, q.CorrectAnswer))
+		}
+		if q.Explanation != nil && *q.Explanation != "" {
+			fmt.Fprintf(a.prompt, "  %s\n", *q.Explanation)
+		}
+
+		answers = append(answers, drillAnswer{questionID: q.ID, answer: answer, correct: correct})
+	}
+
+	return answers, nil
+}
+
+// ask prompts until the answer fits the question type, returning it as the server stores answers
+func (a *app) ask(q question) (string, error) {
+	if q.Type == "CLOZE" && q.Cloze != nil {
+		return a.askBlanks(q.Cloze.Blanks)
+	}
+
+	switch q.Type {
+	case "MULTIPLE_CHOICE", "ORDERING":
+		for i, option := range q.Options {
+			fmt.Fprintf(a.prompt, "  %d) %s\n", i+1, option)
+		}
+	case "TRUE_FALSE":
+		fmt.Fprintln(a.prompt, "  (true/false)")
+	case "NUMERIC":
+		if q.Numeric != nil && len(q.Numeric.Units) > 0 {
+			fmt.Fprintf(a.prompt, "  (a number, optionally in %s)\n", strings.Join(q.Numeric.Units, " or "))
+		} else {
+			fmt.Fprintln(a.prompt, "  (a number)")
+		}
+	case "MATCHING":
+		if q.Matching != nil {
+			for i, prompt := range q.Matching.Prompts {
+				fmt.Fprintf(a.prompt, "  %d) %s\n", i+1, prompt)
+			}
+			for i, answer := range q.Matching.Answers {
+				fmt.Fprintf(a.prompt, "  %c) %s\n", 'a'+i, answer)
+			}
+		}
+	}
+
+	for {
+		line, err := a.readLine("> ")
+		if err != nil {
+			return "", err
+		}
+
+		answer, ok := parseAnswer(q, line)
+		if ok {
+			return answer, nil
+		}
+
+		switch q.Type {
+		case "MULTIPLE_CHOICE":
+			fmt.Fprintf(a.prompt, "Enter a number between 1 and %d\n", len(q.Options))
+		case "TRUE_FALSE":
+			fmt.Fprintln(a.prompt, "Enter true or false")
+		case "ORDERING":
+			fmt.Fprintf(a.prompt, "Enter the numbers 1 to %d in order, such as 2 1 3\n", len(q.Options))
+		case "MATCHING":
+			fmt.Fprintln(a.prompt, "Enter a letter for each numbered prompt in turn, such as b a c")
+		default:
+			fmt.Fprintln(a.prompt, "Enter an answer")
+		}
+	}
+}
+
+// askBlanks prompts for each blank of a cloze question in turn, returning what goes in them as a JSON array
+func (a *app) askBlanks(n int) (string, error) {
+	filled := make([]string, n)
+	for i := range filled {
+		for filled[i] == "" {
+			line, err := a.readLine(fmt.Sprintf("{{%d}}> ", i+1))
+			if err != nil {
+				return "", err
+			}
+			filled[i] = line
+		}
+	}
+
+	answer, err := json.Marshal(filled)
+	if err != nil {
+		return "", err
+	}
+	return string(answer), nil
+}
+
+// readLine prompts for a line of input, returning it without surrounding spaces
+func (a *app) readLine(prompt string) (string, error) {
+	fmt.Fprint(a.prompt, prompt)
+	line, err := a.in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return "", errors.New("input closed before the drill finished")
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// parseAnswer maps what was typed to an answer: an option number, true/false, an order, matches or free text
+func parseAnswer(q question, input string) (string, bool) {
+	if input == "" {
+		return "", false
+	}
+
+	switch q.Type {
+	case "MULTIPLE_CHOICE":
+		n, err := strconv.Atoi(input)
+		if err == nil && n >= 1 && n <= len(q.Options) {
+			return q.Options[n-1], true
+		}
+		for _, option := range q.Options {
+			if strings.EqualFold(option, input) {
+				return option, true
+			}
+		}
+		return "", false
+	case "TRUE_FALSE":
+		switch strings.ToLower(input) {
+		case "t", "true", "y", "yes":
+			return "true", true
+		case "f", "false", "n", "no":
+			return "false", true
+		}
+		return "", false
+	case "ORDERING":
+		// Items are numbered from 1 when asked, but the server takes their indexes
+		fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' })
+		if len(fields) != len(q.Options) {
+			return "", false
+		}
+		seen := make(map[int]bool, len(fields))
+		indexes := make([]string, len(fields))
+		for i, field := range fields {
+			n, err := strconv.Atoi(field)
+			if err != nil || n < 1 || n > len(q.Options) || seen[n] {
+				return "", false
+			}
+			seen[n] = true
+			indexes[i] = strconv.Itoa(n - 1)
+		}
+		return strings.Join(indexes, ","), true
+	case "MATCHING":
+		// Answers are lettered from a when asked, but the server takes their indexes
+		if q.Matching == nil {
+			return "", false
+		}
+		fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' })
+		if len(fields) != len(q.Matching.Prompts) {
+			return "", false
+		}
+		indexes := make([]string, len(fields))
+		for i, field := range fields {
+			letter := strings.ToLower(field)
+			if len(letter) != 1 || letter[0] < 'a' || int(letter[0]-'a') >= len(q.Matching.Answers) {
+				return "", false
+			}
+			indexes[i] = strconv.Itoa(int(letter[0] - 'a'))
+		}
+		return strings.Join(indexes, ","), true
+	default:
+		return input, true
+	}
+}
+
+// formatAnswer shows an answer as typed, listing the items of an order rather than their indexes
+func formatAnswer(q question, answer string) string {
+	if q.Type != "ORDERING" {
+		return answer
+	}
+
+	var items []string
+	for _, field := range strings.Split(answer, ",") {
+		index, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || index < 0 || index >= len(q.Options) {
+			return answer
+		}
+		items = append(items, q.Options[index])
+	}
+	return strings.Join(items, ", ")
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestParseAnswer(t *testing.T) {
+	multipleChoice := question{Type: "MULTIPLE_CHOICE", Options: []string{"Paris", "Tokyo"}}
+	trueFalse := question{Type: "TRUE_FALSE"}
+	shortAnswer := question{Type: "SHORT_ANSWER"}
+	ordering := question{Type: "ORDERING", Options: []string{"ACK", "SYN", "SYN-ACK"}}
+	capitals := question{Type: "MATCHING", Matching: &matching{Prompts: []string{"France", "Italy"}, Answers: []string{"Paris", "Rome"}}}
+
+	tests := []struct {
+		name     string
+		question question
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"option number", multipleChoice, "2", "Tokyo", true},
+		{"option text", multipleChoice, "paris", "Paris", true},
+		{"option out of range", multipleChoice, "3", "", false},
+		{"true shorthand", trueFalse, "T", "true", true},
+		{"false word", trueFalse, "no", "false", true},
+		{"not a boolean", trueFalse, "maybe", "", false},
+		{"free text", shortAnswer, "goroutine", "goroutine", true},
+		{"empty", shortAnswer, "", "", false},
+		{"order", ordering, "2 3 1", "1,2,0", true},
+		{"order with commas", ordering, "2, 3,1", "1,2,0", true},
+		{"order with a repeat", ordering, "2 2 1", "", false},
+		{"order missing an item", ordering, "2 3", "", false},
+		{"matches", capitals, "a B", "0,1", true},
+		{"matches with commas", capitals, "b,b", "1,1", true},
+		{"match out of range", capitals, "a c", "", false},
+		{"matches missing a prompt", capitals, "a", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answer, ok := parseAnswer(tt.question, tt.input)
+			if answer != tt.expected || ok != tt.ok {
+				t.Errorf("expected ('%s', %v), got ('%s', %v)", tt.expected, tt.ok, answer, ok)
+			}
+		})
+	}
+}
+
+func TestFormatAnswer(t *testing.T) {
+	ordering := question{Type: "ORDERING", Options: []string{"ACK", "SYN", "SYN-ACK"}}
+
+	if got := formatAnswer(ordering, "1,2,0"); got != "SYN, SYN-ACK, ACK" {
+		t.Errorf("expected the items in order, got '%s'", got)
+	}
+	if got := formatAnswer(question{Type: "SHORT_ANSWER"}, "1,2,0"); got != "1,2,0" {
+		t.Errorf("expected the answer unchanged, got '%s'", got)
+	}
+}
+
+func TestDrill(t *testing.T) {
+	var out strings.Builder
+	a := &app{
+		in:     bufio.NewReader(strings.NewReader("5\n1\nfalse\n9.8 m/s^2\n")),
+		out:    io.Discard,
+		prompt: &out,
+	}
+
+	questions := []question{
+		{ID: "1", Type: "MULTIPLE_CHOICE", Content: "Capital of France?", Options: []string{"Paris", "Tokyo"}, CorrectAnswer: "Paris"},
+		{ID: "2", Type: "TRUE_FALSE", Content: "Go has classes.", CorrectAnswer: "true"},
+		{ID: "3", Type: "NUMERIC", Content: "Gravity on Earth?", Numeric: &numeric{Units: []string{"m/s^2"}}, CorrectAnswer: "9.81"},
+	}
+
+	// Execute
+	answers, err := a.drill(questions)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(answers) != 3 {
+		t.Fatalf("expected 3 answers, got %d", len(answers))
+	}
+
+	if answers[0].answer != "Paris" || !answers[0].correct {
+		t.Errorf("expected correct answer 'Paris', got '%s' (correct: %v)", answers[0].answer, answers[0].correct)
+	}
+
+	if answers[1].answer != "false" || answers[1].correct {
+		t.Errorf("expected wrong answer 'false', got '%s' (correct: %v)", answers[1].answer, answers[1].correct)
+	}
+
+	if answers[2].answer != "9.8 m/s^2" || answers[2].correct {
+		t.Errorf("expected the number left for the server to grade, got '%s' (correct: %v)", answers[2].answer, answers[2].correct)
+	}
+
+	// The out-of-range choice is asked again, the wrong answer is corrected and the units are listed
+	for _, expected := range []string{"Enter a number between 1 and 2", "Wrong, the answer is: true", "optionally in m/s^2", "close enough to: 9.81"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("expected output to contain '%s', got:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestDrill_Cloze(t *testing.T) {
+	var out strings.Builder
+	a := &app{
+		in:     bufio.NewReader(strings.NewReader("Paris\n\n Seine \n")),
+		out:    io.Discard,
+		prompt: &out,
+	}
+
+	questions := []question{
+		{ID: "1", Type: "CLOZE", Content: "{{1}} lies on the {{2}}", Cloze: &cloze{Blanks: 2}},
+	}
+
+	// Execute
+	answers, err := a.drill(questions)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(answers) != 1 || answers[0].answer != `["Paris","Seine"]` || answers[0].correct {
+		t.Fatalf("expected the blanks as a JSON array left for the server to grade, got %+v", answers)
+	}
+
+	// The empty blank is asked again
+	if count := strings.Count(out.String(), "{{2}}> "); count != 2 {
+		t.Errorf("expected the second blank to be asked twice, got %d times:\n%s", count, out.String())
+	}
+	if !strings.Contains(out.String(), "cloze questions are graded by the server") {
+		t.Errorf("expected the answer to be left to the server, got:\n%s", out.String())
+	}
+}
+
+func TestDrill_InputClosed(t *testing.T) {
+	a := &app{
+		in:     bufio.NewReader(strings.NewReader("")),
+		out:    io.Discard,
+		prompt: io.Discard,
+	}
+
+	_, err := a.drill([]question{{ID: "1", Type: "SHORT_ANSWER", Content: "Say hi"}})
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,112 @@
+// Command quizlog drills quiz-log questions from the terminal through the GraphQL API.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"text/tabwriter"
+)
+
+// app carries what every command needs
+type app struct {
+	client     *Client
+	configPath string
+	json       bool
+	in         *bufio.Reader
+	out        io.Writer
+	// prompt receives the interactive drill, kept apart from the results with --json
+	prompt io.Writer
+}
+
+type command struct {
+	args    string
+	summary string
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"config":  {"-endpoint URL [-token TOKEN]", "write the config file", runConfig},
+	"quizzes": {"[-status STATUS]", "list quizzes", runQuizzes},
+	"take":    {"QUIZ_ID", "take a quiz interactively", runTake},
+	"review":  {"", "drill the questions answered wrong", runReview},
+	"import":  {"FILE", "import questions from a JSON file", runImport},
+	"export":  {"[-quiz ID] [-o FILE]", "export questions as JSON", runExport},
+	"stats":   {"", "print statistics", runStats},
+}
+
+func main() {
+	flags := flag.NewFlagSet("quizlog", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath(), "path of the config file")
+	jsonOutput := flags.Bool("json", false, "print machine-readable JSON")
+	flags.Usage = func() { usage(flags) }
+	flags.Parse(os.Args[1:])
+
+	if flags.NArg() == 0 {
+		usage(flags)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "quizlog: unknown command %q\n\n", flags.Arg(0))
+		usage(flags)
+		os.Exit(2)
+	}
+
+	cfg, err := LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "quizlog: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{
+		client:     NewClient(cfg),
+		configPath: *configPath,
+		json:       *jsonOutput,
+		in:         bufio.NewReader(os.Stdin),
+		out:        os.Stdout,
+		prompt:     os.Stdout,
+	}
+	if a.json {
+		a.prompt = os.Stderr
+	}
+
+	if err := cmd.run(ctx, a, flags.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "quizlog: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage(flags *flag.FlagSet) {
+	out := flags.Output()
+	fmt.Fprintln(out, "usage: quizlog [-config FILE] [-json] COMMAND [ARGS]")
+	fmt.Fprintln(out, "\nCommands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s %s\t%s\n", name, commands[name].args, commands[name].summary)
+	}
+	w.Flush()
+
+	fmt.Fprintln(out, "\nFlags:")
+	flags.PrintDefaults()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// printJSON writes v as indented JSON for --json output
+func (a *app) printJSON(v any) error {
+	enc := json.NewEncoder(a.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printTable writes rows aligned in columns under a header
+func (a *app) printTable(header []string, rows [][]string) error {
+	w := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+func (a *app) printQuestions(questions []question) error {
+	rows := make([][]string, len(questions))
+	for i, q := range questions {
+		rows[i] = []string{q.ID, q.Type, q.Difficulty, truncate(q.Content, 60)}
+	}
+	return a.printTable([]string{"ID", "TYPE", "DIFFICULTY", "CONTENT"}, rows)
+}
+
+// truncate shortens s to at most n runes on a single line
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"quiz-log/blob"
+	"quiz-log/db"
+)
+
+// Limits applied unless configured otherwise
+const (
+	// DefaultMaxComplexity is the highest cost a GraphQL operation may have
+	DefaultMaxComplexity = 1000
+	// DefaultMaxDepth is the deepest a GraphQL operation may nest
+	DefaultMaxDepth = 10
+	// DefaultMaxAttachmentSize is the size in bytes up to which files can be attached
+	DefaultMaxAttachmentSize = 10 << 20
+)
+
+// Config holds every setting of the server, read from environment variables
+type Config struct {
+	Port              string
+	PlaygroundEnabled bool
+	AutoMigrate       bool
+	TracesExporter    string
+	Log               LogConfig
+	DB                db.Config
+	HTTP              HTTPConfig
+	GraphQL           GraphQLConfig
+	Attachments       AttachmentsConfig
+}
+
+// LogConfig holds the settings of the structured logger
+type LogConfig struct {
+	Level              string
+	Format             string
+	SlowQueryThreshold time.Duration
+}
+
+// HTTPConfig holds the timeouts of the HTTP server
+type HTTPConfig struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+}
+
+// GraphQLConfig holds the limits applied to GraphQL operations
+type GraphQLConfig struct {
+	MaxComplexity int
+	MaxDepth      int
+	APQCacheSize  int
+	Allowlist     bool
+}
+
+// AttachmentsConfig holds where the files attached to questions are stored and how large they can be
+type AttachmentsConfig struct {
+	Blob            blob.Config
+	MaxSize         int64
+	CleanupInterval time.Duration
+}
+
+// Load reads the configuration from the environment, reporting every invalid variable at once
+func Load() (*Config, error) {
+	var l loader
+
+	cfg := &Config{
+		Port:              l.string("PORT", "8080"),
+		PlaygroundEnabled: l.bool("PLAYGROUND_ENABLED", true),
+		AutoMigrate:       l.bool("AUTO_MIGRATE", false),
+		TracesExporter:    l.string("OTEL_TRACES_EXPORTER", "none"),
+		Log: LogConfig{
+			Level:              l.string("LOG_LEVEL", "info"),
+			Format:             l.string("LOG_FORMAT", "text"),
+			SlowQueryThreshold: l.duration("SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+		},
+		DB: db.Config{
+			Driver:   l.oneOf("DB_DRIVER", db.DriverPostgres, db.DriverPostgres, db.DriverSQLite),
+			Path:     l.string("DB_PATH", "quizlog.db"),
+			Host:     l.string("DB_HOST", "localhost"),
+			Port:     l.int("DB_PORT", 5432),
+			User:     l.string("DB_USER", "postgres"),
+			Password: l.string("DB_PASSWORD", "postgres"),
+			DBName:   l.string("DB_NAME", "quizlog"),
+		},
+		HTTP: HTTPConfig{
+			ReadTimeout:       l.duration("HTTP_READ_TIMEOUT", 15*time.Second),
+			ReadHeaderTimeout: l.duration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+			WriteTimeout:      l.duration("HTTP_WRITE_TIMEOUT", 30*time.Second),
+			IdleTimeout:       l.duration("HTTP_IDLE_TIMEOUT", 60*time.Second),
+			ShutdownTimeout:   l.duration("HTTP_SHUTDOWN_TIMEOUT", 30*time.Second),
+		},
+		GraphQL: GraphQLConfig{
+			MaxComplexity: l.positiveInt("GRAPHQL_MAX_COMPLEXITY", DefaultMaxComplexity),
+			MaxDepth:      l.positiveInt("GRAPHQL_MAX_DEPTH", DefaultMaxDepth),
+			APQCacheSize:  l.positiveInt("GRAPHQL_APQ_CACHE_SIZE", 100),
+			Allowlist:     l.bool("GRAPHQL_ALLOWLIST", false),
+		},
+		Attachments: AttachmentsConfig{
+			Blob: blob.Config{
+				Storage: l.oneOf("ATTACHMENT_STORAGE", blob.StorageFS, blob.StorageFS, blob.StorageS3),
+				Dir:     l.string("ATTACHMENT_DIR", "attachments"),
+				S3: blob.S3Config{
+					Endpoint:  l.string("S3_ENDPOINT", ""),
+					Bucket:    l.string("S3_BUCKET", ""),
+					Region:    l.string("S3_REGION", "us-east-1"),
+					AccessKey: l.string("S3_ACCESS_KEY", ""),
+					SecretKey: l.string("S3_SECRET_KEY", ""),
+					UseSSL:    l.bool("S3_USE_SSL", true),
+				},
+			},
+			MaxSize:         int64(l.positiveInt("ATTACHMENT_MAX_SIZE", DefaultMaxAttachmentSize)),
+			CleanupInterval: l.positiveDuration("ATTACHMENT_CLEANUP_INTERVAL", 10*time.Minute),
+		},
+	}
+
+	if err := errors.Join(l.errs...); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loader reads typed environment variables, collecting the errors of invalid ones
+type loader struct {
+	errs []error
+}
+
+func (l *loader) string(key string, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (l *loader) oneOf(key string, defaultValue string, allowed ...string) string {
+	value := l.string(key, defaultValue)
+	if !slices.Contains(allowed, value) {
+		l.errs = append(l.errs, fmt.Errorf("%s: %q is not one of %s", key, value, strings.Join(allowed, ", ")))
+		return defaultValue
+	}
+	return value
+}
+
+func (l *loader) int(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %q is not an integer", key, value))
+		return defaultValue
+	}
+	return n
+}
+
+func (l *loader) positiveInt(key string, defaultValue int) int {
+	n := l.int(key, defaultValue)
+	if n <= 0 {
+		l.errs = append(l.errs, fmt.Errorf("%s: %d is not positive", key, n))
+		return defaultValue
+	}
+	return n
+}
+
+func (l *loader) bool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %q is not a boolean", key, value))
+		return defaultValue
+	}
+	return b
+}
+
+func (l *loader) duration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		l.errs = append(l.errs, fmt.Errorf("%s: %q is not a duration", key, value))
+		return defaultValue
+	}
+	return d
+}
+
+func (l *loader) positiveDuration(key string, defaultValue time.Duration) time.Duration {
+	d := l.duration(key, defaultValue)
+	if d <= 0 {
+		l.errs = append(l.errs, fmt.Errorf("%s: %s is not positive", key, d))
+		return defaultValue
+	}
+	return d
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoad_Defaults(t *testing.T) {
+	for _, key := range []string{"PORT", "DB_PORT", "PLAYGROUND_ENABLED"} {
+		t.Setenv(key, "")
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Port != "8080" {
+		t.Errorf("expected port '8080', got '%s'", cfg.Port)
+	}
+
+	if cfg.DB.Port != 5432 {
+		t.Errorf("expected DB port 5432, got %d", cfg.DB.Port)
+	}
+
+	if !cfg.PlaygroundEnabled {
+		t.Error("expected playground to be enabled")
+	}
+}
+
+func TestLoad_FromEnv(t *testing.T) {
+	t.Setenv("DB_PORT", "6543")
+	t.Setenv("PLAYGROUND_ENABLED", "false")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "1m")
+	t.Setenv("GRAPHQL_ALLOWLIST", "true")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("ATTACHMENT_STORAGE", "s3")
+	t.Setenv("ATTACHMENT_MAX_SIZE", "1048576")
+	t.Setenv("S3_USE_SSL", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.DB.Port != 6543 {
+		t.Errorf("expected DB port 6543, got %d", cfg.DB.Port)
+	}
+
+	if cfg.PlaygroundEnabled {
+		t.Error("expected playground to be disabled")
+	}
+
+	if cfg.HTTP.WriteTimeout != time.Minute {
+		t.Errorf("expected write timeout 1m, got %s", cfg.HTTP.WriteTimeout)
+	}
+
+	if !cfg.GraphQL.Allowlist {
+		t.Error("expected allowlist to be enabled")
+	}
+
+	if cfg.DB.Driver != "sqlite" {
+		t.Errorf("expected driver 'sqlite', got '%s'", cfg.DB.Driver)
+	}
+
+	if cfg.Attachments.Blob.Storage != "s3" || cfg.Attachments.Blob.S3.UseSSL {
+		t.Errorf("expected S3 storage without SSL, got %+v", cfg.Attachments.Blob)
+	}
+
+	if cfg.Attachments.MaxSize != 1<<20 {
+		t.Errorf("expected attachment max size 1048576, got %d", cfg.Attachments.MaxSize)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Setenv("DB_PORT", "postgres")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "60")
+	t.Setenv("DB_DRIVER", "mysql")
+	t.Setenv("ATTACHMENT_STORAGE", "ftp")
+	t.Setenv("ATTACHMENT_MAX_SIZE", "0")
+	t.Setenv("ATTACHMENT_CLEANUP_INTERVAL", "-1m")
+	t.Setenv("GRAPHQL_MAX_COMPLEXITY", "0")
+	t.Setenv("GRAPHQL_MAX_DEPTH", "-1")
+	t.Setenv("GRAPHQL_APQ_CACHE_SIZE", "0")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	// Every invalid variable is reported
+	for _, key := range []string{"DB_PORT", "HTTP_IDLE_TIMEOUT", "DB_DRIVER", "ATTACHMENT_STORAGE", "ATTACHMENT_MAX_SIZE", "ATTACHMENT_CLEANUP_INTERVAL",
+		"GRAPHQL_MAX_COMPLEXITY", "GRAPHQL_MAX_DEPTH", "GRAPHQL_APQ_CACHE_SIZE"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("expected error to mention %s, got '%v'", key, err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package dataloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"quiz-log/db"
+	"quiz-log/graph/model"
+	"quiz-log/repository"
+)
+
+// batchAttachmentsByQuestionID batches attachments by question IDs
+func batchAttachmentsByQuestionID(attachmentRepo repository.AttachmentRepository) dataloader.BatchFunc[int, []*model.Attachment] {
+	return func(ctx context.Context, questionIDs []int) []*dataloader.Result[[]*model.Attachment] {
+		attachmentsMap, err := attachmentRepo.FindByQuestionIDs(ctx, questionIDs)
+		if err != nil {
+			// Return error for all keys
+			results := make([]*dataloader.Result[[]*model.Attachment], len(questionIDs))
+			for i := range questionIDs {
+				results[i] = &dataloader.Result[[]*model.Attachment]{Error: err}
+			}
+			return results
+		}
+
+		// Create results in the same order as requested keys
+		results := make([]*dataloader.Result[[]*model.Attachment], len(questionIDs))
+		for i, questionID := range questionIDs {
+			dbAttachments := attachmentsMap[questionID]
+			attachments := make([]*model.Attachment, len(dbAttachments))
+			for j, dbA := range dbAttachments {
+				attachments[j] = db.AttachmentToGraphQL(dbA)
+			}
+			results[i] = &dataloader.Result[[]*model.Attachment]{Data: attachments}
+		}
+		return results
+	}
+}
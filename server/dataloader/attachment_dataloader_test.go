@@ -0,0 +1,72 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"quiz-log/models"
+	"quiz-log/repository/mocks"
+)
+
+func TestBatchAttachmentsByQuestionID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+
+	// Setup test data
+	questionIDs := []int{2, 1}
+	questionID := 1
+	attachmentsMap := map[int][]*models.Attachment{
+		1: {
+			{ID: 5, QuestionID: &questionID, Placement: "CONTENT", Filename: "a.png", ContentType: "image/png", Size: 10, CreatedAt: time.Now()},
+			{ID: 6, QuestionID: &questionID, Placement: "EXPLANATION", Filename: "b.pdf", ContentType: "application/pdf", Size: 20, CreatedAt: time.Now()},
+		},
+	}
+
+	mockRepo.EXPECT().
+		FindByQuestionIDs(gomock.Any(), questionIDs).
+		Return(attachmentsMap, nil)
+
+	// Execute
+	batchFunc := batchAttachmentsByQuestionID(mockRepo)
+	results := batchFunc(context.Background(), questionIDs)
+
+	// Verify results follow the key order, with no attachments for question 2
+	assert.Len(t, results, 2)
+	assert.NoError(t, results[0].Error)
+	assert.Empty(t, results[0].Data)
+	assert.Len(t, results[1].Data, 2)
+	assert.Equal(t, "5", results[1].Data[0].ID)
+	assert.Equal(t, "/attachments/5", results[1].Data[0].URL)
+	assert.Equal(t, "EXPLANATION", string(results[1].Data[1].Placement))
+}
+
+func TestBatchAttachmentsByQuestionID_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+
+	questionIDs := []int{1, 2}
+	expectedErr := errors.New("database error")
+
+	mockRepo.EXPECT().
+		FindByQuestionIDs(gomock.Any(), questionIDs).
+		Return(nil, expectedErr)
+
+	// Execute
+	batchFunc := batchAttachmentsByQuestionID(mockRepo)
+	results := batchFunc(context.Background(), questionIDs)
+
+	// Verify every key gets the error
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, expectedErr, result.Error)
+	}
+}
@@ -0,0 +1,65 @@
+package dataloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"quiz-log/db"
+	"quiz-log/graph/model"
+	"quiz-log/repository"
+)
+
+// batchAnswersByAttemptID batches answers by attempt IDs
+func batchAnswersByAttemptID(attemptRepo repository.AttemptRepository) dataloader.BatchFunc[int, []*model.Answer] {
+	return func(ctx context.Context, attemptIDs []int) []*dataloader.Result[[]*model.Answer] {
+		answersMap, err := attemptRepo.FindAnswersByAttemptIDs(ctx, attemptIDs)
+		if err != nil {
+			// Return error for all keys
+			results := make([]*dataloader.Result[[]*model.Answer], len(attemptIDs))
+			for i := range attemptIDs {
+				results[i] = &dataloader.Result[[]*model.Answer]{Error: err}
+			}
+			return results
+		}
+
+		// Create results in the same order as requested keys
+		results := make([]*dataloader.Result[[]*model.Answer], len(attemptIDs))
+		for i, attemptID := range attemptIDs {
+			dbAnswers := answersMap[attemptID]
+			answers := make([]*model.Answer, len(dbAnswers))
+			for j, dbA := range dbAnswers {
+				answers[j] = db.AnswerToGraphQL(dbA)
+			}
+			results[i] = &dataloader.Result[[]*model.Answer]{Data: answers}
+		}
+		return results
+	}
+}
+
+// batchAttemptsByQuizID batches attempts by quiz IDs
+func batchAttemptsByQuizID(attemptRepo repository.AttemptRepository) dataloader.BatchFunc[int, []*model.Attempt] {
+	return func(ctx context.Context, quizIDs []int) []*dataloader.Result[[]*model.Attempt] {
+		attemptsMap, err := attemptRepo.FindByQuizIDs(ctx, quizIDs)
+		if err != nil {
+			// Return error for all keys
+			results := make([]*dataloader.Result[[]*model.Attempt], len(quizIDs))
+			for i := range quizIDs {
+				results[i] = &dataloader.Result[[]*model.Attempt]{Error: err}
+			}
+			return results
+		}
+
+		// Create results in the same order as requested keys
+		results := make([]*dataloader.Result[[]*model.Attempt], len(quizIDs))
+		for i, quizID := range quizIDs {
+			dbAttempts := attemptsMap[quizID]
+			attempts := make([]*model.Attempt, len(dbAttempts))
+			for j, dbA := range dbAttempts {
+				attempts[j] = db.AttemptToGraphQL(dbA)
+			}
+			results[i] = &dataloader.Result[[]*model.Attempt]{Data: attempts}
+		}
+		return results
+	}
+}
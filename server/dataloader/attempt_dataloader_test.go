@@ -0,0 +1,100 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"quiz-log/models"
+	"quiz-log/repository/mocks"
+)
+
+func TestBatchAnswersByAttemptID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttemptRepository(ctrl)
+
+	attemptIDs := []int{2, 1}
+	answersMap := map[int][]*models.Answer{
+		1: {
+			{ID: 1, AttemptID: intPtr(1), QuestionID: intPtr(5), UserAnswer: "Paris", IsCorrect: true},
+			{ID: 2, AttemptID: intPtr(1), QuestionID: intPtr(6), UserAnswer: "Rome", IsCorrect: false},
+		},
+	}
+
+	mockRepo.EXPECT().
+		FindAnswersByAttemptIDs(gomock.Any(), attemptIDs).
+		Return(answersMap, nil)
+
+	// Execute
+	batchFunc := batchAnswersByAttemptID(mockRepo)
+	results := batchFunc(context.Background(), attemptIDs)
+
+	// Verify results follow the key order, with no answers for attempt 2
+	assert.Len(t, results, 2)
+	assert.NotNil(t, results[0].Data)
+	assert.Empty(t, results[0].Data)
+	assert.Len(t, results[1].Data, 2)
+	assert.Equal(t, "5", results[1].Data[0].QuestionID)
+	assert.False(t, results[1].Data[1].IsCorrect)
+}
+
+func TestBatchAnswersByAttemptID_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttemptRepository(ctrl)
+
+	attemptIDs := []int{1, 2}
+	expectedErr := errors.New("database error")
+
+	mockRepo.EXPECT().
+		FindAnswersByAttemptIDs(gomock.Any(), attemptIDs).
+		Return(nil, expectedErr)
+
+	// Execute
+	batchFunc := batchAnswersByAttemptID(mockRepo)
+	results := batchFunc(context.Background(), attemptIDs)
+
+	// Verify all results have error
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, expectedErr, result.Error)
+	}
+}
+
+func TestBatchAttemptsByQuizID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttemptRepository(ctrl)
+
+	quizIDs := []int{1, 2}
+	now := time.Now()
+	attemptsMap := map[int][]*models.Attempt{
+		2: {
+			{ID: 7, QuizID: intPtr(2), StartedAt: now, Score: 100, TotalQuestions: 3},
+			{ID: 4, QuizID: intPtr(2), StartedAt: now.Add(-time.Hour), Score: 50, TotalQuestions: 3},
+		},
+	}
+
+	mockRepo.EXPECT().
+		FindByQuizIDs(gomock.Any(), quizIDs).
+		Return(attemptsMap, nil)
+
+	// Execute
+	batchFunc := batchAttemptsByQuizID(mockRepo)
+	results := batchFunc(context.Background(), quizIDs)
+
+	// Verify results follow the key order, keeping the order of each quiz
+	assert.Len(t, results, 2)
+	assert.Empty(t, results[0].Data)
+	assert.Len(t, results[1].Data, 2)
+	assert.Equal(t, "7", results[1].Data[0].ID)
+	assert.Equal(t, "4", results[1].Data[1].ID)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"quiz-log/graph/model"
 	"quiz-log/repository"
+	"quiz-log/telemetry"
 	"time"
 
 	"github.com/graph-gophers/dataloader/v7"
@@ -16,31 +17,83 @@ const (
 	loadersKey = ctxKey("dataloaders")
 )
 
+// BatchWait is how long loaders collect keys before fetching them in one batch
+var BatchWait = time.Millisecond
+
 // Loaders holds all dataloaders
 type Loaders struct {
-	QuestionsByQuizID *dataloader.Loader[int, []*model.Question]
-	TagsByQuizID      *dataloader.Loader[int, []*model.Tag]
+	QuestionsByQuizID       *dataloader.Loader[int, []*model.Question]
+	TagsByQuizID            *dataloader.Loader[int, []*model.Tag]
+	TagUsageByID            *dataloader.Loader[int, *repository.TagUsage]
+	TagByID                 *dataloader.Loader[int, *model.Tag]
+	TagsByParentID          *dataloader.Loader[int, []*model.Tag]
+	TagsByQuestionID        *dataloader.Loader[int, []*model.Tag]
+	QuestionByID            *dataloader.Loader[int, *model.Question]
+	AnswersByAttemptID      *dataloader.Loader[int, []*model.Answer]
+	AttemptsByQuizID        *dataloader.Loader[int, []*model.Attempt]
+	AttachmentsByQuestionID *dataloader.Loader[int, []*model.Attachment]
 }
 
 // NewLoaders creates new dataloaders
-func NewLoaders(quizRepo repository.QuizRepository) *Loaders {
+func NewLoaders(quizRepo repository.QuizRepository, questionRepo repository.QuestionRepository, tagRepo repository.TagRepository, attemptRepo repository.AttemptRepository, attachmentRepo repository.AttachmentRepository) *Loaders {
 	return &Loaders{
 		QuestionsByQuizID: dataloader.NewBatchedLoader(
-			batchQuestionsByQuizID(quizRepo),
-			dataloader.WithWait[int, []*model.Question](time.Millisecond),
+			observeBatch("questions_by_quiz_id", batchQuestionsByQuizID(quizRepo)),
+			dataloader.WithWait[int, []*model.Question](BatchWait),
 		),
 		TagsByQuizID: dataloader.NewBatchedLoader(
-			batchTagsByQuizID(quizRepo),
-			dataloader.WithWait[int, []*model.Tag](time.Millisecond),
+			observeBatch("tags_by_quiz_id", batchTagsByQuizID(quizRepo)),
+			dataloader.WithWait[int, []*model.Tag](BatchWait),
+		),
+		TagUsageByID: dataloader.NewBatchedLoader(
+			observeBatch("tag_usage_by_id", batchTagUsageByID(tagRepo)),
+			dataloader.WithWait[int, *repository.TagUsage](BatchWait),
+		),
+		TagByID: dataloader.NewBatchedLoader(
+			observeBatch("tag_by_id", batchTagByID(tagRepo)),
+			dataloader.WithWait[int, *model.Tag](BatchWait),
+		),
+		TagsByParentID: dataloader.NewBatchedLoader(
+			observeBatch("tags_by_parent_id", batchTagsByParentID(tagRepo)),
+			dataloader.WithWait[int, []*model.Tag](BatchWait),
+		),
+		TagsByQuestionID: dataloader.NewBatchedLoader(
+			observeBatch("tags_by_question_id", batchTagsByQuestionID(questionRepo)),
+			dataloader.WithWait[int, []*model.Tag](BatchWait),
+		),
+		QuestionByID: dataloader.NewBatchedLoader(
+			observeBatch("question_by_id", batchQuestionByID(questionRepo)),
+			dataloader.WithWait[int, *model.Question](BatchWait),
 		),
+		AnswersByAttemptID: dataloader.NewBatchedLoader(
+			observeBatch("answers_by_attempt_id", batchAnswersByAttemptID(attemptRepo)),
+			dataloader.WithWait[int, []*model.Answer](BatchWait),
+		),
+		AttemptsByQuizID: dataloader.NewBatchedLoader(
+			observeBatch("attempts_by_quiz_id", batchAttemptsByQuizID(attemptRepo)),
+			dataloader.WithWait[int, []*model.Attempt](BatchWait),
+		),
+		AttachmentsByQuestionID: dataloader.NewBatchedLoader(
+			observeBatch("attachments_by_question_id", batchAttachmentsByQuestionID(attachmentRepo)),
+			dataloader.WithWait[int, []*model.Attachment](BatchWait),
+		),
+	}
+}
+
+// observeBatch records the size of every batch a loader fetches
+func observeBatch[K comparable, V any](name string, batchFn dataloader.BatchFunc[K, V]) dataloader.BatchFunc[K, V] {
+	return func(ctx context.Context, keys []K) []*dataloader.Result[V] {
+		telemetry.ObserveBatch(name, len(keys))
+		return batchFn(ctx, keys)
 	}
 }
 
-// Middleware injects dataloaders into the context
-func Middleware(loaders *Loaders) func(http.Handler) http.Handler {
+// Middleware injects dataloaders into the context. Every request gets new loaders,
+// so their caches never serve data written by an earlier request.
+func Middleware(newLoaders func() *Loaders) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx := context.WithValue(r.Context(), loadersKey, loaders)
+			ctx := context.WithValue(r.Context(), loadersKey, newLoaders())
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -0,0 +1,65 @@
+package dataloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"quiz-log/db"
+	"quiz-log/graph/model"
+	"quiz-log/repository"
+)
+
+// batchTagsByQuestionID batches tags by question IDs
+func batchTagsByQuestionID(questionRepo repository.QuestionRepository) dataloader.BatchFunc[int, []*model.Tag] {
+	return func(ctx context.Context, questionIDs []int) []*dataloader.Result[[]*model.Tag] {
+		tagsMap, err := questionRepo.FindTagsByQuestionIDs(ctx, questionIDs)
+		if err != nil {
+			// Return error for all keys
+			results := make([]*dataloader.Result[[]*model.Tag], len(questionIDs))
+			for i := range questionIDs {
+				results[i] = &dataloader.Result[[]*model.Tag]{Error: err}
+			}
+			return results
+		}
+
+		// Create results in the same order as requested keys
+		results := make([]*dataloader.Result[[]*model.Tag], len(questionIDs))
+		for i, questionID := range questionIDs {
+			dbTags := tagsMap[questionID]
+			tags := make([]*model.Tag, len(dbTags))
+			for j, dbT := range dbTags {
+				tags[j] = db.TagToGraphQL(dbT)
+			}
+			results[i] = &dataloader.Result[[]*model.Tag]{Data: tags}
+		}
+		return results
+	}
+}
+
+// batchQuestionByID batches questions by their IDs, with nil for missing questions
+func batchQuestionByID(questionRepo repository.QuestionRepository) dataloader.BatchFunc[int, *model.Question] {
+	return func(ctx context.Context, questionIDs []int) []*dataloader.Result[*model.Question] {
+		dbQuestions, err := questionRepo.FindByIDs(ctx, questionIDs)
+		if err != nil {
+			// Return error for all keys
+			results := make([]*dataloader.Result[*model.Question], len(questionIDs))
+			for i := range questionIDs {
+				results[i] = &dataloader.Result[*model.Question]{Error: err}
+			}
+			return results
+		}
+
+		questionsMap := make(map[int]*model.Question, len(dbQuestions))
+		for _, dbQ := range dbQuestions {
+			questionsMap[dbQ.ID] = db.QuestionToGraphQL(dbQ)
+		}
+
+		// Create results in the same order as requested keys
+		results := make([]*dataloader.Result[*model.Question], len(questionIDs))
+		for i, questionID := range questionIDs {
+			results[i] = &dataloader.Result[*model.Question]{Data: questionsMap[questionID]}
+		}
+		return results
+	}
+}
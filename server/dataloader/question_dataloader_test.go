@@ -0,0 +1,95 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"quiz-log/models"
+	"quiz-log/repository/mocks"
+)
+
+func TestBatchTagsByQuestionID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+
+	questionIDs := []int{2, 1, 3}
+	tagsMap := map[int][]*models.Tag{
+		1: {{ID: 10, Name: "go"}},
+		2: {{ID: 20, Name: "rust"}, {ID: 30, Name: "systems"}},
+	}
+
+	mockRepo.EXPECT().
+		FindTagsByQuestionIDs(gomock.Any(), questionIDs).
+		Return(tagsMap, nil)
+
+	// Execute
+	batchFunc := batchTagsByQuestionID(mockRepo)
+	results := batchFunc(context.Background(), questionIDs)
+
+	// Verify results follow the key order, with no tags for question 3
+	assert.Len(t, results, 3)
+	assert.Len(t, results[0].Data, 2)
+	assert.Equal(t, "20", results[0].Data[0].ID)
+	assert.Equal(t, "10", results[1].Data[0].ID)
+	assert.NotNil(t, results[2].Data)
+	assert.Empty(t, results[2].Data)
+}
+
+func TestBatchTagsByQuestionID_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+
+	questionIDs := []int{1, 2}
+	expectedErr := errors.New("database error")
+
+	mockRepo.EXPECT().
+		FindTagsByQuestionIDs(gomock.Any(), questionIDs).
+		Return(nil, expectedErr)
+
+	// Execute
+	batchFunc := batchTagsByQuestionID(mockRepo)
+	results := batchFunc(context.Background(), questionIDs)
+
+	// Verify all results have error
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, expectedErr, result.Error)
+		assert.Nil(t, result.Data)
+	}
+}
+
+func TestBatchQuestionByID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+
+	questionIDs := []int{3, 1, 2}
+
+	// The repository returns questions in ID order and leaves out missing ones
+	mockRepo.EXPECT().
+		FindByIDs(gomock.Any(), questionIDs).
+		Return([]*models.Question{
+			{ID: 1, Type: "TRUE_FALSE", Content: "Is Go compiled?", CorrectAnswer: "true", Difficulty: "EASY"},
+			{ID: 3, Type: "SHORT_ANSWER", Content: "What is a goroutine?", CorrectAnswer: "A lightweight thread", Difficulty: "MEDIUM"},
+		}, nil)
+
+	// Execute
+	batchFunc := batchQuestionByID(mockRepo)
+	results := batchFunc(context.Background(), questionIDs)
+
+	// Verify results follow the key order, with nil for the missing question
+	assert.Len(t, results, 3)
+	assert.Equal(t, "3", results[0].Data.ID)
+	assert.Equal(t, "1", results[1].Data.ID)
+	assert.NoError(t, results[2].Error)
+	assert.Nil(t, results[2].Data)
+}
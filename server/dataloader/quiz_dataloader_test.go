@@ -28,7 +28,7 @@ func TestBatchQuestionsByQuizID_Success(t *testing.T) {
 		1: {
 			{
 				ID:            1,
-				QuizID:        1,
+				QuizID:        intPtr(1),
 				Type:          "MULTIPLE_CHOICE",
 				Content:       "What is Go?",
 				Options:       []string{"A", "B", "C"},
@@ -40,7 +40,7 @@ func TestBatchQuestionsByQuizID_Success(t *testing.T) {
 			},
 			{
 				ID:            2,
-				QuizID:        1,
+				QuizID:        intPtr(1),
 				Type:          "TRUE_FALSE",
 				Content:       "Is Go compiled?",
 				Options:       []string{"true", "false"},
@@ -54,7 +54,7 @@ func TestBatchQuestionsByQuizID_Success(t *testing.T) {
 		2: {
 			{
 				ID:            3,
-				QuizID:        2,
+				QuizID:        intPtr(2),
 				Type:          "SHORT_ANSWER",
 				Content:       "What is a goroutine?",
 				Options:       nil,
@@ -285,10 +285,10 @@ func TestBatchQuestionsByQuizID_OrderPreservation(t *testing.T) {
 	now := time.Now()
 
 	questionsMap := map[int][]*models.Question{
-		1: {{ID: 100, QuizID: 1, Type: "MULTIPLE_CHOICE", Content: "Q1", CorrectAnswer: "A", Difficulty: "EASY", CreatedAt: now, UpdatedAt: now}},
-		2: {{ID: 200, QuizID: 2, Type: "TRUE_FALSE", Content: "Q2", CorrectAnswer: "true", Difficulty: "MEDIUM", CreatedAt: now, UpdatedAt: now}},
-		5: {{ID: 500, QuizID: 5, Type: "SHORT_ANSWER", Content: "Q5", CorrectAnswer: "Answer", Difficulty: "HARD", CreatedAt: now, UpdatedAt: now}},
-		8: {{ID: 800, QuizID: 8, Type: "MULTIPLE_CHOICE", Content: "Q8", CorrectAnswer: "B", Difficulty: "EASY", CreatedAt: now, UpdatedAt: now}},
+		1: {{ID: 100, QuizID: intPtr(1), Type: "MULTIPLE_CHOICE", Content: "Q1", CorrectAnswer: "A", Difficulty: "EASY", CreatedAt: now, UpdatedAt: now}},
+		2: {{ID: 200, QuizID: intPtr(2), Type: "TRUE_FALSE", Content: "Q2", CorrectAnswer: "true", Difficulty: "MEDIUM", CreatedAt: now, UpdatedAt: now}},
+		5: {{ID: 500, QuizID: intPtr(5), Type: "SHORT_ANSWER", Content: "Q5", CorrectAnswer: "Answer", Difficulty: "HARD", CreatedAt: now, UpdatedAt: now}},
+		8: {{ID: 800, QuizID: intPtr(8), Type: "MULTIPLE_CHOICE", Content: "Q8", CorrectAnswer: "B", Difficulty: "EASY", CreatedAt: now, UpdatedAt: now}},
 	}
 
 	mockRepo.EXPECT().
@@ -339,3 +339,8 @@ func TestBatchTagsByQuizID_OrderPreservation(t *testing.T) {
 func strPtr(s string) *string {
 	return &s
 }
+
+// Helper function to create int pointers
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,91 @@
+package dataloader
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+
+	"quiz-log/db"
+	"quiz-log/graph/model"
+	"quiz-log/repository"
+)
+
+// batchTagByID batches tags by their IDs, with nil for missing tags
+func batchTagByID(tagRepo repository.TagRepository) dataloader.BatchFunc[int, *model.Tag] {
+	return func(ctx context.Context, tagIDs []int) []*dataloader.Result[*model.Tag] {
+		dbTags, err := tagRepo.FindByIDs(ctx, tagIDs)
+		if err != nil {
+			// Return error for all keys
+			results := make([]*dataloader.Result[*model.Tag], len(tagIDs))
+			for i := range tagIDs {
+				results[i] = &dataloader.Result[*model.Tag]{Error: err}
+			}
+			return results
+		}
+
+		tagsMap := make(map[int]*model.Tag, len(dbTags))
+		for _, dbT := range dbTags {
+			tagsMap[dbT.ID] = db.TagToGraphQL(dbT)
+		}
+
+		// Create results in the same order as requested keys
+		results := make([]*dataloader.Result[*model.Tag], len(tagIDs))
+		for i, tagID := range tagIDs {
+			results[i] = &dataloader.Result[*model.Tag]{Data: tagsMap[tagID]}
+		}
+		return results
+	}
+}
+
+// batchTagsByParentID batches the direct children of tags by parent IDs
+func batchTagsByParentID(tagRepo repository.TagRepository) dataloader.BatchFunc[int, []*model.Tag] {
+	return func(ctx context.Context, parentIDs []int) []*dataloader.Result[[]*model.Tag] {
+		childrenMap, err := tagRepo.FindByParentIDs(ctx, parentIDs)
+		if err != nil {
+			// Return error for all keys
+			results := make([]*dataloader.Result[[]*model.Tag], len(parentIDs))
+			for i := range parentIDs {
+				results[i] = &dataloader.Result[[]*model.Tag]{Error: err}
+			}
+			return results
+		}
+
+		// Create results in the same order as requested keys
+		results := make([]*dataloader.Result[[]*model.Tag], len(parentIDs))
+		for i, parentID := range parentIDs {
+			dbTags := childrenMap[parentID]
+			children := make([]*model.Tag, len(dbTags))
+			for j, dbT := range dbTags {
+				children[j] = db.TagToGraphQL(dbT)
+			}
+			results[i] = &dataloader.Result[[]*model.Tag]{Data: children}
+		}
+		return results
+	}
+}
+
+// batchTagUsageByID batches tag usage by tag IDs
+func batchTagUsageByID(tagRepo repository.TagRepository) dataloader.BatchFunc[int, *repository.TagUsage] {
+	return func(ctx context.Context, tagIDs []int) []*dataloader.Result[*repository.TagUsage] {
+		usageMap, err := tagRepo.FindUsageByTagIDs(ctx, tagIDs)
+		if err != nil {
+			// Return error for all keys
+			results := make([]*dataloader.Result[*repository.TagUsage], len(tagIDs))
+			for i := range tagIDs {
+				results[i] = &dataloader.Result[*repository.TagUsage]{Error: err}
+			}
+			return results
+		}
+
+		// Create results in the same order as requested keys, unused for missing tags
+		results := make([]*dataloader.Result[*repository.TagUsage], len(tagIDs))
+		for i, tagID := range tagIDs {
+			usage, ok := usageMap[tagID]
+			if !ok {
+				usage = &repository.TagUsage{TagID: tagID}
+			}
+			results[i] = &dataloader.Result[*repository.TagUsage]{Data: usage}
+		}
+		return results
+	}
+}
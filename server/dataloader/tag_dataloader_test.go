@@ -0,0 +1,158 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"quiz-log/models"
+	"quiz-log/repository"
+	"quiz-log/repository/mocks"
+)
+
+func TestBatchTagUsageByID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+
+	// Setup test data
+	tagIDs := []int{2, 1, 3}
+	usedAt := time.Now()
+
+	usageMap := map[int]*repository.TagUsage{
+		1: {TagID: 1, QuizCount: 3, QuestionCount: 5, LastUsedAt: &usedAt},
+		2: {TagID: 2},
+	}
+
+	mockRepo.EXPECT().
+		FindUsageByTagIDs(gomock.Any(), tagIDs).
+		Return(usageMap, nil)
+
+	// Execute
+	batchFunc := batchTagUsageByID(mockRepo)
+	results := batchFunc(context.Background(), tagIDs)
+
+	// Verify results follow the key order
+	assert.Len(t, results, 3)
+	assert.Equal(t, 2, results[0].Data.TagID)
+	assert.Equal(t, 3, results[1].Data.QuizCount)
+	assert.Equal(t, 5, results[1].Data.QuestionCount)
+	assert.Equal(t, &usedAt, results[1].Data.LastUsedAt)
+
+	// Missing tags are reported unused
+	assert.NoError(t, results[2].Error)
+	assert.Equal(t, &repository.TagUsage{TagID: 3}, results[2].Data)
+}
+
+func TestBatchTagUsageByID_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+
+	tagIDs := []int{1, 2}
+	expectedErr := errors.New("database error")
+
+	mockRepo.EXPECT().
+		FindUsageByTagIDs(gomock.Any(), tagIDs).
+		Return(nil, expectedErr)
+
+	// Execute
+	batchFunc := batchTagUsageByID(mockRepo)
+	results := batchFunc(context.Background(), tagIDs)
+
+	// Verify all results have error
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, expectedErr, result.Error)
+		assert.Nil(t, result.Data)
+	}
+}
+
+func TestBatchTagByID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+
+	tagIDs := []int{2, 1, 3}
+	dbTags := []*models.Tag{
+		{ID: 1, Name: "Go"},
+		{ID: 2, Name: "Concurrency", ParentID: intPtr(1)},
+	}
+
+	mockRepo.EXPECT().
+		FindByIDs(gomock.Any(), tagIDs).
+		Return(dbTags, nil)
+
+	// Execute
+	batchFunc := batchTagByID(mockRepo)
+	results := batchFunc(context.Background(), tagIDs)
+
+	// Verify results follow the key order, with nil for the missing tag
+	assert.Len(t, results, 3)
+	assert.Equal(t, "Concurrency", results[0].Data.Name)
+	assert.Equal(t, "1", *results[0].Data.ParentID)
+	assert.Equal(t, "Go", results[1].Data.Name)
+	assert.NoError(t, results[2].Error)
+	assert.Nil(t, results[2].Data)
+}
+
+func TestBatchTagsByParentID_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+
+	parentIDs := []int{2, 1}
+	childrenMap := map[int][]*models.Tag{
+		1: {
+			{ID: 2, Name: "Concurrency", ParentID: intPtr(1)},
+			{ID: 3, Name: "Generics", ParentID: intPtr(1)},
+		},
+	}
+
+	mockRepo.EXPECT().
+		FindByParentIDs(gomock.Any(), parentIDs).
+		Return(childrenMap, nil)
+
+	// Execute
+	batchFunc := batchTagsByParentID(mockRepo)
+	results := batchFunc(context.Background(), parentIDs)
+
+	// Verify results follow the key order, with no children for tag 2
+	assert.Len(t, results, 2)
+	assert.NotNil(t, results[0].Data)
+	assert.Empty(t, results[0].Data)
+	assert.Len(t, results[1].Data, 2)
+	assert.Equal(t, "Generics", results[1].Data[1].Name)
+}
+
+func TestBatchTagsByParentID_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+
+	parentIDs := []int{1, 2}
+	expectedErr := errors.New("database error")
+
+	mockRepo.EXPECT().
+		FindByParentIDs(gomock.Any(), parentIDs).
+		Return(nil, expectedErr)
+
+	// Execute
+	batchFunc := batchTagsByParentID(mockRepo)
+	results := batchFunc(context.Background(), parentIDs)
+
+	// Verify every key gets the error
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.Equal(t, expectedErr, result.Error)
+	}
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"strconv"
 	
+	"quiz-log/grading"
 	"quiz-log/graph/model"
 	"quiz-log/models"
 )
@@ -13,6 +14,8 @@ func QuizToGraphQL(q *models.Quiz) *model.Quiz {
 		ID:          strconv.Itoa(q.ID),
 		Title:       q.Title,
 		Description: q.Description,
+		IsTemplate:  q.IsTemplate,
+		Status:      model.QuizStatus(q.Status),
 		CreatedAt:   q.CreatedAt,
 		UpdatedAt:   q.UpdatedAt,
 	}
@@ -20,17 +23,51 @@ func QuizToGraphQL(q *models.Quiz) *model.Quiz {
 
 // TagToGraphQL converts a db.Tag to a GraphQL model.Tag
 func TagToGraphQL(t *models.Tag) *model.Tag {
+	var parentID *string
+	if t.ParentID != nil {
+		id := strconv.Itoa(*t.ParentID)
+		parentID = &id
+	}
+
 	return &model.Tag{
-		ID:   strconv.Itoa(t.ID),
-		Name: t.Name,
+		ID:       strconv.Itoa(t.ID),
+		Name:     t.Name,
+		ParentID: parentID,
 	}
 }
 
 // QuestionToGraphQL converts a db.Question to a GraphQL model.Question
 func QuestionToGraphQL(q *models.Question) *model.Question {
-	var quizID string
+	var quizID *string
 	if q.QuizID != nil {
-		quizID = strconv.Itoa(*q.QuizID)
+		id := strconv.Itoa(*q.QuizID)
+		quizID = &id
+	}
+
+	// Prompts and answers are listed apart, so quiz takers cannot tell which go together
+	var matching *model.Matching
+	if len(q.Payload.Pairs) > 0 {
+		matching = &model.Matching{Answers: grading.MatchingAnswers(q.Payload.Pairs)}
+		for _, pair := range q.Payload.Pairs {
+			matching.Prompts = append(matching.Prompts, pair.Prompt)
+		}
+	}
+
+	// Numeric questions stored without a tolerance take their value exactly, in no unit
+	var numeric *model.Numeric
+	if n := q.Payload.Numeric; n != nil {
+		numeric = &model.Numeric{Tolerance: n.Tolerance, ToleranceMode: model.ToleranceMode(n.ToleranceMode), Units: n.Units}
+	} else if q.Type == string(model.QuestionTypeNumeric) {
+		numeric = &model.Numeric{ToleranceMode: model.ToleranceModeAbsolute}
+	}
+	if numeric != nil && numeric.Units == nil {
+		numeric.Units = []string{}
+	}
+
+	// Only the number of blanks is shown, not the answers they accept
+	var cloze *model.Cloze
+	if len(q.Payload.Blanks) > 0 {
+		cloze = &model.Cloze{Blanks: len(q.Payload.Blanks)}
 	}
 
 	return &model.Question{
@@ -38,10 +75,17 @@ func QuestionToGraphQL(q *models.Question) *model.Question {
 		QuizID:        quizID,
 		Type:          model.QuestionType(q.Type),
 		Content:       q.Content,
+		ContentFormat: model.ContentFormat(q.ContentFormat),
 		Options:       q.Options,
+		Matching:      matching,
+		Numeric:       numeric,
+		Cloze:         cloze,
 		CorrectAnswer: q.CorrectAnswer,
+		Grading:       model.Grading(q.Grading),
 		Explanation:   q.Explanation,
 		Difficulty:    model.Difficulty(q.Difficulty),
+		Position:      q.Position,
+		Points:        q.Points,
 		CreatedAt:     q.CreatedAt,
 		UpdatedAt:     q.UpdatedAt,
 	}
@@ -80,5 +124,32 @@ func AnswerToGraphQL(a *models.Answer) *model.Answer {
 		QuestionID: questionID,
 		UserAnswer: a.UserAnswer,
 		IsCorrect:  a.IsCorrect,
+		Credit:     a.Credit,
+		Blanks:     a.Blanks,
+	}
+}
+
+// AttachmentURL is the path the attachment with the given ID is served from
+func AttachmentURL(id int) string {
+	return "/attachments/" + strconv.Itoa(id)
+}
+
+// AttachmentToGraphQL converts a db.Attachment to a GraphQL model.Attachment
+func AttachmentToGraphQL(a *models.Attachment) *model.Attachment {
+	var questionID *string
+	if a.QuestionID != nil {
+		id := strconv.Itoa(*a.QuestionID)
+		questionID = &id
+	}
+
+	return &model.Attachment{
+		ID:          strconv.Itoa(a.ID),
+		QuestionID:  questionID,
+		Placement:   model.AttachmentPlacement(a.Placement),
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Size:        int(a.Size),
+		URL:         AttachmentURL(a.ID),
+		CreatedAt:   a.CreatedAt,
 	}
 }
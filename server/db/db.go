@@ -3,29 +3,55 @@ package db
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
 	"github.com/uptrace/bun/driver/pgdriver"
+	_ "modernc.org/sqlite"
+)
+
+// Storage drivers selectable with Config.Driver
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 )
 
 type Config struct {
+	Driver   string
 	Host     string
 	Port     int
 	User     string
 	Password string
 	DBName   string
+	// Path is the SQLite database file, or ":memory:" for a throwaway database
+	Path string
 }
 
 func Connect(cfg Config) (*bun.DB, error) {
-	connStr := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName,
-	)
+	var db *bun.DB
+
+	switch cfg.Driver {
+	case DriverPostgres, "":
+		connStr := fmt.Sprintf(
+			"postgres://%s:%s@%s:%d/%s?sslmode=disable",
+			cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName,
+		)
 
-	sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(connStr)))
+		sqldb := sql.OpenDB(pgdriver.NewConnector(pgdriver.WithDSN(connStr)))
 
-	db := bun.NewDB(sqldb, pgdialect.New())
+		db = bun.NewDB(sqldb, pgdialect.New())
+	case DriverSQLite:
+		sqldb, err := openSQLite(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		db = bun.NewDB(sqldb, sqlitedialect.New())
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", cfg.Driver)
+	}
 
 	if err := db.Ping(); err != nil {
 		return nil, err
@@ -33,3 +59,23 @@ func Connect(cfg Config) (*bun.DB, error) {
 
 	return db, nil
 }
+
+// openSQLite opens a SQLite database enforcing foreign keys, which SQLite leaves off by default
+func openSQLite(path string) (*sql.DB, error) {
+	if path == ":memory:" {
+		sqldb, err := sql.Open("sqlite", "file::memory:?_pragma=foreign_keys(1)")
+		if err != nil {
+			return nil, err
+		}
+
+		// Every connection to :memory: opens a separate database, so keep a single one
+		sqldb.SetMaxOpenConns(1)
+		return sqldb, nil
+	}
+
+	// Transactions take the write lock upfront, as upgrading a read lock fails instead of waiting
+	pragmas := []string{"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(5000)"}
+	dsn := "file:" + path + "?_txlock=immediate&_pragma=" + strings.Join(pragmas, "&_pragma=")
+
+	return sql.Open("sqlite", dsn)
+}
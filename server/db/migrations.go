@@ -0,0 +1,23 @@
+package db
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// Migrations returns the SQL migration files of a driver compiled into the binary
+func Migrations(driver string) fs.FS {
+	dir := "migrations"
+	if driver == DriverSQLite {
+		dir = "migrations/sqlite"
+	}
+
+	sub, err := fs.Sub(migrationFiles, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
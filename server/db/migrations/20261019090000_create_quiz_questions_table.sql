@@ -0,0 +1,38 @@
+-- +migrate Up
+-- Quiz questions junction table (question bank membership)
+CREATE TABLE quiz_questions (
+    quiz_id INTEGER REFERENCES quizzes(id) ON DELETE CASCADE,
+    question_id INTEGER REFERENCES questions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    points INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (quiz_id, question_id)
+);
+
+-- Carry over existing quiz membership, keeping the creation order
+INSERT INTO quiz_questions (quiz_id, question_id, position, points)
+SELECT quiz_id, id, ROW_NUMBER() OVER (PARTITION BY quiz_id ORDER BY created_at, id) - 1, 1
+FROM questions
+WHERE quiz_id IS NOT NULL;
+
+CREATE INDEX idx_quiz_questions_question_id ON quiz_questions(question_id);
+
+DROP INDEX IF EXISTS idx_questions_quiz_id;
+ALTER TABLE questions DROP COLUMN quiz_id;
+
+-- +migrate Down
+ALTER TABLE questions ADD COLUMN quiz_id INTEGER REFERENCES quizzes(id) ON DELETE CASCADE;
+
+-- A question can only belong to one quiz again, so keep its first membership
+UPDATE questions q
+SET quiz_id = qq.quiz_id
+FROM (
+    SELECT DISTINCT ON (question_id) question_id, quiz_id
+    FROM quiz_questions
+    ORDER BY question_id, position, quiz_id
+) qq
+WHERE q.id = qq.question_id;
+
+CREATE INDEX idx_questions_quiz_id ON questions(quiz_id);
+
+DROP INDEX IF EXISTS idx_quiz_questions_question_id;
+DROP TABLE IF EXISTS quiz_questions;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE quizzes ADD COLUMN is_template BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_quizzes_is_template ON quizzes(is_template);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_quizzes_is_template;
+
+ALTER TABLE quizzes DROP COLUMN IF EXISTS is_template;
@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE quizzes ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'DRAFT'
+    CHECK (status IN ('DRAFT', 'PUBLISHED', 'ARCHIVED'));
+
+-- Existing quizzes could already be attempted, so keep them available
+UPDATE quizzes SET status = 'PUBLISHED';
+
+CREATE INDEX idx_quizzes_status ON quizzes(status);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_quizzes_status;
+
+ALTER TABLE quizzes DROP COLUMN IF EXISTS status;
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- Tags form a hierarchy such as Go > Concurrency > Channels
+ALTER TABLE tags ADD COLUMN parent_id INTEGER REFERENCES tags(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tags_parent_id ON tags(parent_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_tags_parent_id;
+
+ALTER TABLE tags DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- How answers that are only partly right are graded, for the question types that allow it
+ALTER TABLE questions ADD COLUMN grading VARCHAR(20) NOT NULL DEFAULT 'EXACT'
+    CHECK (grading IN ('EXACT', 'PARTIAL'));
+
+-- +migrate Down
+ALTER TABLE questions DROP COLUMN IF EXISTS grading;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Structured content of the question types options and a correct answer cannot hold, such as the pairs of MATCHING questions
+ALTER TABLE questions ADD COLUMN payload JSONB;
+
+-- +migrate Down
+ALTER TABLE questions DROP COLUMN IF EXISTS payload;
@@ -0,0 +1,97 @@
+-- +migrate Up
+-- SQLite schema, matching the PostgreSQL migrations in the parent directory.
+-- Question options are stored as a JSON array instead of TEXT[].
+CREATE TABLE quizzes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    is_template BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'DRAFT'
+        CHECK (status IN ('DRAFT', 'PUBLISHED', 'ARCHIVED')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE tags (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(100) UNIQUE NOT NULL
+);
+
+CREATE TABLE quiz_tags (
+    quiz_id INTEGER REFERENCES quizzes(id) ON DELETE CASCADE,
+    tag_id INTEGER REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (quiz_id, tag_id)
+);
+
+CREATE TABLE questions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    type VARCHAR(20) NOT NULL,
+    content TEXT NOT NULL,
+    options TEXT, -- JSON array for multiple choice options
+    correct_answer TEXT NOT NULL,
+    explanation TEXT,
+    difficulty VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE quiz_questions (
+    quiz_id INTEGER REFERENCES quizzes(id) ON DELETE CASCADE,
+    question_id INTEGER REFERENCES questions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    points INTEGER NOT NULL DEFAULT 1,
+    PRIMARY KEY (quiz_id, question_id)
+);
+
+CREATE TABLE question_tags (
+    question_id INTEGER REFERENCES questions(id) ON DELETE CASCADE,
+    tag_id INTEGER REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (question_id, tag_id)
+);
+
+CREATE TABLE attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    quiz_id INTEGER REFERENCES quizzes(id) ON DELETE CASCADE,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP,
+    score INTEGER NOT NULL DEFAULT 0,
+    total_questions INTEGER NOT NULL
+);
+
+CREATE TABLE answers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    attempt_id INTEGER REFERENCES attempts(id) ON DELETE CASCADE,
+    question_id INTEGER REFERENCES questions(id) ON DELETE CASCADE,
+    user_answer TEXT NOT NULL,
+    is_correct BOOLEAN NOT NULL
+);
+
+CREATE TABLE accounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(255) UNIQUE NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    display_name VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_quizzes_is_template ON quizzes(is_template);
+CREATE INDEX idx_quizzes_status ON quizzes(status);
+CREATE INDEX idx_quiz_questions_question_id ON quiz_questions(question_id);
+CREATE INDEX idx_attempts_quiz_id ON attempts(quiz_id);
+CREATE INDEX idx_answers_attempt_id ON answers(attempt_id);
+CREATE INDEX idx_answers_question_id ON answers(question_id);
+CREATE INDEX idx_answers_is_correct ON answers(is_correct);
+
+-- +migrate Down
+DROP TABLE IF EXISTS accounts;
+DROP TABLE IF EXISTS answers;
+DROP TABLE IF EXISTS attempts;
+DROP TABLE IF EXISTS question_tags;
+DROP TABLE IF EXISTS quiz_questions;
+DROP TABLE IF EXISTS questions;
+DROP TABLE IF EXISTS quiz_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS quizzes;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- The format content and explanation are written in, rendered to HTML for clients
+ALTER TABLE questions ADD COLUMN content_format VARCHAR(20) NOT NULL DEFAULT 'PLAIN'
+    CHECK (content_format IN ('PLAIN', 'MARKDOWN'));
+
+-- +migrate Down
+ALTER TABLE questions DROP COLUMN content_format;
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Files attached to the content or explanation of a question, stored as blobs under storage_key.
+-- Deleting a question keeps its attachments with a NULL question_id until their blobs are cleaned up.
+-- The copies of a duplicated question share the blobs of its attachments.
+CREATE TABLE attachments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    question_id INTEGER REFERENCES questions(id) ON DELETE SET NULL,
+    placement VARCHAR(20) NOT NULL DEFAULT 'CONTENT' CHECK (placement IN ('CONTENT', 'EXPLANATION')),
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(200) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_attachments_question_id ON attachments(question_id);
+CREATE INDEX idx_attachments_storage_key ON attachments(storage_key);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_attachments_storage_key;
+DROP INDEX IF EXISTS idx_attachments_question_id;
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- The share of the question an answer earned, between 0 and 1 for partially correct answers
+ALTER TABLE answers ADD COLUMN credit REAL NOT NULL DEFAULT 0;
+UPDATE answers SET credit = 1 WHERE is_correct;
+
+-- +migrate Down
+ALTER TABLE answers DROP COLUMN credit;
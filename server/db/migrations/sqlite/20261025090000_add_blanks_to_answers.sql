@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Whether each blank of the answer to a CLOZE question was filled in correctly, in blank order
+ALTER TABLE answers ADD COLUMN blanks TEXT;
+
+-- +migrate Down
+ALTER TABLE answers DROP COLUMN blanks;
@@ -0,0 +1,176 @@
+// Package sqlcount counts the SQL statements sent to a database, so tests can catch
+// operations whose number of queries grows with the size of their result.
+package sqlcount
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+)
+
+// Counter counts statements. It is safe for concurrent use.
+type Counter struct {
+	n atomic.Int64
+}
+
+// Count returns the number of statements run since the last reset
+func (c *Counter) Count() int {
+	return int(c.n.Load())
+}
+
+// Reset sets the count back to zero
+func (c *Counter) Reset() {
+	c.n.Store(0)
+}
+
+// count records a statement unless the driver skipped it, as database/sql then retries it another way
+func (c *Counter) count(err error) {
+	if !errors.Is(err, driver.ErrSkip) {
+		c.n.Add(1)
+	}
+}
+
+// NewConnector returns a connector opening dsn with d, counting the queries and executions
+// of its connections on counter. Transaction control statements are not counted.
+func NewConnector(d driver.Driver, dsn string, counter *Counter) driver.Connector {
+	return &connector{driver: d, dsn: dsn, counter: counter}
+}
+
+type connector struct {
+	driver  driver.Driver
+	dsn     string
+	counter *Counter
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	inner, err := c.driver.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: inner, counter: c.counter}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.driver
+}
+
+// conn counts the statements of a connection. It offers every optional interface,
+// returning driver.ErrSkip where the wrapped connection lacks one.
+type conn struct {
+	driver.Conn
+	counter *Counter
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	result, err := execer.ExecContext(ctx, query, args)
+	c.counter.count(err)
+	return result, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	rows, err := queryer.QueryContext(ctx, query, args)
+	c.counter.count(err)
+	return rows, err
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		inner driver.Stmt
+		err   error
+	)
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		inner, err = preparer.PrepareContext(ctx, query)
+	} else {
+		inner, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: inner, counter: c.counter}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) CheckNamedValue(value *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(value)
+	}
+	return driver.ErrSkip
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// stmt counts every execution of a prepared statement
+type stmt struct {
+	driver.Stmt
+	counter *Counter
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	var (
+		result driver.Result
+		err    error
+	)
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		result, err = s.Stmt.Exec(values(args))
+	}
+	s.counter.count(err)
+	return result, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		rows, err = s.Stmt.Query(values(args))
+	}
+	s.counter.count(err)
+	return rows, err
+}
+
+func values(args []driver.NamedValue) []driver.Value {
+	vals := make([]driver.Value, len(args))
+	for i, arg := range args {
+		vals[i] = arg.Value
+	}
+	return vals
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+
+	"quiz-log/blob"
+	"quiz-log/graph/resolvers"
+	"quiz-log/markup"
+	"quiz-log/pubsub"
+	"quiz-log/repository/memory"
+	"quiz-log/services"
+)
+
+// newDemoResolver wires the services to in-memory repositories seeded with sample quizzes
+func newDemoResolver(ctx context.Context, bus pubsub.Bus, blobs blob.Store, maxAttachmentSize int64) (*resolvers.Resolver, error) {
+	store := memory.NewStore()
+	if err := memory.Seed(ctx, store); err != nil {
+		return nil, err
+	}
+
+	quizService := &services.QuizService{Repo: memory.NewQuizRepository(store)}
+	questionService := &services.QuestionService{Repo: memory.NewQuestionRepository(store)}
+	attemptService := &services.AttemptService{
+		Repo:     memory.NewAttemptRepository(store),
+		QuizRepo: quizService.Repo,
+		Bus:      bus,
+	}
+
+	return &resolvers.Resolver{
+		QuizService:     quizService,
+		QuestionService: questionService,
+		TagService: &services.TagService{
+			Repo:         memory.NewTagRepository(store),
+			QuestionRepo: questionService.Repo,
+		},
+		AttemptService: attemptService,
+		StatisticsService: &services.StatisticsService{
+			Repo:           memory.NewStatisticsRepository(store),
+			AttemptService: attemptService,
+			Bus:            bus,
+		},
+		RoomService: services.NewRoomService(quizService.Repo, attemptService, bus),
+		AttachmentService: &services.AttachmentService{
+			Repo:         memory.NewAttachmentRepository(store),
+			QuestionRepo: questionService.Repo,
+			Blobs:        blobs,
+			MaxSize:      maxAttachmentSize,
+		},
+		Renderer: markup.NewRenderer(markup.DefaultCacheSize),
+	}, nil
+}
+
+// alwaysReady stands in for the database in the readiness check of the demo mode
+type alwaysReady struct{}
+
+func (alwaysReady) PingContext(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,352 @@
+// Package grading scores answers against the correct answer of a question
+package grading
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"quiz-log/graph/model"
+	"quiz-log/models"
+)
+
+// Grade returns the credit an answer earns, from 0 when wrong to 1 when fully correct.
+// Only questions graded PARTIAL earn anything in between.
+func Grade(q *models.Question, answer string) float64 {
+	switch model.QuestionType(q.Type) {
+	case model.QuestionTypeOrdering:
+		return gradeOrdering(q, answer)
+	case model.QuestionTypeMatching:
+		return gradeMatching(q, answer)
+	case model.QuestionTypeNumeric:
+		return gradeNumeric(q, answer)
+	case model.QuestionTypeCloze:
+		return gradeCloze(q, answer)
+	default:
+		if answer == q.CorrectAnswer {
+			return 1
+		}
+		return 0
+	}
+}
+
+// gradeOrdering compares the order given with the correct one. Under PARTIAL grading the credit
+// falls with the Kendall tau distance, the share of item pairs put the wrong way around.
+func gradeOrdering(q *models.Question, answer string) float64 {
+	correct, err := ParseOrder(q.CorrectAnswer, len(q.Options))
+	if err != nil {
+		return 0
+	}
+	given, err := ParseOrder(answer, len(q.Options))
+	if err != nil {
+		return 0
+	}
+
+	distance := KendallTauDistance(given, correct)
+	if distance == 0 {
+		return 1
+	}
+	if model.Grading(q.Grading) != model.GradingPartial {
+		return 0
+	}
+
+	n := len(correct)
+	pairs := n * (n - 1) / 2
+	return 1 - float64(distance)/float64(pairs)
+}
+
+// gradeMatching checks the answer picked for each prompt. Under PARTIAL grading every pair
+// matched earns its share of the credit.
+func gradeMatching(q *models.Question, answer string) float64 {
+	pairs := q.Payload.Pairs
+	answers := MatchingAnswers(pairs)
+
+	fields := strings.Split(answer, ",")
+	if len(pairs) == 0 || len(fields) != len(pairs) {
+		return 0
+	}
+
+	matched := 0
+	for i, field := range fields {
+		// A prompt left blank is not matched
+		index, err := strconv.Atoi(strings.TrimSpace(field))
+		if err == nil && index >= 0 && index < len(answers) && answers[index] == pairs[i].Answer {
+			matched++
+		}
+	}
+
+	if matched == len(pairs) {
+		return 1
+	}
+	if model.Grading(q.Grading) != model.GradingPartial {
+		return 0
+	}
+	return float64(matched) / float64(len(pairs))
+}
+
+// gradeNumeric checks the value given is within the tolerance of the correct one,
+// in one of the accepted units if any is given
+func gradeNumeric(q *models.Question, answer string) float64 {
+	correct, err := ParseNumber(q.CorrectAnswer)
+	if err != nil {
+		return 0
+	}
+
+	numeric := q.Payload.Numeric
+	if numeric == nil {
+		numeric = &models.NumericAnswer{ToleranceMode: string(model.ToleranceModeAbsolute)}
+	}
+
+	value, err := ParseNumber(trimUnit(normalizeNumber(answer), numeric.Units))
+	if err != nil {
+		return 0
+	}
+
+	allowed := numeric.Tolerance
+	if model.ToleranceMode(numeric.ToleranceMode) == model.ToleranceModeRelative {
+		allowed *= math.Abs(correct)
+	}
+	// Leave room for the rounding of decimal values, so that 1.05 is within 0.05 of 1
+	allowed += numericSlack * math.Max(1, math.Abs(correct))
+
+	if math.Abs(value-correct) <= allowed {
+		return 1
+	}
+	return 0
+}
+
+// numericSlack is the rounding error forgiven when comparing numeric answers, relative to the correct value
+const numericSlack = 1e-9
+
+// trimUnit strips the longest accepted unit the answer ends with, leaving the value
+func trimUnit(answer string, units []string) string {
+	answer = strings.TrimSpace(answer)
+
+	longest := ""
+	for _, unit := range units {
+		unit = normalizeNumber(strings.TrimSpace(unit))
+		if len(unit) > len(longest) && strings.HasSuffix(answer, unit) {
+			longest = unit
+		}
+	}
+	return strings.TrimSpace(strings.TrimSuffix(answer, longest))
+}
+
+var (
+	// powerOfTen matches scientific notation written out, such as 6.02 × 10^23
+	powerOfTen = regexp.MustCompile(`^(.+?)\s*[x*]\s*10\^\s*([+-]?\d+)$`)
+	// groupedDigits matches the integer part of a number split into groups of three digits
+	groupedDigits = regexp.MustCompile(`^([+-]?\d{1,3})((?:[,_ ]\d{3})+)((?:\.\d*)?(?:e[+-]?\d+)?)$`)
+	// plainNumber matches the numbers left for strconv to parse, ruling out hexadecimal, Inf and NaN
+	plainNumber = regexp.MustCompile(`^[+-]?(?:\d+\.?\d*|\.\d+)(?:e[+-]?\d+)?$`)
+)
+
+// ParseNumber parses a number the way people type it: with full-width digits, commas, underscores
+// or spaces between groups of three digits, and in scientific notation such as 6.02e23 or 6.02×10^23.
+// A comma is never taken for a decimal point.
+func ParseNumber(s string) (float64, error) {
+	number := strings.ToLower(strings.TrimSpace(normalizeNumber(s)))
+
+	exponent := ""
+	if m := powerOfTen.FindStringSubmatch(number); m != nil {
+		number, exponent = m[1], "e"+m[2]
+	}
+
+	if m := groupedDigits.FindStringSubmatch(number); m != nil {
+		number = m[1] + strings.Map(func(r rune) rune {
+			if r == ',' || r == '_' || r == ' ' {
+				return -1
+			}
+			return r
+		}, m[2]) + m[3]
+	}
+	number += exponent
+
+	if !plainNumber.MatchString(number) {
+		return 0, fmt.Errorf("%q is not a number", strings.TrimSpace(s))
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is out of range", strings.TrimSpace(s))
+	}
+	return value, nil
+}
+
+// normalizeNumber narrows full-width characters to their ASCII forms and folds
+// the spaces, minus and multiplication signs found in typeset numbers
+func normalizeNumber(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '\uFF01' && r <= '\uFF5E':
+			return r - 0xFEE0
+		case r == '\u3000', r == '\u00A0', r == '\u2009', r == '\u202F':
+			return ' '
+		case r == '\u2212':
+			return '-'
+		case r == '×', r == '·':
+			return '*'
+		}
+		return r
+	}, s)
+}
+
+// gradeCloze checks each blank filled in. Under PARTIAL grading every blank right earns its share of the credit.
+func gradeCloze(q *models.Question, answer string) float64 {
+	blanks := GradeBlanks(q, answer)
+	if len(blanks) == 0 {
+		return 0
+	}
+
+	right := 0
+	for _, ok := range blanks {
+		if ok {
+			right++
+		}
+	}
+
+	if right == len(blanks) {
+		return 1
+	}
+	if model.Grading(q.Grading) != model.GradingPartial {
+		return 0
+	}
+	return float64(right) / float64(len(blanks))
+}
+
+// GradeBlanks tells, for each blank of a CLOZE question in order, whether the answer fills it in
+// with one of the answers it accepts. The answer to a CLOZE question is a JSON array of what goes
+// in each blank, such as ["Paris", "1889"]. It returns nil for the other question types.
+func GradeBlanks(q *models.Question, answer string) models.Blanks {
+	if model.QuestionType(q.Type) != model.QuestionTypeCloze {
+		return nil
+	}
+
+	results := make(models.Blanks, len(q.Payload.Blanks))
+
+	// An answer that is not an array fills in nothing, and blanks past its end are left empty
+	var filled []string
+	if err := json.Unmarshal([]byte(answer), &filled); err != nil {
+		return results
+	}
+	for i, blank := range q.Payload.Blanks {
+		if i < len(filled) {
+			results[i] = blankAccepts(blank, filled[i])
+		}
+	}
+	return results
+}
+
+// blankAccepts compares what was filled in a blank with each answer it accepts, the way its strategy says.
+// Surrounding spaces never count.
+func blankAccepts(blank models.ClozeBlank, filled string) bool {
+	filled = strings.TrimSpace(filled)
+	if filled == "" {
+		return false
+	}
+
+	for _, accepted := range blank.Accepted {
+		accepted = strings.TrimSpace(accepted)
+		switch model.BlankStrategy(blank.Strategy) {
+		case model.BlankStrategyIgnoreCase:
+			if strings.EqualFold(filled, accepted) {
+				return true
+			}
+		case model.BlankStrategyNumeric:
+			want, err := ParseNumber(accepted)
+			if err != nil {
+				continue
+			}
+			got, err := ParseNumber(filled)
+			if err != nil {
+				return false
+			}
+			if math.Abs(got-want) <= numericSlack*math.Max(1, math.Abs(want)) {
+				return true
+			}
+		default:
+			if filled == accepted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// clozeMarker matches a blank marker in the content of a CLOZE question, such as {{1}}
+var clozeMarker = regexp.MustCompile(`\{\{\s*(\d+)\s*\}\}`)
+
+// ClozeMarkers lists the numbers of the blank markers in the content of a CLOZE question, in the order they appear.
+// Blanks are numbered from 1.
+func ClozeMarkers(content string) []int {
+	var numbers []int
+	for _, m := range clozeMarker.FindAllStringSubmatch(content, -1) {
+		// Numbers too large to parse stand for no blank at all
+		n, _ := strconv.Atoi(m[1])
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// MatchingAnswers lists the answers of matching pairs the way quiz takers pick from them: sorted,
+// so their order says nothing about the prompt each one belongs to. The answer to a MATCHING
+// question gives, for each prompt in order, the index of the answer picked in this list,
+// separated by commas, such as "2,0,1".
+func MatchingAnswers(pairs []models.MatchingPair) []string {
+	answers := make([]string, len(pairs))
+	for i, pair := range pairs {
+		answers[i] = pair.Answer
+	}
+	slices.Sort(answers)
+	return answers
+}
+
+// ParseOrder parses the answer to an ORDERING question: the zero-based indexes of its n options
+// separated by commas, in the order they go, such as "2,0,1". Every index must appear exactly once.
+func ParseOrder(s string, n int) ([]int, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) != n {
+		return nil, fmt.Errorf("must list all %d options", n)
+	}
+
+	order := make([]int, n)
+	seen := make([]bool, n)
+	for i, field := range fields {
+		index, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an option index", strings.TrimSpace(field))
+		}
+		if index < 0 || index >= n {
+			return nil, fmt.Errorf("option index %d is out of range", index)
+		}
+		if seen[index] {
+			return nil, fmt.Errorf("option index %d is listed twice", index)
+		}
+		seen[index] = true
+		order[i] = index
+	}
+
+	return order, nil
+}
+
+// KendallTauDistance counts the pairs of items two orders of the same items put the other way around
+func KendallTauDistance(a, b []int) int {
+	// Rank of each item in b
+	rank := make(map[int]int, len(b))
+	for i, item := range b {
+		rank[item] = i
+	}
+
+	distance := 0
+	for i := range a {
+		for j := i + 1; j < len(a); j++ {
+			if rank[a[i]] > rank[a[j]] {
+				distance++
+			}
+		}
+	}
+	return distance
+}
@@ -0,0 +1,367 @@
+package grading
+
+import (
+	"slices"
+	"testing"
+
+	"quiz-log/models"
+)
+
+func TestGrade(t *testing.T) {
+	handshake := []string{"ACK", "SYN", "SYN-ACK", "DATA"}
+	// Quiz takers pick from the answers sorted: Berlin, Paris, Rome
+	capitals := models.Payload{Pairs: []models.MatchingPair{
+		{Prompt: "France", Answer: "Paris"},
+		{Prompt: "Italy", Answer: "Rome"},
+		{Prompt: "Germany", Answer: "Berlin"},
+	}}
+	gravity := models.Payload{Numeric: &models.NumericAnswer{Tolerance: 0.05, ToleranceMode: "ABSOLUTE", Units: []string{"m/s²", "m/s^2"}}}
+	avogadro := models.Payload{Numeric: &models.NumericAnswer{Tolerance: 0.01, ToleranceMode: "RELATIVE"}}
+	// The {{1}} tower was finished in {{2}}
+	eiffel := models.Payload{Blanks: []models.ClozeBlank{
+		{Accepted: []string{"Eiffel", "eiffel"}, Strategy: "EXACT"},
+		{Accepted: []string{"1889"}, Strategy: "NUMERIC"},
+	}}
+
+	tests := []struct {
+		name     string
+		question models.Question
+		answer   string
+		expected float64
+	}{
+		{
+			name:     "multiple choice match",
+			question: models.Question{Type: "MULTIPLE_CHOICE", Options: []string{"Paris", "Lyon"}, CorrectAnswer: "Paris"},
+			answer:   "Paris",
+			expected: 1,
+		},
+		{
+			name:     "short answer mismatch",
+			question: models.Question{Type: "SHORT_ANSWER", CorrectAnswer: "Oslo"},
+			answer:   "oslo",
+			expected: 0,
+		},
+		{
+			name:     "ordering in order",
+			question: models.Question{Type: "ORDERING", Options: handshake, CorrectAnswer: "1,2,0,3", Grading: "EXACT"},
+			answer:   " 1, 2,0 ,3",
+			expected: 1,
+		},
+		{
+			name:     "ordering with a swap graded exactly",
+			question: models.Question{Type: "ORDERING", Options: handshake, CorrectAnswer: "1,2,0,3", Grading: "EXACT"},
+			answer:   "2,1,0,3",
+			expected: 0,
+		},
+		{
+			name:     "ordering with a swap graded partially",
+			question: models.Question{Type: "ORDERING", Options: handshake, CorrectAnswer: "1,2,0,3", Grading: "PARTIAL"},
+			answer:   "2,1,0,3",
+			expected: 1 - 1.0/6,
+		},
+		{
+			name:     "ordering reversed graded partially",
+			question: models.Question{Type: "ORDERING", Options: handshake, CorrectAnswer: "1,2,0,3", Grading: "PARTIAL"},
+			answer:   "3,0,2,1",
+			expected: 0,
+		},
+		{
+			name:     "ordering with a missing item",
+			question: models.Question{Type: "ORDERING", Options: handshake, CorrectAnswer: "1,2,0,3", Grading: "PARTIAL"},
+			answer:   "1,2,0",
+			expected: 0,
+		},
+		{
+			name:     "matching all pairs",
+			question: models.Question{Type: "MATCHING", Payload: capitals, Grading: "PARTIAL"},
+			answer:   "1, 2, 0",
+			expected: 1,
+		},
+		{
+			name:     "matching one pair graded exactly",
+			question: models.Question{Type: "MATCHING", Payload: capitals, Grading: "EXACT"},
+			answer:   "1,0,2",
+			expected: 0,
+		},
+		{
+			name:     "matching one pair graded partially",
+			question: models.Question{Type: "MATCHING", Payload: capitals, Grading: "PARTIAL"},
+			answer:   "1,0,2",
+			expected: 1.0 / 3,
+		},
+		{
+			name:     "matching with prompts left blank",
+			question: models.Question{Type: "MATCHING", Payload: capitals, Grading: "PARTIAL"},
+			answer:   "1,,x",
+			expected: 1.0 / 3,
+		},
+		{
+			name:     "matching with too few fields",
+			question: models.Question{Type: "MATCHING", Payload: capitals, Grading: "PARTIAL"},
+			answer:   "1,2",
+			expected: 0,
+		},
+		{
+			name:     "numeric at the edge of an absolute tolerance",
+			question: models.Question{Type: "NUMERIC", Payload: gravity, CorrectAnswer: "9.81"},
+			answer:   "9.86",
+			expected: 1,
+		},
+		{
+			name:     "numeric beyond an absolute tolerance",
+			question: models.Question{Type: "NUMERIC", Payload: gravity, CorrectAnswer: "9.81"},
+			answer:   "9.87",
+			expected: 0,
+		},
+		{
+			name:     "numeric with an accepted unit",
+			question: models.Question{Type: "NUMERIC", Payload: gravity, CorrectAnswer: "9.81"},
+			answer:   "9.8 m/s^2",
+			expected: 1,
+		},
+		{
+			name:     "numeric with another unit",
+			question: models.Question{Type: "NUMERIC", Payload: gravity, CorrectAnswer: "9.81"},
+			answer:   "9.81 ft/s^2",
+			expected: 0,
+		},
+		{
+			name:     "numeric within a relative tolerance",
+			question: models.Question{Type: "NUMERIC", Payload: avogadro, CorrectAnswer: "6.022e23"},
+			answer:   "6 × 10^23",
+			expected: 1,
+		},
+		{
+			name:     "numeric beyond a relative tolerance",
+			question: models.Question{Type: "NUMERIC", Payload: avogadro, CorrectAnswer: "6.022e23"},
+			answer:   "5.9e23",
+			expected: 0,
+		},
+		{
+			name:     "numeric without a tolerance",
+			question: models.Question{Type: "NUMERIC", CorrectAnswer: "1234"},
+			answer:   "１，２３４",
+			expected: 1,
+		},
+		{
+			name:     "cloze all blanks",
+			question: models.Question{Type: "CLOZE", Payload: eiffel, Grading: "PARTIAL"},
+			answer:   `["Eiffel", "1,889"]`,
+			expected: 1,
+		},
+		{
+			name:     "cloze one blank graded partially",
+			question: models.Question{Type: "CLOZE", Payload: eiffel, Grading: "PARTIAL"},
+			answer:   `["EIFFEL", "1889"]`,
+			expected: 0.5,
+		},
+		{
+			name:     "cloze one blank graded exactly",
+			question: models.Question{Type: "CLOZE", Payload: eiffel, Grading: "EXACT"},
+			answer:   `["Eiffel"]`,
+			expected: 0,
+		},
+		{
+			name:     "cloze answer not an array",
+			question: models.Question{Type: "CLOZE", Payload: eiffel, Grading: "PARTIAL"},
+			answer:   "Eiffel, 1889",
+			expected: 0,
+		},
+		{
+			name:     "numeric with a unit when none is accepted",
+			question: models.Question{Type: "NUMERIC", CorrectAnswer: "1234"},
+			answer:   "1234 km",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Execute
+			credit := Grade(&tt.question, tt.answer)
+
+			// Assert
+			if credit != tt.expected {
+				t.Errorf("expected credit %v, got %v", tt.expected, credit)
+			}
+		})
+	}
+}
+
+func TestGradeBlanks(t *testing.T) {
+	question := &models.Question{Type: "CLOZE", Payload: models.Payload{Blanks: []models.ClozeBlank{
+		{Accepted: []string{"Paris"}, Strategy: "EXACT"},
+		{Accepted: []string{"Seine", "La Seine"}, Strategy: "IGNORE_CASE"},
+		{Accepted: []string{"2.1e6"}, Strategy: "NUMERIC"},
+	}}}
+
+	tests := []struct {
+		answer   string
+		expected models.Blanks
+	}{
+		{answer: `["Paris", "la seine", "2,100,000"]`, expected: models.Blanks{true, true, true}},
+		{answer: `[" Paris ", "Loire", "2.1 million"]`, expected: models.Blanks{true, false, false}},
+		{answer: `["paris", "SEINE"]`, expected: models.Blanks{false, true, false}},
+		{answer: `["", "", "", "extra"]`, expected: models.Blanks{false, false, false}},
+		{answer: `{"1": "Paris"}`, expected: models.Blanks{false, false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			// Execute
+			blanks := GradeBlanks(question, tt.answer)
+
+			// Assert
+			if !slices.Equal(blanks, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, blanks)
+			}
+		})
+	}
+
+	if blanks := GradeBlanks(&models.Question{Type: "SHORT_ANSWER", CorrectAnswer: "Paris"}, "Paris"); blanks != nil {
+		t.Errorf("expected no blanks for a short answer, got %v", blanks)
+	}
+}
+
+func TestClozeMarkers(t *testing.T) {
+	// Execute
+	markers := ClozeMarkers("The {{2}} flows through {{ 1 }}, not {1} or {{x}}, then {{2}} again")
+
+	// Assert
+	if expected := []int{2, 1, 2}; !slices.Equal(markers, expected) {
+		t.Errorf("expected %v, got %v", expected, markers)
+	}
+}
+
+func TestMatchingAnswers(t *testing.T) {
+	pairs := []models.MatchingPair{
+		{Prompt: "H2O", Answer: "water"},
+		{Prompt: "NaCl", Answer: "salt"},
+		{Prompt: "CO2", Answer: "carbon dioxide"},
+	}
+
+	// Execute
+	answers := MatchingAnswers(pairs)
+
+	// Assert
+	expected := []string{"carbon dioxide", "salt", "water"}
+	if !slices.Equal(answers, expected) {
+		t.Errorf("expected %v, got %v", expected, answers)
+	}
+	if pairs[0].Answer != "water" {
+		t.Errorf("expected the pairs to be left in order, got %v", pairs)
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		wantErr  bool
+	}{
+		{input: "42", expected: 42},
+		{input: " -0.5 ", expected: -0.5},
+		{input: "+.25", expected: 0.25},
+		{input: "1,234,567.89", expected: 1234567.89},
+		{input: "1 234", expected: 1234},
+		{input: "1_000", expected: 1000},
+		{input: "6.02e23", expected: 6.02e23},
+		{input: "1.5E-3", expected: 0.0015},
+		{input: "6.02 x 10^23", expected: 6.02e23},
+		{input: "3×10^-2", expected: 0.03},
+		{input: "１２．５", expected: 12.5},
+		{input: "−７", expected: -7},
+		{input: "12,34", wantErr: true},
+		{input: "1,2345", wantErr: true},
+		{input: "0x10", wantErr: true},
+		{input: "Inf", wantErr: true},
+		{input: "NaN", wantErr: true},
+		{input: "1e400", wantErr: true},
+		{input: "twelve", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			// Execute
+			value, err := ParseNumber(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if value != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestParseOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		expected []int
+		wantErr  bool
+	}{
+		{input: "2,0,1", n: 3, expected: []int{2, 0, 1}},
+		{input: " 1 , 0 ", n: 2, expected: []int{1, 0}},
+		{input: "0,1", n: 3, wantErr: true},
+		{input: "0,1,3", n: 3, wantErr: true},
+		{input: "0,1,1", n: 3, wantErr: true},
+		{input: "0,a,1", n: 3, wantErr: true},
+		{input: "-1,0", n: 2, wantErr: true},
+		{input: "", n: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			// Execute
+			order, err := ParseOrder(tt.input, tt.n)
+
+			// Assert
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", order)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(order, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, order)
+			}
+		})
+	}
+}
+
+func TestKendallTauDistance(t *testing.T) {
+	correct := []int{0, 1, 2, 3}
+
+	tests := []struct {
+		order    []int
+		expected int
+	}{
+		{order: []int{0, 1, 2, 3}, expected: 0},
+		{order: []int{1, 0, 2, 3}, expected: 1},
+		{order: []int{0, 3, 1, 2}, expected: 2},
+		{order: []int{3, 2, 1, 0}, expected: 6},
+	}
+
+	for _, tt := range tests {
+		// Execute
+		distance := KendallTauDistance(tt.order, correct)
+
+		// Assert
+		if distance != tt.expected {
+			t.Errorf("expected distance %d for %v, got %d", tt.expected, tt.order, distance)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package allowlist
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errOperationNotAllowed = "OPERATION_NOT_ALLOWED"
+
+// operationsJSON maps the name of every operation the web client sends to its text.
+// Regenerate it with `make allowlist` after running the Relay compiler.
+//
+//go:embed operations.json
+var operationsJSON []byte
+
+// Allowlist only lets through operations whose text is known in advance
+type Allowlist struct {
+	queries map[string]bool
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = &Allowlist{}
+
+// New creates an Allowlist of the given operation texts
+func New(queries ...string) *Allowlist {
+	a := &Allowlist{queries: make(map[string]bool, len(queries))}
+	for _, query := range queries {
+		a.queries[query] = true
+	}
+	return a
+}
+
+// RelayOperations returns the text of every operation emitted by the Relay compiler, keyed by name
+func RelayOperations() (map[string]string, error) {
+	var operations map[string]string
+	if err := json.Unmarshal(operationsJSON, &operations); err != nil {
+		return nil, err
+	}
+	return operations, nil
+}
+
+// Relay creates an Allowlist of the operations emitted by the Relay compiler for the web client
+func Relay() (*Allowlist, error) {
+	operations, err := RelayOperations()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([]string, 0, len(operations))
+	for _, query := range operations {
+		queries = append(queries, query)
+	}
+
+	return New(queries...), nil
+}
+
+func (a *Allowlist) ExtensionName() string {
+	return "Allowlist"
+}
+
+func (a *Allowlist) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationParameters rejects unknown operations before they are parsed.
+// It must run after the persisted query extension so that hashed queries are already resolved.
+func (a *Allowlist) MutateOperationParameters(ctx context.Context, request *graphql.RawParams) *gqlerror.Error {
+	if a.queries[request.Query] {
+		return nil
+	}
+
+	err := gqlerror.Errorf("operation is not allowed")
+	errcode.Set(err, errOperationNotAllowed)
+	return err
+}
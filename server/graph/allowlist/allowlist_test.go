@@ -0,0 +1,41 @@
+package allowlist
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+func TestAllowlist_MutateOperationParameters(t *testing.T) {
+	a, err := Relay()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	operations, err := RelayOperations()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(operations) == 0 {
+		t.Fatal("expected Relay operations, got none")
+	}
+
+	// Operations of the web client are accepted
+	for name, query := range operations {
+		if gqlErr := a.MutateOperationParameters(context.Background(), &graphql.RawParams{Query: query}); gqlErr != nil {
+			t.Errorf("expected %s to be allowed, got %v", name, gqlErr)
+		}
+	}
+
+	// Anything else is rejected
+	gqlErr := a.MutateOperationParameters(context.Background(), &graphql.RawParams{Query: "{ quizzes { questions { tags { id } } } }"})
+	if gqlErr == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if gqlErr.Extensions["code"] != errOperationNotAllowed {
+		t.Errorf("expected code '%s', got '%v'", errOperationNotAllowed, gqlErr.Extensions["code"])
+	}
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"quiz-log/graph/model"
+)
+
+// Expected number of items returned by list fields, used to weigh the cost of their selections
+const (
+	largeListSize = 20
+	smallListSize = 5
+)
+
+// NewComplexityRoot returns the cost of fields whose selections are repeated for every returned item
+func NewComplexityRoot() ComplexityRoot {
+	var c ComplexityRoot
+
+	c.Query.Quizzes = func(childComplexity int, status *model.QuizStatus) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Query.QuizTemplates = func(childComplexity int) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Query.Questions = func(childComplexity int, quizID *string) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Query.WrongQuestions = func(childComplexity int) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Query.Attempts = func(childComplexity int, quizID *string) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Query.Tags = func(childComplexity int) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Query.TagSuggestions = func(childComplexity int, prefix string, limit *int) int {
+		return listCost(childComplexity, limitOr(limit, largeListSize))
+	}
+	c.Query.QuestionTagSuggestions = func(childComplexity int, content string, limit *int) int {
+		return listCost(childComplexity, limitOr(limit, largeListSize))
+	}
+
+	c.Mutation.ImportQuestions = func(childComplexity int, data string) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Mutation.BulkUpdateQuestions = func(childComplexity int, ids []string, patch model.BulkQuestionPatch) int {
+		return listCost(childComplexity, len(ids))
+	}
+	c.Mutation.BulkTagQuestions = func(childComplexity int, ids []string, addTagIDs []string, removeTagIDs []string) int {
+		return listCost(childComplexity, len(ids))
+	}
+	c.Mutation.MoveQuestions = func(childComplexity int, ids []string, sourceQuizID *string, targetQuizID string) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Mutation.ReorderQuestions = func(childComplexity int, quizID string, orderedIDs []string) int {
+		return listCost(childComplexity, len(orderedIDs))
+	}
+
+	c.Quiz.Questions = func(childComplexity int) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Quiz.Tags = func(childComplexity int) int {
+		return listCost(childComplexity, smallListSize)
+	}
+	c.Quiz.Attempts = func(childComplexity int) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Question.Tags = func(childComplexity int) int {
+		return listCost(childComplexity, smallListSize)
+	}
+	c.Question.Attachments = func(childComplexity int) int {
+		return listCost(childComplexity, smallListSize)
+	}
+	c.Tag.Children = func(childComplexity int) int {
+		return listCost(childComplexity, smallListSize)
+	}
+	c.Attempt.Answers = func(childComplexity int) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.AttemptResult.WrongQuestions = func(childComplexity int) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Statistics.CategoryStats = func(childComplexity int, rollUp *bool) int {
+		return listCost(childComplexity, largeListSize)
+	}
+	c.Statistics.RecentAttempts = func(childComplexity int) int {
+		return listCost(childComplexity, 10)
+	}
+	c.Room.Leaderboard = func(childComplexity int) int {
+		return listCost(childComplexity, largeListSize)
+	}
+
+	return c
+}
+
+func listCost(childComplexity, size int) int {
+	return 1 + childComplexity*size
+}
+
+// limitOr returns the number of items a limit argument asks for, or size without one
+func limitOr(limit *int, size int) int {
+	if limit == nil || *limit < 0 {
+		return size
+	}
+	return *limit
+}
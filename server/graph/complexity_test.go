@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/vektah/gqlparser/v2"
+
+	"quiz-log/config"
+	"quiz-log/graph/allowlist"
+	"quiz-log/graph/depthlimit"
+)
+
+func TestComplexity_RelayOperationsWithinLimits(t *testing.T) {
+	es := NewExecutableSchema(Config{Complexity: NewComplexityRoot()})
+
+	operations, err := allowlist.RelayOperations()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, query := range operations {
+		t.Run(name, func(t *testing.T) {
+			doc, gqlErr := gqlparser.LoadQuery(es.Schema(), query)
+			if gqlErr != nil {
+				t.Fatalf("unexpected error: %v", gqlErr)
+			}
+
+			op := doc.Operations.ForName(name)
+			cost := complexity.Calculate(context.Background(), es, op, nil)
+
+			if cost > config.DefaultMaxComplexity {
+				t.Errorf("expected complexity at most %d, got %d", config.DefaultMaxComplexity, cost)
+			}
+
+			if depth := depthlimit.Depth(op.SelectionSet); depth > config.DefaultMaxDepth {
+				t.Errorf("expected depth at most %d, got %d", config.DefaultMaxDepth, depth)
+			}
+		})
+	}
+}
+
+func TestComplexity_NestedListsExceedLimit(t *testing.T) {
+	es := NewExecutableSchema(Config{Complexity: NewComplexityRoot()})
+
+	query := `{ quizzes { questions { tags { id name } } } }`
+	doc, gqlErr := gqlparser.LoadQuery(es.Schema(), query)
+	if gqlErr != nil {
+		t.Fatalf("unexpected error: %v", gqlErr)
+	}
+
+	cost := complexity.Calculate(context.Background(), es, doc.Operations[0], map[string]any{})
+	if cost <= config.DefaultMaxComplexity {
+		t.Errorf("expected complexity above %d, got %d", config.DefaultMaxComplexity, cost)
+	}
+}
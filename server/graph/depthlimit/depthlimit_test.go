@@ -0,0 +1,96 @@
+package depthlimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// testSchema nests nodes as deep as an operation asks for
+var testSchema = gqlparser.MustLoadSchema(&ast.Source{Input: `
+	type Query { node: Node }
+	type Node { id: ID! child: Node }
+`})
+
+func TestDepthLimit_MutateOperationContext(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		depth    int
+		rejected bool
+	}{
+		{
+			name:  "at the limit",
+			query: `{ node { child { id } } }`,
+			depth: 3,
+		},
+		{
+			name:     "above the limit",
+			query:    `{ node { child { child { id } } } }`,
+			depth:    4,
+			rejected: true,
+		},
+		{
+			name:  "fragment spread at the limit",
+			query: `{ node { ...childID } } fragment childID on Node { child { id } }`,
+			depth: 3,
+		},
+		{
+			name:     "fragment spread above the limit",
+			query:    `{ node { ...grandchildID } } fragment grandchildID on Node { child { child { id } } }`,
+			depth:    4,
+			rejected: true,
+		},
+		{
+			name:     "nested fragment spreads above the limit",
+			query:    `{ node { ...outer } } fragment outer on Node { child { ...inner } } fragment inner on Node { child { id } }`,
+			depth:    4,
+			rejected: true,
+		},
+		{
+			name:     "inline fragment above the limit",
+			query:    `{ node { ... on Node { child { child { id } } } } }`,
+			depth:    4,
+			rejected: true,
+		},
+		{
+			name:  "introspection not counted",
+			query: `{ __schema { types { fields { type { ofType { name } } } } } }`,
+			depth: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, gqlErr := gqlparser.LoadQuery(testSchema, tt.query)
+			if gqlErr != nil {
+				t.Fatalf("unexpected error: %v", gqlErr)
+			}
+
+			if depth := Depth(doc.Operations[0].SelectionSet); depth != tt.depth {
+				t.Errorf("expected depth %d, got %d", tt.depth, depth)
+			}
+
+			// Execute
+			err := DepthLimit{Max: 3}.MutateOperationContext(context.Background(), &graphql.OperationContext{Doc: doc})
+
+			// Assert
+			if !tt.rejected {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if code := err.Extensions["code"]; code != errDepthLimit {
+				t.Errorf("expected code '%s', got '%v'", errDepthLimit, code)
+			}
+		})
+	}
+}
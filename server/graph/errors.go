@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"quiz-log/apperrors"
+	"quiz-log/requestid"
+	"quiz-log/validation"
+)
+
+// PostgreSQL error codes translated into domain errors
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+)
+
+// ErrorPresenter maps errors to an extensions.code, hiding and logging the details of internal errors
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	// Errors raised by gqlgen itself carry no cause and are already safe to show
+	if gqlErr.Err == nil {
+		return gqlErr
+	}
+
+	var validationErrs validation.Errors
+	if errors.As(err, &validationErrs) {
+		gqlErr.Message = "invalid input"
+		gqlErr.Extensions = map[string]any{
+			"code":   apperrors.CodeInvalidArgument,
+			"fields": []*validation.FieldError(validationErrs),
+		}
+		return gqlErr
+	}
+
+	appErr := apperrors.As(translateDBError(err))
+	gqlErr.Message = appErr.Message
+	gqlErr.Extensions = map[string]any{
+		"code": appErr.Code,
+	}
+
+	if appErr.Code == apperrors.CodeInternal {
+		id := requestid.FromContext(ctx)
+		slog.ErrorContext(ctx, "internal error", "path", gqlErr.Path.String(), "error", err)
+		gqlErr.Extensions["requestId"] = id
+	}
+
+	return gqlErr
+}
+
+// translateDBError turns database errors clients can act on into domain errors
+func translateDBError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound("record not found")
+	}
+
+	var pgErr pgdriver.Error
+	if errors.As(err, &pgErr) {
+		switch pgErr.Field('C') {
+		case pgUniqueViolation:
+			return apperrors.Conflict("record already exists")
+		case pgForeignKeyViolation:
+			return apperrors.InvalidArgument("referenced record does not exist")
+		}
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		switch sqliteErr.Code() {
+		case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+			return apperrors.Conflict("record already exists")
+		case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+			return apperrors.InvalidArgument("referenced record does not exist")
+		}
+	}
+
+	return err
+}
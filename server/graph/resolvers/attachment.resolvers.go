@@ -0,0 +1,23 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+	"quiz-log/graph/model"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// UploadAttachment is the resolver for the uploadAttachment field.
+func (r *mutationResolver) UploadAttachment(ctx context.Context, questionID string, file graphql.Upload, placement *model.AttachmentPlacement) (*model.Attachment, error) {
+	return r.AttachmentService.UploadAttachment(ctx, questionID, file, placement)
+}
+
+// DeleteAttachment is the resolver for the deleteAttachment field.
+func (r *mutationResolver) DeleteAttachment(ctx context.Context, id string) (bool, error) {
+	return r.AttachmentService.DeleteAttachment(ctx, id)
+}
@@ -7,9 +7,30 @@ package resolvers
 
 import (
 	"context"
+	"quiz-log/dataloader"
+	"quiz-log/graph"
 	"quiz-log/graph/model"
+	"strconv"
 )
 
+// Question is the resolver for the question field.
+func (r *answerResolver) Question(ctx context.Context, obj *model.Answer) (*model.Question, error) {
+	questionID, err := strconv.Atoi(obj.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).QuestionByID.Load(ctx, questionID)()
+}
+
+// Answers is the resolver for the answers field.
+func (r *attemptResolver) Answers(ctx context.Context, obj *model.Attempt) ([]*model.Answer, error) {
+	attemptID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).AnswersByAttemptID.Load(ctx, attemptID)()
+}
+
 // SubmitAttempt is the resolver for the submitAttempt field.
 func (r *mutationResolver) SubmitAttempt(ctx context.Context, input model.SubmitAttemptInput) (*model.AttemptResult, error) {
 	return r.AttemptService.SubmitAttempt(ctx, input)
@@ -19,3 +40,17 @@ func (r *mutationResolver) SubmitAttempt(ctx context.Context, input model.Submit
 func (r *queryResolver) Attempts(ctx context.Context, quizID *string) ([]*model.Attempt, error) {
 	return r.AttemptService.GetAttempts(ctx, quizID)
 }
+
+// AttemptSubmitted is the resolver for the attemptSubmitted field.
+func (r *subscriptionResolver) AttemptSubmitted(ctx context.Context, quizID string) (<-chan *model.Attempt, error) {
+	return r.AttemptService.SubscribeAttemptSubmitted(ctx, quizID)
+}
+
+// Answer returns graph.AnswerResolver implementation.
+func (r *Resolver) Answer() graph.AnswerResolver { return &answerResolver{r} }
+
+// Attempt returns graph.AttemptResolver implementation.
+func (r *Resolver) Attempt() graph.AttemptResolver { return &attemptResolver{r} }
+
+type answerResolver struct{ *Resolver }
+type attemptResolver struct{ *Resolver }
@@ -7,7 +7,10 @@ package resolvers
 
 import (
 	"context"
+	"quiz-log/dataloader"
+	"quiz-log/graph"
 	"quiz-log/graph/model"
+	"strconv"
 )
 
 // CreateQuestion is the resolver for the createQuestion field.
@@ -25,6 +28,16 @@ func (r *mutationResolver) DeleteQuestion(ctx context.Context, id string) (bool,
 	return r.QuestionService.DeleteQuestion(ctx, id)
 }
 
+// AddQuestionToQuiz is the resolver for the addQuestionToQuiz field.
+func (r *mutationResolver) AddQuestionToQuiz(ctx context.Context, quizID string, questionID string, points *int) (bool, error) {
+	return r.QuestionService.AddQuestionToQuiz(ctx, quizID, questionID, points)
+}
+
+// RemoveQuestionFromQuiz is the resolver for the removeQuestionFromQuiz field.
+func (r *mutationResolver) RemoveQuestionFromQuiz(ctx context.Context, quizID string, questionID string) (bool, error) {
+	return r.QuestionService.RemoveQuestionFromQuiz(ctx, quizID, questionID)
+}
+
 // ImportQuestions is the resolver for the importQuestions field.
 func (r *mutationResolver) ImportQuestions(ctx context.Context, data string) ([]*model.Question, error) {
 	return r.QuestionService.ImportQuestions(ctx, data)
@@ -35,6 +48,31 @@ func (r *mutationResolver) ExportQuestions(ctx context.Context, quizID *string)
 	return r.QuestionService.ExportQuestions(ctx, quizID)
 }
 
+// BulkUpdateQuestions is the resolver for the bulkUpdateQuestions field.
+func (r *mutationResolver) BulkUpdateQuestions(ctx context.Context, ids []string, patch model.BulkQuestionPatch) ([]*model.Question, error) {
+	return r.QuestionService.BulkUpdateQuestions(ctx, ids, patch)
+}
+
+// BulkDeleteQuestions is the resolver for the bulkDeleteQuestions field.
+func (r *mutationResolver) BulkDeleteQuestions(ctx context.Context, ids []string) (int, error) {
+	return r.QuestionService.BulkDeleteQuestions(ctx, ids)
+}
+
+// BulkTagQuestions is the resolver for the bulkTagQuestions field.
+func (r *mutationResolver) BulkTagQuestions(ctx context.Context, ids []string, addTagIDs []string, removeTagIDs []string) ([]*model.Question, error) {
+	return r.QuestionService.BulkTagQuestions(ctx, ids, addTagIDs, removeTagIDs)
+}
+
+// MoveQuestions is the resolver for the moveQuestions field.
+func (r *mutationResolver) MoveQuestions(ctx context.Context, ids []string, sourceQuizID *string, targetQuizID string) ([]*model.Question, error) {
+	return r.QuestionService.MoveQuestions(ctx, ids, sourceQuizID, targetQuizID)
+}
+
+// ReorderQuestions is the resolver for the reorderQuestions field.
+func (r *mutationResolver) ReorderQuestions(ctx context.Context, quizID string, orderedIDs []string) ([]*model.Question, error) {
+	return r.QuestionService.ReorderQuestions(ctx, quizID, orderedIDs)
+}
+
 // Questions is the resolver for the questions field.
 func (r *queryResolver) Questions(ctx context.Context, quizID *string) ([]*model.Question, error) {
 	return r.QuestionService.GetAllQuestions(ctx, quizID)
@@ -49,3 +87,44 @@ func (r *queryResolver) Question(ctx context.Context, id string) (*model.Questio
 func (r *queryResolver) WrongQuestions(ctx context.Context) ([]*model.Question, error) {
 	return r.QuestionService.GetWrongQuestions(ctx)
 }
+
+// ContentHTML is the resolver for the contentHTML field.
+func (r *questionResolver) ContentHTML(ctx context.Context, obj *model.Question) (string, error) {
+	return r.Renderer.Render(string(obj.ContentFormat), obj.Content)
+}
+
+// ExplanationHTML is the resolver for the explanationHTML field.
+func (r *questionResolver) ExplanationHTML(ctx context.Context, obj *model.Question) (*string, error) {
+	if obj.Explanation == nil {
+		return nil, nil
+	}
+
+	explanation, err := r.Renderer.Render(string(obj.ContentFormat), *obj.Explanation)
+	if err != nil {
+		return nil, err
+	}
+	return &explanation, nil
+}
+
+// Tags is the resolver for the tags field.
+func (r *questionResolver) Tags(ctx context.Context, obj *model.Question) ([]*model.Tag, error) {
+	questionID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).TagsByQuestionID.Load(ctx, questionID)()
+}
+
+// Attachments is the resolver for the attachments field.
+func (r *questionResolver) Attachments(ctx context.Context, obj *model.Question) ([]*model.Attachment, error) {
+	questionID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).AttachmentsByQuestionID.Load(ctx, questionID)()
+}
+
+// Question returns graph.QuestionResolver implementation.
+func (r *Resolver) Question() graph.QuestionResolver { return &questionResolver{r} }
+
+type questionResolver struct{ *Resolver }
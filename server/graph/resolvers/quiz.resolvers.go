@@ -28,9 +28,29 @@ func (r *mutationResolver) DeleteQuiz(ctx context.Context, id string) (bool, err
 	return r.QuizService.DeleteQuiz(ctx, id)
 }
 
+// PublishQuiz is the resolver for the publishQuiz field.
+func (r *mutationResolver) PublishQuiz(ctx context.Context, id string) (*model.Quiz, error) {
+	return r.QuizService.PublishQuiz(ctx, id)
+}
+
+// ArchiveQuiz is the resolver for the archiveQuiz field.
+func (r *mutationResolver) ArchiveQuiz(ctx context.Context, id string) (*model.Quiz, error) {
+	return r.QuizService.ArchiveQuiz(ctx, id)
+}
+
+// DuplicateQuiz is the resolver for the duplicateQuiz field.
+func (r *mutationResolver) DuplicateQuiz(ctx context.Context, id string, title *string, includeTags *bool) (*model.Quiz, error) {
+	return r.QuizService.DuplicateQuiz(ctx, id, title, includeTags)
+}
+
+// InstantiateTemplate is the resolver for the instantiateTemplate field.
+func (r *mutationResolver) InstantiateTemplate(ctx context.Context, id string, title *string, placeholders []*model.PlaceholderInput) (*model.Quiz, error) {
+	return r.QuizService.InstantiateTemplate(ctx, id, title, placeholders)
+}
+
 // Quizzes is the resolver for the quizzes field.
-func (r *queryResolver) Quizzes(ctx context.Context) ([]*model.Quiz, error) {
-	return r.QuizService.GetAllQuizzes(ctx)
+func (r *queryResolver) Quizzes(ctx context.Context, status *model.QuizStatus) ([]*model.Quiz, error) {
+	return r.QuizService.GetAllQuizzes(ctx, status)
 }
 
 // Quiz is the resolver for the quiz field.
@@ -38,6 +58,11 @@ func (r *queryResolver) Quiz(ctx context.Context, id string) (*model.Quiz, error
 	return r.QuizService.GetQuizByID(ctx, id)
 }
 
+// QuizTemplates is the resolver for the quizTemplates field.
+func (r *queryResolver) QuizTemplates(ctx context.Context) ([]*model.Quiz, error) {
+	return r.QuizService.GetQuizTemplates(ctx)
+}
+
 // Questions is the resolver for the questions field on Quiz type.
 func (r *quizResolver) Questions(ctx context.Context, obj *model.Quiz) ([]*model.Question, error) {
 	loaders := dataloader.For(ctx)
@@ -58,6 +83,15 @@ func (r *quizResolver) Tags(ctx context.Context, obj *model.Quiz) ([]*model.Tag,
 	return loaders.TagsByQuizID.Load(ctx, quizID)()
 }
 
+// Attempts is the resolver for the attempts field.
+func (r *quizResolver) Attempts(ctx context.Context, obj *model.Quiz) ([]*model.Attempt, error) {
+	quizID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).AttemptsByQuizID.Load(ctx, quizID)()
+}
+
 // Quiz returns graph.QuizResolver implementation.
 func (r *Resolver) Quiz() graph.QuizResolver { return &quizResolver{r} }
 
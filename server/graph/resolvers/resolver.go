@@ -1,6 +1,7 @@
 package resolvers
 
 import (
+	"quiz-log/markup"
 	"quiz-log/services"
 
 	sq "github.com/Masterminds/squirrel"
@@ -19,6 +20,9 @@ type Resolver struct {
 	TagService        *services.TagService
 	AttemptService    *services.AttemptService
 	StatisticsService *services.StatisticsService
+	RoomService       *services.RoomService
+	AttachmentService *services.AttachmentService
+	Renderer          *markup.Renderer
 }
 
 // PostgreSQL query builder
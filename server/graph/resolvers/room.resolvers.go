@@ -0,0 +1,51 @@
+package resolvers
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+	"quiz-log/graph/model"
+)
+
+// CreateRoom is the resolver for the createRoom field.
+func (r *mutationResolver) CreateRoom(ctx context.Context, quizID string, questionSeconds *int) (*model.CreateRoomPayload, error) {
+	return r.RoomService.CreateRoom(ctx, quizID, questionSeconds)
+}
+
+// JoinRoom is the resolver for the joinRoom field.
+func (r *mutationResolver) JoinRoom(ctx context.Context, code string, name string) (*model.JoinRoomPayload, error) {
+	return r.RoomService.JoinRoom(ctx, code, name)
+}
+
+// StartRoom is the resolver for the startRoom field.
+func (r *mutationResolver) StartRoom(ctx context.Context, code string, hostToken string) (*model.Room, error) {
+	return r.RoomService.StartRoom(ctx, code, hostToken)
+}
+
+// NextRoomQuestion is the resolver for the nextRoomQuestion field.
+func (r *mutationResolver) NextRoomQuestion(ctx context.Context, code string, hostToken string) (*model.Room, error) {
+	return r.RoomService.NextRoomQuestion(ctx, code, hostToken)
+}
+
+// CloseRoom is the resolver for the closeRoom field.
+func (r *mutationResolver) CloseRoom(ctx context.Context, code string, hostToken string) (*model.Room, error) {
+	return r.RoomService.CloseRoom(ctx, code, hostToken)
+}
+
+// SubmitRoomAnswer is the resolver for the submitRoomAnswer field.
+func (r *mutationResolver) SubmitRoomAnswer(ctx context.Context, code string, participantToken string, answer string) (*model.RoomAnswerResult, error) {
+	return r.RoomService.SubmitRoomAnswer(ctx, code, participantToken, answer)
+}
+
+// Room is the resolver for the room field.
+func (r *queryResolver) Room(ctx context.Context, code string) (*model.Room, error) {
+	return r.RoomService.GetRoom(ctx, code)
+}
+
+// RoomUpdated is the resolver for the roomUpdated field.
+func (r *subscriptionResolver) RoomUpdated(ctx context.Context, code string) (<-chan *model.Room, error) {
+	return r.RoomService.SubscribeRoom(ctx, code)
+}
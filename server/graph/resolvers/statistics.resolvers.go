@@ -7,6 +7,7 @@ package resolvers
 
 import (
 	"context"
+	"quiz-log/graph"
 	"quiz-log/graph/model"
 )
 
@@ -14,3 +15,18 @@ import (
 func (r *queryResolver) Statistics(ctx context.Context) (*model.Statistics, error) {
 	return r.StatisticsService.GetStatistics(ctx)
 }
+
+// CategoryStats is the resolver for the categoryStats field.
+func (r *statisticsResolver) CategoryStats(ctx context.Context, obj *model.Statistics, rollUp *bool) ([]*model.CategoryStat, error) {
+	return r.StatisticsService.GetCategoryStats(ctx, rollUp != nil && *rollUp)
+}
+
+// StatisticsUpdated is the resolver for the statisticsUpdated field.
+func (r *subscriptionResolver) StatisticsUpdated(ctx context.Context) (<-chan *model.Statistics, error) {
+	return r.StatisticsService.SubscribeStatistics(ctx)
+}
+
+// Statistics returns graph.StatisticsResolver implementation.
+func (r *Resolver) Statistics() graph.StatisticsResolver { return &statisticsResolver{r} }
+
+type statisticsResolver struct{ *Resolver }
@@ -7,15 +7,119 @@ package resolvers
 
 import (
 	"context"
+	"quiz-log/dataloader"
+	"quiz-log/graph"
 	"quiz-log/graph/model"
+	"strconv"
+	"time"
 )
 
 // CreateTag is the resolver for the createTag field.
-func (r *mutationResolver) CreateTag(ctx context.Context, name string) (*model.Tag, error) {
-	return r.TagService.CreateTag(ctx, name)
+func (r *mutationResolver) CreateTag(ctx context.Context, name string, parentID *string) (*model.Tag, error) {
+	return r.TagService.CreateTag(ctx, name, parentID)
+}
+
+// RenameTag is the resolver for the renameTag field.
+func (r *mutationResolver) RenameTag(ctx context.Context, id string, name string) (*model.Tag, error) {
+	return r.TagService.RenameTag(ctx, id, name)
+}
+
+// MoveTag is the resolver for the moveTag field.
+func (r *mutationResolver) MoveTag(ctx context.Context, id string, parentID *string) (*model.Tag, error) {
+	return r.TagService.MoveTag(ctx, id, parentID)
+}
+
+// DeleteTag is the resolver for the deleteTag field.
+func (r *mutationResolver) DeleteTag(ctx context.Context, id string) (bool, error) {
+	return r.TagService.DeleteTag(ctx, id)
+}
+
+// MergeTags is the resolver for the mergeTags field.
+func (r *mutationResolver) MergeTags(ctx context.Context, sourceIDs []string, targetID string) (*model.Tag, error) {
+	return r.TagService.MergeTags(ctx, sourceIDs, targetID)
 }
 
 // Tags is the resolver for the tags field.
 func (r *queryResolver) Tags(ctx context.Context) ([]*model.Tag, error) {
 	return r.TagService.GetAllTags(ctx)
 }
+
+// Tag is the resolver for the tag field.
+func (r *queryResolver) Tag(ctx context.Context, id string) (*model.Tag, error) {
+	return r.TagService.GetTagByID(ctx, id)
+}
+
+// TagSuggestions is the resolver for the tagSuggestions field.
+func (r *queryResolver) TagSuggestions(ctx context.Context, prefix string, limit *int) ([]*model.Tag, error) {
+	return r.TagService.SuggestTags(ctx, prefix, limit)
+}
+
+// QuestionTagSuggestions is the resolver for the questionTagSuggestions field.
+func (r *queryResolver) QuestionTagSuggestions(ctx context.Context, content string, limit *int) ([]*model.Tag, error) {
+	return r.TagService.SuggestTagsForQuestion(ctx, content, limit)
+}
+
+// Parent is the resolver for the parent field.
+func (r *tagResolver) Parent(ctx context.Context, obj *model.Tag) (*model.Tag, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	parentID, err := strconv.Atoi(*obj.ParentID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).TagByID.Load(ctx, parentID)()
+}
+
+// Children is the resolver for the children field.
+func (r *tagResolver) Children(ctx context.Context, obj *model.Tag) ([]*model.Tag, error) {
+	tagID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return dataloader.For(ctx).TagsByParentID.Load(ctx, tagID)()
+}
+
+// QuizCount is the resolver for the quizCount field.
+func (r *tagResolver) QuizCount(ctx context.Context, obj *model.Tag) (int, error) {
+	tagID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	usage, err := dataloader.For(ctx).TagUsageByID.Load(ctx, tagID)()
+	if err != nil {
+		return 0, err
+	}
+	return usage.QuizCount, nil
+}
+
+// QuestionCount is the resolver for the questionCount field.
+func (r *tagResolver) QuestionCount(ctx context.Context, obj *model.Tag) (int, error) {
+	tagID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	usage, err := dataloader.For(ctx).TagUsageByID.Load(ctx, tagID)()
+	if err != nil {
+		return 0, err
+	}
+	return usage.QuestionCount, nil
+}
+
+// LastUsedAt is the resolver for the lastUsedAt field.
+func (r *tagResolver) LastUsedAt(ctx context.Context, obj *model.Tag) (*time.Time, error) {
+	tagID, err := strconv.Atoi(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := dataloader.For(ctx).TagUsageByID.Load(ctx, tagID)()
+	if err != nil {
+		return nil, err
+	}
+	return usage.LastUsedAt, nil
+}
+
+// Tag returns graph.TagResolver implementation.
+func (r *Resolver) Tag() graph.TagResolver { return &tagResolver{r} }
+
+type tagResolver struct{ *Resolver }
@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// pingTimeout bounds how long a readiness check waits for the database
+const pingTimeout = 2 * time.Second
+
+// Pinger checks that a dependency is reachable, such as *sql.DB or *bun.DB
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// Checker serves the liveness and readiness endpoints of the server
+type Checker struct {
+	DB       Pinger
+	draining atomic.Bool
+}
+
+// NewChecker creates a Checker reporting on the given database
+func NewChecker(db Pinger) *Checker {
+	return &Checker{DB: db}
+}
+
+// Drain makes the server report itself as not ready, so no new traffic is sent while it shuts down
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Healthz reports that the process is alive. It does not depend on the database,
+// so an outage does not get otherwise healthy instances restarted.
+func (c *Checker) Healthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// Readyz reports whether the server can serve requests, which requires a reachable database
+func (c *Checker) Readyz(w http.ResponseWriter, r *http.Request) {
+	if c.draining.Load() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), pingTimeout)
+	defer cancel()
+
+	if err := c.DB.PingContext(ctx); err != nil {
+		slog.WarnContext(ctx, "readiness check failed", "error", err)
+		http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok\n"))
+}
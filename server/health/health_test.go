@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type pingerFunc func(ctx context.Context) error
+
+func (f pingerFunc) PingContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestChecker_Readyz(t *testing.T) {
+	tests := []struct {
+		name     string
+		ping     error
+		drain    bool
+		expected int
+	}{
+		{name: "database reachable", expected: http.StatusOK},
+		{name: "database unreachable", ping: errors.New("connection refused"), expected: http.StatusServiceUnavailable},
+		{name: "draining", drain: true, expected: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(pingerFunc(func(ctx context.Context) error {
+				return tt.ping
+			}))
+			if tt.drain {
+				checker.Drain()
+			}
+
+			rec := httptest.NewRecorder()
+			checker.Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.expected {
+				t.Errorf("expected status %d, got %d", tt.expected, rec.Code)
+			}
+		})
+	}
+}
+
+func TestChecker_Healthz(t *testing.T) {
+	// The database is not consulted for liveness
+	checker := NewChecker(pingerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	rec := httptest.NewRecorder()
+	checker.Healthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+}
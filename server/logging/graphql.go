@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// GraphQL logs one entry per GraphQL operation, or per event of a subscription
+type GraphQL struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = GraphQL{}
+
+func (GraphQL) ExtensionName() string {
+	return "Logging"
+}
+
+func (GraphQL) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	resp := next(ctx)
+	duration := time.Since(start)
+
+	opCtx := graphql.GetOperationContext(ctx)
+	attrs := []any{
+		slog.String("operation", opCtx.OperationName),
+		slog.Duration("duration", duration),
+	}
+
+	level := slog.LevelInfo
+	if opCtx.Operation != nil {
+		attrs = append(attrs, slog.String("type", string(opCtx.Operation.Operation)))
+
+		// Subscriptions log every event, which is too chatty for the default level
+		if opCtx.Operation.Operation == ast.Subscription {
+			level = slog.LevelDebug
+		}
+	}
+
+	if resp != nil && len(resp.Errors) > 0 {
+		codes := make([]string, len(resp.Errors))
+		for i, err := range resp.Errors {
+			codes[i] = errorCode(err.Extensions)
+		}
+		attrs = append(attrs, slog.Any("error_codes", codes))
+	}
+
+	slog.Log(ctx, level, "graphql operation", attrs...)
+
+	return resp
+}
+
+func errorCode(extensions map[string]any) string {
+	if code, ok := extensions["code"]; ok {
+		return fmt.Sprint(code)
+	}
+	return "UNKNOWN"
+}
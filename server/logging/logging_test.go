@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"quiz-log/requestid"
+)
+
+func TestContextHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+	// Execute
+	handler := requestid.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "handled")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/query", nil)
+	req.Header.Set(requestid.Header, "abc123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Assert
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if record["request_id"] != "abc123" {
+		t.Errorf("expected request_id 'abc123', got %v", record["request_id"])
+	}
+
+	if record["component"] != "test" {
+		t.Errorf("expected component 'test', got %v", record["component"])
+	}
+}
+
+func TestSetup_Invalid(t *testing.T) {
+	if err := Setup("xml", "info"); err == nil {
+		t.Error("expected error for invalid format, got nil")
+	}
+
+	if err := Setup("json", "loud"); err == nil {
+		t.Error("expected error for invalid level, got nil")
+	}
+}
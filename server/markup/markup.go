@@ -0,0 +1,107 @@
+// Package markup renders the text of questions into sanitised HTML.
+//
+// Markdown supports GitHub tables, strikethrough and autolinks. Fenced code blocks keep their
+// language hint as a language-* class for the client to highlight, and TeX between $ or $$ is
+// passed through escaped in math elements for the client to typeset. Raw HTML is dropped.
+package markup
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Formats of question text
+const (
+	FormatPlain    = "PLAIN"
+	FormatMarkdown = "MARKDOWN"
+)
+
+// DefaultCacheSize is the number of rendered texts a Renderer keeps unless configured otherwise
+const DefaultCacheSize = 1000
+
+var paragraphBreak = regexp.MustCompile(`\n\s*\n`)
+
+// Renderer renders text to HTML, caching the output by a hash of the format and text.
+// It is safe for concurrent use.
+type Renderer struct {
+	markdown goldmark.Markdown
+	policy   *bluemonday.Policy
+	cache    *lru.Cache[string, string]
+}
+
+// NewRenderer creates a Renderer keeping the output of up to cacheSize texts
+func NewRenderer(cacheSize int) *Renderer {
+	// New only fails for a size below one
+	cache, _ := lru.New[string, string](max(cacheSize, 1))
+
+	policy := bluemonday.UGCPolicy()
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#.-]+$`)).OnElements("code")
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^math math-(inline|display)$`)).OnElements("span", "div")
+
+	return &Renderer{
+		markdown: goldmark.New(goldmark.WithExtensions(
+			extension.Table,
+			extension.Strikethrough,
+			extension.Linkify,
+			Math,
+		)),
+		policy: policy,
+		cache:  cache,
+	}
+}
+
+// Render returns the sanitised HTML of text written in format
+func (r *Renderer) Render(format, text string) (string, error) {
+	key := cacheKey(format, text)
+	if out, ok := r.cache.Get(key); ok {
+		return out, nil
+	}
+
+	var out string
+	switch format {
+	case FormatPlain:
+		out = renderPlain(text)
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := r.markdown.Convert([]byte(text), &buf); err != nil {
+			return "", err
+		}
+		out = r.policy.Sanitize(buf.String())
+	default:
+		return "", fmt.Errorf("unknown text format %q", format)
+	}
+
+	r.cache.Add(key, out)
+	return out, nil
+}
+
+// renderPlain escapes text into paragraphs separated by blank lines, keeping single line breaks
+func renderPlain(text string) string {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, paragraph := range paragraphBreak.Split(text, -1) {
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+func cacheKey(format, text string) string {
+	sum := sha256.Sum256([]byte(format + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
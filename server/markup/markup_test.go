@@ -0,0 +1,125 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		text   string
+		want   string
+	}{
+		{
+			name:   "plain text is escaped",
+			format: FormatPlain,
+			text:   "Is a < b && *c*?\nYes\n\n\nNo",
+			want:   "<p>Is a &lt; b &amp;&amp; *c*?<br>\nYes</p>\n<p>No</p>\n",
+		},
+		{
+			name:   "markdown",
+			format: FormatMarkdown,
+			text:   "Which is **faster**, `map` or ~~slice~~?",
+			want:   "<p>Which is <strong>faster</strong>, <code>map</code> or <del>slice</del>?</p>\n",
+		},
+		{
+			name:   "fenced code keeps its language",
+			format: FormatMarkdown,
+			text:   "```go\nfmt.Println(\"<hi>\")\n```",
+			want:   "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;hi&gt;&#34;)\n</code></pre>\n",
+		},
+		{
+			name:   "inline math",
+			format: FormatMarkdown,
+			text:   "Solve $a_1 * b_1 < 4$ for $a_1$",
+			want:   "<p>Solve <span class=\"math math-inline\">a_1 * b_1 &lt; 4</span> for <span class=\"math math-inline\">a_1</span></p>\n",
+		},
+		{
+			name:   "display math",
+			format: FormatMarkdown,
+			text:   "Compute\n$$\n\\int_0^1 x\\,dx\n$$",
+			want:   "<p>Compute</p>\n<div class=\"math math-display\">\\int_0^1 x\\,dx\n</div>\n",
+		},
+		{
+			name:   "amounts are not math",
+			format: FormatMarkdown,
+			text:   "It costs $5 or $10",
+			want:   "<p>It costs $5 or $10</p>\n",
+		},
+		{
+			name:   "raw HTML and unsafe links are dropped",
+			format: FormatMarkdown,
+			text:   "Hi <img src=x onerror=alert(1)> [x](javascript:alert(1)) [docs](https://go.dev)",
+			want:   "<p>Hi  x <a href=\"https://go.dev\" rel=\"nofollow\">docs</a></p>\n",
+		},
+		{
+			name:   "attached images are kept",
+			format: FormatMarkdown,
+			text:   "![diagram](/attachments/3)",
+			want:   "<p><img src=\"/attachments/3\" alt=\"diagram\"></p>\n",
+		},
+		{
+			name:   "unexpected classes are dropped",
+			format: FormatMarkdown,
+			text:   "```go\" onclick=\"x\nx\n```",
+			want:   "<pre><code>x\n</code></pre>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRenderer(DefaultCacheSize)
+
+			// Execute
+			got, err := r.Render(tt.format, tt.text)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderer_Render_Cached(t *testing.T) {
+	r := NewRenderer(1)
+
+	// Execute
+	first, err := r.Render(FormatMarkdown, "# Title")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plain, err := r.Render(FormatPlain, "# Title")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	if !strings.HasPrefix(first, "<h1") {
+		t.Errorf("expected a heading, got %q", first)
+	}
+	if plain != "<p># Title</p>\n" {
+		t.Errorf("expected the format to be part of the cache key, got %q", plain)
+	}
+	if r.cache.Len() != 1 {
+		t.Errorf("expected the cache to hold 1 entry, got %d", r.cache.Len())
+	}
+}
+
+func TestRenderer_Render_UnknownFormat(t *testing.T) {
+	r := NewRenderer(DefaultCacheSize)
+
+	// Execute
+	_, err := r.Render("HTML", "<b>bold</b>")
+
+	// Assert
+	if err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,216 @@
+package markup
+
+import (
+	"bytes"
+	"html"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// Math is a goldmark extension passing TeX through untouched by Markdown.
+// $x$ and $$x$$ within a paragraph become a span, and $$ on lines of their own fence a display block.
+// A single $ must hug its content and the closing one not be followed by a digit, so that amounts like $5 stay text.
+var Math goldmark.Extender = mathExtension{}
+
+var (
+	KindMathInline = ast.NewNodeKind("MathInline")
+	KindMathBlock  = ast.NewNodeKind("MathBlock")
+)
+
+// MathInline is TeX within a paragraph
+type MathInline struct {
+	ast.BaseInline
+	TeX     []byte
+	Display bool
+}
+
+func (n *MathInline) Kind() ast.NodeKind {
+	return KindMathInline
+}
+
+func (n *MathInline) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"TeX": string(n.TeX)}, nil)
+}
+
+// MathBlock is TeX displayed on lines of its own
+type MathBlock struct {
+	ast.BaseBlock
+	closed bool
+}
+
+func (n *MathBlock) Kind() ast.NodeKind {
+	return KindMathBlock
+}
+
+func (n *MathBlock) IsRaw() bool {
+	return true
+}
+
+func (n *MathBlock) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, nil, nil)
+}
+
+type mathExtension struct{}
+
+func (mathExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		parser.WithBlockParsers(util.Prioritized(mathBlockParser{}, 700)),
+		parser.WithInlineParsers(util.Prioritized(mathInlineParser{}, 500)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(mathRenderer{}, 500)))
+}
+
+var mathFence = []byte("$$")
+
+type mathBlockParser struct{}
+
+func (mathBlockParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (mathBlockParser) Open(parent ast.Node, reader text.Reader, pc parser.Context) (ast.Node, parser.State) {
+	line, segment := reader.PeekLine()
+	pos := pc.BlockOffset()
+	if pos < 0 || !bytes.HasPrefix(line[pos:], mathFence) {
+		return nil, parser.NoChildren
+	}
+
+	rest := util.TrimRightSpace(line[pos+len(mathFence):])
+	node := &MathBlock{}
+	if len(util.TrimLeftSpace(rest)) == 0 {
+		return node, parser.NoChildren
+	}
+
+	// $$x$$ on a line of its own is a whole block, anything else starts a paragraph
+	if len(rest) <= len(mathFence) || !bytes.HasSuffix(rest, mathFence) {
+		return nil, parser.NoChildren
+	}
+	start := segment.Start + pos + len(mathFence)
+	tex := text.NewSegment(start, start+len(rest)-len(mathFence))
+	tex = tex.TrimLeftSpace(reader.Source())
+	node.Lines().Append(tex.TrimRightSpace(reader.Source()))
+	node.closed = true
+	return node, parser.NoChildren
+}
+
+func (mathBlockParser) Continue(node ast.Node, reader text.Reader, pc parser.Context) parser.State {
+	if node.(*MathBlock).closed {
+		return parser.Close
+	}
+
+	line, segment := reader.PeekLine()
+	if bytes.Equal(util.TrimRightSpace(util.TrimLeftSpace(line)), mathFence) {
+		reader.AdvanceToEOL()
+		return parser.Close
+	}
+
+	node.Lines().Append(segment)
+	reader.AdvanceToEOL()
+	return parser.Continue | parser.NoChildren
+}
+
+func (mathBlockParser) Close(node ast.Node, reader text.Reader, pc parser.Context) {}
+
+func (mathBlockParser) CanInterruptParagraph() bool {
+	return true
+}
+
+func (mathBlockParser) CanAcceptIndentedLine() bool {
+	return false
+}
+
+type mathInlineParser struct{}
+
+func (mathInlineParser) Trigger() []byte {
+	return []byte{'$'}
+}
+
+func (mathInlineParser) Parse(parent ast.Node, block text.Reader, pc parser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	delimiter := 1
+	if bytes.HasPrefix(line, mathFence) {
+		delimiter = len(mathFence)
+	}
+
+	rest := line[delimiter:]
+	if len(rest) == 0 || (delimiter == 1 && util.IsSpace(rest[0])) {
+		return nil
+	}
+
+	end := closingDollar(rest, delimiter)
+	if end <= 0 {
+		return nil
+	}
+
+	block.Advance(delimiter + end + delimiter)
+	return &MathInline{TeX: bytes.Clone(rest[:end]), Display: delimiter == len(mathFence)}
+}
+
+// closingDollar returns the position of the delimiter closing TeX at the start of s, or -1.
+// Escaped dollars such as \$ belong to the TeX. The first other $ after a single $ must close it,
+// so that prose like "$5 and $10" is never taken for TeX.
+func closingDollar(s []byte, delimiter int) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] != '$':
+		case delimiter == len(mathFence):
+			if i+1 < len(s) && s[i+1] == '$' {
+				return i
+			}
+		case i > 0 && !util.IsSpace(s[i-1]) && (i+1 == len(s) || !isDigit(s[i+1])):
+			return i
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+type mathRenderer struct{}
+
+func (mathRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(KindMathInline, renderMathInline)
+	reg.Register(KindMathBlock, renderMathBlock)
+}
+
+func renderMathInline(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	n := node.(*MathInline)
+	class := "math math-inline"
+	if n.Display {
+		class = "math math-display"
+	}
+	_, _ = w.WriteString(`<span class="` + class + `">`)
+	_, _ = w.WriteString(html.EscapeString(string(n.TeX)))
+	_, _ = w.WriteString("</span>")
+	return ast.WalkSkipChildren, nil
+}
+
+func renderMathBlock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+
+	_, _ = w.WriteString(`<div class="math math-display">`)
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		segment := lines.At(i)
+		_, _ = w.WriteString(html.EscapeString(string(segment.Value(source))))
+	}
+	_, _ = w.WriteString("</div>\n")
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,250 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTable is the table sql-migrate records applied migrations in, so existing databases carry over
+const DefaultTable = "migrations"
+
+// lockKey identifies the advisory lock serializing migrations across replicas
+const lockKey = 4207795634
+
+// Status reports whether a migration is applied
+type Status struct {
+	ID        string
+	AppliedAt *time.Time
+}
+
+// Migrator applies migrations to a PostgreSQL or SQLite database
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []*Migration
+	Table      string
+	// SkipLock disables the advisory lock, for SQLite which has none and serializes writers itself
+	SkipLock bool
+}
+
+// NewMigrator creates a Migrator recording applied migrations in DefaultTable
+func NewMigrator(db *sql.DB, migrations []*Migration) *Migrator {
+	return &Migrator{
+		DB:         db,
+		Migrations: migrations,
+		Table:      DefaultTable,
+	}
+}
+
+// Up applies pending migrations in order, at most limit of them when limit is positive.
+// It holds an advisory lock meanwhile, so replicas starting together apply each migration once.
+func (m *Migrator) Up(ctx context.Context, limit int) ([]string, error) {
+	var applied []string
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedIDs(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.Migrations {
+			if limit > 0 && len(applied) == limit {
+				break
+			}
+			if _, ok := done[migration.ID]; ok {
+				continue
+			}
+
+			record := func(tx execer) error {
+				_, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (id, applied_at) VALUES ($1, CURRENT_TIMESTAMP)", m.Table), migration.ID)
+				return err
+			}
+			if err := m.apply(ctx, conn, migration.ID, migration.Up, migration.DisableTransactionUp, record); err != nil {
+				return err
+			}
+			applied = append(applied, migration.ID)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the most recently applied migrations, limit of them (one when limit is not positive)
+func (m *Migrator) Down(ctx context.Context, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 1
+	}
+
+	var rolledBack []string
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.appliedIDs(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0 && len(rolledBack) < limit; i-- {
+			migration := m.Migrations[i]
+			if _, ok := done[migration.ID]; !ok {
+				continue
+			}
+
+			record := func(tx execer) error {
+				_, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1", m.Table), migration.ID)
+				return err
+			}
+			if err := m.apply(ctx, conn, migration.ID, migration.Down, migration.DisableTransactionDown, record); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, migration.ID)
+		}
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Status lists every known migration with the time it was applied, if it was
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	done, err := m.appliedIDs(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.Migrations))
+	for i, migration := range m.Migrations {
+		statuses[i] = Status{ID: migration.ID}
+		if appliedAt, ok := done[migration.ID]; ok {
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// apply runs the statements of one migration and records it, in a single transaction unless disabled
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, id string, statements []string, noTransaction bool, record func(execer) error) error {
+	if noTransaction {
+		for _, stmt := range statements {
+			if _, err := conn.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("migration %s: %w", id, err)
+			}
+		}
+		return record(conn)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migration %s: %w", id, err)
+		}
+	}
+
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withLock runs fn on a connection holding the migration advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if m.SkipLock {
+		return fn(conn)
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey)
+
+	return fn(conn)
+}
+
+// appliedIDs returns the applied migrations by ID, creating the table recording them if needed
+func (m *Migrator) appliedIDs(ctx context.Context, conn *sql.Conn) (map[string]time.Time, error) {
+	createTable := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id TEXT NOT NULL PRIMARY KEY, applied_at TIMESTAMP WITH TIME ZONE)", m.Table)
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT id, applied_at FROM %s", m.Table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			id        string
+			appliedAt appliedTime
+		)
+		if err := rows.Scan(&id, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[id] = appliedAt.Time
+	}
+
+	return applied, rows.Err()
+}
+
+// sqliteTimeFormats are the texts SQLite stores times as: CURRENT_TIMESTAMP in UTC,
+// and time.Time.String for times passed as parameters
+var sqliteTimeFormats = []string{"2006-01-02 15:04:05", "2006-01-02 15:04:05.999999999 -0700 MST"}
+
+// appliedTime scans applied_at, which PostgreSQL returns as a time and SQLite as text
+type appliedTime struct {
+	time.Time
+}
+
+func (t *appliedTime) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		t.Time = time.Time{}
+	case time.Time:
+		t.Time = v
+	case string:
+		return t.parse(v)
+	case []byte:
+		return t.parse(string(v))
+	default:
+		return fmt.Errorf("cannot scan %T into applied_at", value)
+	}
+	return nil
+}
+
+func (t *appliedTime) parse(s string) error {
+	// Drop the monotonic clock reading time.Time.String appends
+	text, _, _ := strings.Cut(s, " m=")
+	for _, layout := range sqliteTimeFormats {
+		if parsed, err := time.Parse(layout, text); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid applied_at %q", s)
+}
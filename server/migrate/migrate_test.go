@@ -0,0 +1,144 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMigrator_Up(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer sqlDB.Close()
+
+	migrator := NewMigrator(sqlDB, []*Migration{
+		{ID: "001_init.sql", Up: []string{"CREATE TABLE quizzes (id SERIAL);"}},
+		{ID: "002_tags.sql", Up: []string{"CREATE TABLE tags (id SERIAL);"}},
+	})
+
+	// Expect the lock, then only the pending migration to run in a transaction
+	mock.ExpectExec(`SELECT pg_advisory_lock`).
+		WithArgs(lockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS migrations`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT id, applied_at FROM migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "applied_at"}).AddRow("001_init.sql", time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(`CREATE TABLE tags`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO migrations`).
+		WithArgs("002_tags.sql").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).
+		WithArgs(lockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute
+	applied, err := migrator.Up(context.Background(), 0)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(applied) != 1 || applied[0] != "002_tags.sql" {
+		t.Errorf("expected to apply '002_tags.sql', got %v", applied)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer sqlDB.Close()
+
+	migrator := NewMigrator(sqlDB, []*Migration{
+		{ID: "001_init.sql", Down: []string{"DROP TABLE quizzes;"}},
+		{ID: "002_tags.sql", Down: []string{"DROP TABLE tags;"}},
+	})
+
+	// Expect only the latest applied migration to be rolled back
+	mock.ExpectExec(`SELECT pg_advisory_lock`).
+		WithArgs(lockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS migrations`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT id, applied_at FROM migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "applied_at"}).
+			AddRow("001_init.sql", time.Now()).
+			AddRow("002_tags.sql", time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DROP TABLE tags`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM migrations`).
+		WithArgs("002_tags.sql").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(`SELECT pg_advisory_unlock`).
+		WithArgs(lockKey).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute
+	rolledBack, err := migrator.Down(context.Background(), 0)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(rolledBack) != 1 || rolledBack[0] != "002_tags.sql" {
+		t.Errorf("expected to roll back '002_tags.sql', got %v", rolledBack)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestMigrator_Status_TextTimestamps(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	defer sqlDB.Close()
+
+	migrator := NewMigrator(sqlDB, []*Migration{{ID: "001_init.sql"}, {ID: "002_tags.sql"}})
+
+	// Expect applied_at as text, the way SQLite returns it
+	mock.ExpectExec(`CREATE TABLE IF NOT EXISTS migrations`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT id, applied_at FROM migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "applied_at"}).AddRow("001_init.sql", "2026-10-20 09:30:00"))
+
+	// Execute
+	statuses, err := migrator.Status(context.Background())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := time.Date(2026, 10, 20, 9, 30, 0, 0, time.UTC)
+	if statuses[0].AppliedAt == nil || !statuses[0].AppliedAt.Equal(want) {
+		t.Errorf("expected '001_init.sql' applied at %v, got %v", want, statuses[0].AppliedAt)
+	}
+
+	if statuses[1].AppliedAt != nil {
+		t.Errorf("expected '002_tags.sql' to be pending, got %v", statuses[1].AppliedAt)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
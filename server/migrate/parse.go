@@ -0,0 +1,168 @@
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const annotationPrefix = "-- +migrate "
+
+// Migration is one migration file, split into the statements of each direction
+type Migration struct {
+	ID string
+
+	Up   []string
+	Down []string
+
+	DisableTransactionUp   bool
+	DisableTransactionDown bool
+}
+
+// Load reads every .sql file at the root of fsys, ordered as sql-migrate orders them
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []*Migration
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, err := Parse(entry.Name(), content)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].less(migrations[j])
+	})
+
+	return migrations, nil
+}
+
+// Parse splits a migration file on its `-- +migrate Up` and `-- +migrate Down` annotations.
+// Statements end with a semicolon at the end of a line, unless enclosed in
+// `-- +migrate StatementBegin` and `-- +migrate StatementEnd`.
+func Parse(id string, content []byte) (*Migration, error) {
+	m := &Migration{ID: id}
+
+	var (
+		current    *[]string
+		buf        strings.Builder
+		inBlock    bool
+		annotated  bool
+		lineNumber int
+	)
+
+	flush := func() {
+		if stmt := strings.TrimSpace(buf.String()); stmt != "" && current != nil {
+			*current = append(*current, stmt)
+		}
+		buf.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, annotationPrefix) {
+			fields := strings.Fields(strings.TrimPrefix(trimmed, annotationPrefix))
+			if len(fields) == 0 {
+				return nil, fmt.Errorf("%s:%d: empty migrate annotation", id, lineNumber)
+			}
+
+			noTransaction := len(fields) > 1 && fields[1] == "notransaction"
+
+			switch fields[0] {
+			case "Up":
+				flush()
+				current, annotated = &m.Up, true
+				m.DisableTransactionUp = noTransaction
+			case "Down":
+				flush()
+				current, annotated = &m.Down, true
+				m.DisableTransactionDown = noTransaction
+			case "StatementBegin":
+				flush()
+				inBlock = true
+			case "StatementEnd":
+				if !inBlock {
+					return nil, fmt.Errorf("%s:%d: StatementEnd without StatementBegin", id, lineNumber)
+				}
+				flush()
+				inBlock = false
+			default:
+				return nil, fmt.Errorf("%s:%d: unknown migrate annotation %q", id, lineNumber, fields[0])
+			}
+			continue
+		}
+
+		if !inBlock && (trimmed == "" || strings.HasPrefix(trimmed, "--")) {
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("%s:%d: statement outside of an Up or Down section", id, lineNumber)
+		}
+
+		buf.WriteString(line)
+		buf.WriteString("\n")
+
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", id, err)
+	}
+
+	if inBlock {
+		return nil, fmt.Errorf("%s: StatementBegin without StatementEnd", id)
+	}
+	if !annotated {
+		return nil, fmt.Errorf("%s: no Up or Down annotation found", id)
+	}
+
+	flush()
+	return m, nil
+}
+
+// version is the numeric prefix of the migration ID, or -1 without one
+func (m *Migration) version() int64 {
+	digits := m.ID
+	if i := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		digits = digits[:i]
+	}
+
+	v, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return v
+}
+
+func (m *Migration) less(other *Migration) bool {
+	v, otherV := m.version(), other.version()
+	if v >= 0 && otherV >= 0 && v != otherV {
+		return v < otherV
+	}
+	return m.ID < other.ID
+}
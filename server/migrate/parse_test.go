@@ -0,0 +1,119 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"quiz-log/db"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"20251209120000_create_accounts.sql": {Data: []byte("-- +migrate Up\nCREATE TABLE accounts (id SERIAL);\n")},
+		"001_init.sql":                       {Data: []byte("-- +migrate Up\nCREATE TABLE quizzes (id SERIAL);\n")},
+		"002_tags.sql":                       {Data: []byte("-- +migrate Up\nCREATE TABLE tags (id SERIAL);\n")},
+		"README.md":                          {Data: []byte("not a migration")},
+	}
+
+	// Execute
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert: numeric prefixes order migrations, not their names
+	expected := []string{"001_init.sql", "002_tags.sql", "20251209120000_create_accounts.sql"}
+	if len(migrations) != len(expected) {
+		t.Fatalf("expected %d migrations, got %d", len(expected), len(migrations))
+	}
+
+	for i, id := range expected {
+		if migrations[i].ID != id {
+			t.Errorf("expected migration %d to be '%s', got '%s'", i, id, migrations[i].ID)
+		}
+	}
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	for _, driver := range []string{db.DriverPostgres, db.DriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			migrations, err := Load(db.Migrations(driver))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(migrations) == 0 || migrations[0].ID != "001_init.sql" {
+				t.Fatalf("expected migrations to start with '001_init.sql', got %d migrations", len(migrations))
+			}
+
+			for _, m := range migrations {
+				if len(m.Up) == 0 {
+					t.Errorf("expected up statements in '%s'", m.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	content := `-- +migrate Up
+-- Quizzes table
+CREATE TABLE quizzes (
+    id SERIAL PRIMARY KEY
+);
+CREATE INDEX idx_quizzes_id ON quizzes(id);
+
+-- +migrate StatementBegin
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+
+-- +migrate Down notransaction
+DROP TABLE quizzes;
+`
+
+	// Execute
+	m, err := Parse("001_init.sql", []byte(content))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	if len(m.Up) != 3 {
+		t.Fatalf("expected 3 up statements, got %d: %q", len(m.Up), m.Up)
+	}
+
+	if m.Up[1] != "CREATE INDEX idx_quizzes_id ON quizzes(id);" {
+		t.Errorf("unexpected second statement '%s'", m.Up[1])
+	}
+
+	if len(m.Down) != 1 || m.Down[0] != "DROP TABLE quizzes;" {
+		t.Errorf("expected single down statement, got %q", m.Down)
+	}
+
+	if m.DisableTransactionUp || !m.DisableTransactionDown {
+		t.Errorf("expected only down to disable transactions, got up=%v down=%v", m.DisableTransactionUp, m.DisableTransactionDown)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no annotation":       "CREATE TABLE quizzes (id SERIAL);\n",
+		"unknown annotation":  "-- +migrate Sideways\n",
+		"unclosed statement":  "-- +migrate Up\n-- +migrate StatementBegin\nSELECT 1;\n",
+		"unopened statement":  "-- +migrate Up\n-- +migrate StatementEnd\n",
+		"statement before up": "SELECT 1;\n-- +migrate Up\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse("bad.sql", []byte(content)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
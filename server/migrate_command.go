@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"quiz-log/config"
+	"quiz-log/db"
+	"quiz-log/migrate"
+)
+
+const migrateUsage = "usage: server migrate up|down|status [-limit N]"
+
+// runMigrate implements the `migrate` subcommand against the configured database
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	command := args[0]
+	flags := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	limit := flags.Int("limit", 0, "maximum number of migrations to apply or roll back (down defaults to 1)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	dbConn, err := db.Connect(cfg.DB)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer dbConn.Close()
+
+	migrator, err := newMigrator(dbConn.DB, cfg.DB.Driver)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx, *limit)
+		fmt.Printf("Applied %d migrations\n", len(applied))
+		return err
+	case "down":
+		rolledBack, err := migrator.Down(ctx, *limit)
+		fmt.Printf("Rolled back %d migrations\n", len(rolledBack))
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printMigrationStatus(os.Stdout, statuses)
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", command, migrateUsage)
+	}
+}
+
+// autoMigrate applies pending migrations on startup, waiting for any replica already doing so
+func autoMigrate(ctx context.Context, conn *sql.DB, driver string) error {
+	migrator, err := newMigrator(conn, driver)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx, 0)
+	for _, id := range applied {
+		slog.InfoContext(ctx, "applied migration", "id", id)
+	}
+	return err
+}
+
+// newMigrator creates a migrator for the migrations of the driver embedded in the binary
+func newMigrator(conn *sql.DB, driver string) (*migrate.Migrator, error) {
+	migrations, err := migrate.Load(db.Migrations(driver))
+	if err != nil {
+		return nil, fmt.Errorf("load migrations: %w", err)
+	}
+
+	migrator := migrate.NewMigrator(conn, migrations)
+	migrator.SkipLock = driver == db.DriverSQLite
+	return migrator, nil
+}
+
+func printMigrationStatus(out io.Writer, statuses []migrate.Status) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MIGRATION\tAPPLIED")
+	for _, status := range statuses {
+		applied := "no"
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\n", status.ID, applied)
+	}
+	return w.Flush()
+}
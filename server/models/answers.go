@@ -1,17 +1,23 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
 	"github.com/uptrace/bun"
 )
 
 type Answer struct {
 	bun.BaseModel `bun:"table:answers,alias:a"`
 
-	ID         int    `bun:"id,pk,autoincrement"`
-	AttemptID  *int   `bun:"attempt_id"`
-	QuestionID *int   `bun:"question_id"`
-	UserAnswer string `bun:"user_answer,notnull"`
-	IsCorrect  bool   `bun:"is_correct,notnull"`
+	ID         int     `bun:"id,pk,autoincrement"`
+	AttemptID  *int    `bun:"attempt_id"`
+	QuestionID *int    `bun:"question_id"`
+	UserAnswer string  `bun:"user_answer,notnull"`
+	IsCorrect  bool    `bun:"is_correct,notnull"`
+	Credit     float64 `bun:"credit,notnull"`
+	Blanks     Blanks  `bun:"blanks"`
 }
 
 // Getter methods
@@ -34,3 +40,41 @@ func (a *Answer) GetUserAnswer() string {
 func (a *Answer) GetIsCorrect() bool {
 	return a.IsCorrect
 }
+
+func (a *Answer) GetCredit() float64 {
+	return a.Credit
+}
+
+func (a *Answer) GetBlanks() Blanks {
+	return a.Blanks
+}
+
+// Blanks tells, for each blank of a CLOZE question in order, whether the answer filled it in correctly.
+// It is stored as JSON, with no blanks stored as NULL.
+type Blanks []bool
+
+func (b Blanks) Value() (driver.Value, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal([]bool(b))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (b *Blanks) Scan(src any) error {
+	*b = nil
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), (*[]bool)(b))
+	case []byte:
+		return json.Unmarshal(src, (*[]bool)(b))
+	default:
+		return fmt.Errorf("cannot scan %T into answer blanks", src)
+	}
+}
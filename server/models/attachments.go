@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type Attachment struct {
+	bun.BaseModel `bun:"table:attachments,alias:a"`
+
+	ID          int       `bun:"id,pk,autoincrement"`
+	QuestionID  *int      `bun:"question_id"`
+	Placement   string    `bun:"placement,notnull"`
+	Filename    string    `bun:"filename,notnull"`
+	ContentType string    `bun:"content_type,notnull"`
+	Size        int64     `bun:"size,notnull"`
+	StorageKey  string    `bun:"storage_key,notnull"`
+	CreatedAt   time.Time `bun:"created_at,notnull,nullzero,default:now()"`
+}
+
+// Getter methods
+func (a *Attachment) GetID() int {
+	return a.ID
+}
+
+func (a *Attachment) GetQuestionID() *int {
+	return a.QuestionID
+}
+
+func (a *Attachment) GetPlacement() string {
+	return a.Placement
+}
+
+func (a *Attachment) GetFilename() string {
+	return a.Filename
+}
+
+func (a *Attachment) GetContentType() string {
+	return a.ContentType
+}
+
+func (a *Attachment) GetSize() int64 {
+	return a.Size
+}
+
+func (a *Attachment) GetStorageKey() string {
+	return a.StorageKey
+}
+
+func (a *Attachment) GetCreatedAt() time.Time {
+	return a.CreatedAt
+}
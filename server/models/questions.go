@@ -1,6 +1,9 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/uptrace/bun"
@@ -10,15 +13,22 @@ type Question struct {
 	bun.BaseModel `bun:"table:questions,alias:q"`
 
 	ID            int       `bun:"id,pk,autoincrement"`
-	QuizID        *int      `bun:"quiz_id"`
+	QuizID        *int      `bun:"quiz_id,scanonly"`
 	Type          string    `bun:"type,notnull"`
 	Content       string    `bun:"content,notnull"`
+	ContentFormat string    `bun:"content_format,notnull"`
 	Options       []string  `bun:"options,array"`
+	Payload       Payload   `bun:"payload"`
 	CorrectAnswer string    `bun:"correct_answer,notnull"`
+	Grading       string    `bun:"grading,notnull"`
 	Explanation   *string   `bun:"explanation"`
 	Difficulty    string    `bun:"difficulty,notnull"`
 	CreatedAt     time.Time `bun:"created_at,notnull,nullzero,default:now()"`
 	UpdatedAt     time.Time `bun:"updated_at,notnull,nullzero,default:now()"`
+
+	// Quiz membership, only populated when loaded through quiz_questions
+	Position *int `bun:"position,scanonly"`
+	Points   *int `bun:"points,scanonly"`
 }
 
 // Getter methods
@@ -42,10 +52,18 @@ func (q *Question) GetOptions() []string {
 	return q.Options
 }
 
+func (q *Question) GetPayload() Payload {
+	return q.Payload
+}
+
 func (q *Question) GetCorrectAnswer() string {
 	return q.CorrectAnswer
 }
 
+func (q *Question) GetGrading() string {
+	return q.Grading
+}
+
 func (q *Question) GetExplanation() *string {
 	return q.Explanation
 }
@@ -61,3 +79,67 @@ func (q *Question) GetCreatedAt() time.Time {
 func (q *Question) GetUpdatedAt() time.Time {
 	return q.UpdatedAt
 }
+
+func (q *Question) GetPosition() *int {
+	return q.Position
+}
+
+func (q *Question) GetPoints() *int {
+	return q.Points
+}
+
+// Payload holds the structured content of the question types that options and a correct answer
+// cannot express. It is stored as JSON, with an empty payload stored as NULL.
+type Payload struct {
+	// Pairs of a MATCHING question, each prompt with its answer
+	Pairs []MatchingPair `json:"pairs,omitempty"`
+	// Tolerance and units of a NUMERIC question, whose correct value is its correct answer
+	Numeric *NumericAnswer `json:"numeric,omitempty"`
+	// Blanks of a CLOZE question, the first filling the {{1}} marker of its content
+	Blanks []ClozeBlank `json:"blanks,omitempty"`
+}
+
+type MatchingPair struct {
+	Prompt string `json:"prompt"`
+	Answer string `json:"answer"`
+}
+
+// NumericAnswer says how far answers to a NUMERIC question may be off and which units they may carry.
+// An ABSOLUTE tolerance is in the unit of the answer, a RELATIVE one a fraction of the correct value.
+type NumericAnswer struct {
+	Tolerance     float64  `json:"tolerance"`
+	ToleranceMode string   `json:"toleranceMode"`
+	Units         []string `json:"units,omitempty"`
+}
+
+// ClozeBlank lists the answers accepted for one blank of a CLOZE question and how they are compared
+type ClozeBlank struct {
+	Accepted []string `json:"accepted"`
+	Strategy string   `json:"strategy"`
+}
+
+func (p Payload) Value() (driver.Value, error) {
+	if len(p.Pairs) == 0 && p.Numeric == nil && len(p.Blanks) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (p *Payload) Scan(src any) error {
+	*p = Payload{}
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), p)
+	case []byte:
+		return json.Unmarshal(src, p)
+	default:
+		return fmt.Errorf("cannot scan %T into a question payload", src)
+	}
+}
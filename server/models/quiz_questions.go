@@ -0,0 +1,31 @@
+package models
+
+import (
+	"github.com/uptrace/bun"
+)
+
+type QuizQuestion struct {
+	bun.BaseModel `bun:"table:quiz_questions,alias:qq"`
+
+	QuizID     int `bun:"quiz_id,pk"`
+	QuestionID int `bun:"question_id,pk"`
+	Position   int `bun:"position,notnull,default:0"`
+	Points     int `bun:"points,notnull,default:1"`
+}
+
+// Getter methods
+func (qq *QuizQuestion) GetQuizID() int {
+	return qq.QuizID
+}
+
+func (qq *QuizQuestion) GetQuestionID() int {
+	return qq.QuestionID
+}
+
+func (qq *QuizQuestion) GetPosition() int {
+	return qq.Position
+}
+
+func (qq *QuizQuestion) GetPoints() int {
+	return qq.Points
+}
@@ -12,6 +12,8 @@ type Quiz struct {
 	ID          int       `bun:"id,pk,autoincrement"`
 	Title       string    `bun:"title,notnull"`
 	Description *string   `bun:"description"`
+	IsTemplate  bool      `bun:"is_template,notnull,default:false"`
+	Status      string    `bun:"status,notnull,default:'DRAFT'"`
 	CreatedAt   time.Time `bun:"created_at,notnull,nullzero,default:now()"`
 	UpdatedAt   time.Time `bun:"updated_at,notnull,nullzero,default:now()"`
 }
@@ -29,6 +31,14 @@ func (q *Quiz) GetDescription() *string {
 	return q.Description
 }
 
+func (q *Quiz) GetIsTemplate() bool {
+	return q.IsTemplate
+}
+
+func (q *Quiz) GetStatus() string {
+	return q.Status
+}
+
 func (q *Quiz) GetCreatedAt() time.Time {
 	return q.CreatedAt
 }
@@ -7,8 +7,9 @@ import (
 type Tag struct {
 	bun.BaseModel `bun:"table:tags,alias:t"`
 
-	ID   int    `bun:"id,pk,autoincrement"`
-	Name string `bun:"name,notnull,unique"`
+	ID       int    `bun:"id,pk,autoincrement"`
+	Name     string `bun:"name,notnull,unique"`
+	ParentID *int   `bun:"parent_id"`
 }
 
 // Getter methods
@@ -19,3 +20,7 @@ func (t *Tag) GetID() int {
 func (t *Tag) GetName() string {
 	return t.Name
 }
+
+func (t *Tag) GetParentID() *int {
+	return t.ParentID
+}
@@ -0,0 +1,61 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBuffer is how many payloads a subscriber may fall behind before new ones are dropped
+const subscriberBuffer = 16
+
+type memoryBus struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan []byte]struct{}
+}
+
+// NewMemoryBus creates a Bus delivering messages within this process only
+func NewMemoryBus() Bus {
+	return &memoryBus{subscribers: make(map[string]map[chan []byte]struct{})}
+}
+
+// Publish sends a payload to the subscribers of a topic, dropping it for subscribers that are too slow
+func (b *memoryBus) Publish(ctx context.Context, topic string, payload []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[topic] {
+		select {
+		case ch <- payload:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// Subscribe registers a subscriber to a topic until ctx is done
+func (b *memoryBus) Subscribe(ctx context.Context, topic string) (<-chan []byte, error) {
+	ch := make(chan []byte, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = make(map[chan []byte]struct{})
+	}
+	b.subscribers[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subscribers[topic], ch)
+		if len(b.subscribers[topic]) == 0 {
+			delete(b.subscribers, topic)
+		}
+		b.mu.Unlock()
+
+		close(ch)
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,77 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBus_PublishSubscribe(t *testing.T) {
+	bus := NewMemoryBus()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := bus.Subscribe(ctx, "topic")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	other, err := bus.Subscribe(ctx, "other")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Execute
+	err = bus.Publish(context.Background(), "topic", []byte("hello"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	select {
+	case payload := <-ch:
+		if string(payload) != "hello" {
+			t.Errorf("expected payload 'hello', got '%s'", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected payload, got none")
+	}
+
+	select {
+	case payload := <-other:
+		t.Errorf("expected no payload on other topic, got '%s'", payload)
+	default:
+	}
+
+	// Cancelling the subscription closes the channel
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("expected channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected channel to be closed")
+	}
+}
+
+func TestMemoryBus_SlowSubscriber(t *testing.T) {
+	bus := NewMemoryBus()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := bus.Subscribe(ctx, "topic")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Publishing never blocks, even when nobody reads
+	for i := 0; i < subscriberBuffer*2; i++ {
+		if err := bus.Publish(context.Background(), "topic", []byte("x")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(ch) != subscriberBuffer {
+		t.Errorf("expected %d buffered payloads, got %d", subscriberBuffer, len(ch))
+	}
+}
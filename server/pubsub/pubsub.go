@@ -0,0 +1,14 @@
+package pubsub
+
+import (
+	"context"
+)
+
+// Bus delivers messages published on a topic to every subscriber of that topic.
+// Payloads are raw bytes so a backend such as Postgres LISTEN/NOTIFY can carry them between instances.
+type Bus interface {
+	// Publish sends a payload to the current subscribers of a topic
+	Publish(ctx context.Context, topic string, payload []byte) error
+	// Subscribe returns a channel receiving the payloads of a topic until ctx is done, when it is closed
+	Subscribe(ctx context.Context, topic string) (<-chan []byte, error)
+}
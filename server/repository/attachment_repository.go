@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"quiz-log/models"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/uptrace/bun"
+)
+
+//go:generate mockgen -destination=mocks/mock_attachment_repository.go -package=mocks quiz-log/repository AttachmentRepository
+
+// AttachmentRepository defines the interface for attachment repository operations
+type AttachmentRepository interface {
+	Create(ctx context.Context, questionID int, placement string, filename string, contentType string, size int64, storageKey string) (int, error)
+	FindByID(ctx context.Context, id int) (*models.Attachment, error)
+	FindByQuestionIDs(ctx context.Context, questionIDs []int) (map[int][]*models.Attachment, error)
+	FindOrphans(ctx context.Context, limit int) ([]*models.Attachment, error)
+	FindSharedStorageKeys(ctx context.Context, ids []int) (map[string]bool, error)
+	Delete(ctx context.Context, ids []int) error
+}
+
+type attachmentRepository struct {
+	DB *bun.DB
+}
+
+func NewAttachmentRepository(database *bun.DB) AttachmentRepository {
+	return &attachmentRepository{DB: database}
+}
+
+var attachmentColumns = []string{"id", "question_id", "placement", "filename", "content_type", "size", "storage_key", "created_at"}
+
+// Create records a file stored under storageKey as attached to a question
+func (r *attachmentRepository) Create(ctx context.Context, questionID int, placement string, filename string, contentType string, size int64, storageKey string) (int, error) {
+	var attachmentID int
+
+	query := psql.Insert("attachments").
+		Columns("question_id", "placement", "filename", "content_type", "size", "storage_key").
+		Values(questionID, placement, filename, contentType, size, storageKey).
+		Suffix("RETURNING id")
+
+	err := ExecQueryWithReturning[int](ctx, r.DB, query, &attachmentID)
+	if err != nil {
+		return 0, err
+	}
+
+	return attachmentID, nil
+}
+
+// FindByID retrieves an attachment by its ID
+func (r *attachmentRepository) FindByID(ctx context.Context, id int) (*models.Attachment, error) {
+	query := psql.Select(attachmentColumns...).
+		From("attachments").
+		Where("id = ?", id)
+
+	return FindOne[models.Attachment](ctx, r.DB, query)
+}
+
+// FindByQuestionIDs retrieves the attachments of multiple questions, keyed by question ID, oldest first
+func (r *attachmentRepository) FindByQuestionIDs(ctx context.Context, questionIDs []int) (map[int][]*models.Attachment, error) {
+	if len(questionIDs) == 0 {
+		return make(map[int][]*models.Attachment), nil
+	}
+
+	query := psql.Select(attachmentColumns...).
+		From("attachments").
+		Where(sq.Eq{"question_id": questionIDs}).
+		OrderBy("question_id ASC", "id ASC")
+
+	attachments, err := FindAll[models.Attachment](ctx, r.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]*models.Attachment)
+	for _, a := range attachments {
+		result[*a.QuestionID] = append(result[*a.QuestionID], a)
+	}
+	return result, nil
+}
+
+// FindOrphans retrieves up to limit attachments whose question was deleted, oldest first
+func (r *attachmentRepository) FindOrphans(ctx context.Context, limit int) ([]*models.Attachment, error) {
+	query := psql.Select(attachmentColumns...).
+		From("attachments").
+		Where("question_id IS NULL").
+		OrderBy("id ASC").
+		Limit(uint64(limit))
+
+	return FindAll[models.Attachment](ctx, r.DB, query)
+}
+
+// FindSharedStorageKeys retrieves the storage keys of the given attachments that attachments
+// outside of them still refer to, such as the copies of a duplicated question
+func (r *attachmentRepository) FindSharedStorageKeys(ctx context.Context, ids []int) (map[string]bool, error) {
+	shared := make(map[string]bool)
+	if len(ids) == 0 {
+		return shared, nil
+	}
+
+	query := psql.Select("DISTINCT a.storage_key").
+		From("attachments a").
+		Where(sq.Eq{"a.id": ids}).
+		Where(sq.Expr("EXISTS (?)", psql.Select("1").
+			From("attachments other").
+			Where("other.storage_key = a.storage_key").
+			Where(sq.NotEq{"other.id": ids})))
+
+	attachments, err := FindAll[models.Attachment](ctx, r.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		shared[a.StorageKey] = true
+	}
+	return shared, nil
+}
+
+// Delete deletes the attachments with the given IDs, leaving their blobs to the caller
+func (r *attachmentRepository) Delete(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := ExecQuery(ctx, r.DB, psql.Delete("attachments").Where(sq.Eq{"id": ids}))
+	return err
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/uptrace/bun"
+)
+
+func TestAttachmentRepository_FindByQuestionIDs(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewAttachmentRepository(bunDB)
+
+		// Expect a single query for every question
+		now := time.Now()
+		rows := sqlmock.NewRows([]string{"id", "question_id", "placement", "filename", "content_type", "size", "storage_key", "created_at"}).
+			AddRow(1, 4, "CONTENT", "a.png", "image/png", 10, "k1", now).
+			AddRow(2, 4, "EXPLANATION", "b.png", "image/png", 20, "k2", now).
+			AddRow(3, 7, "CONTENT", "c.pdf", "application/pdf", 30, "k3", now)
+		mock.ExpectQuery(`SELECT id, question_id, placement, filename, content_type, size, storage_key, created_at FROM attachments WHERE question_id IN \(\$1,\$2\) ORDER BY question_id ASC, id ASC`).
+			WithArgs(4, 7).
+			WillReturnRows(rows)
+
+		// Execute
+		attachments, err := repo.FindByQuestionIDs(context.Background(), []int{4, 7})
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(attachments[4]) != 2 || len(attachments[7]) != 1 {
+			t.Errorf("unexpected attachments: %v", attachments)
+		}
+		if attachments[7][0].Size != 30 || attachments[7][0].StorageKey != "k3" {
+			t.Errorf("unexpected attachment: %+v", attachments[7][0])
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestAttachmentRepository_FindOrphans(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewAttachmentRepository(bunDB)
+
+		// Expect the attachments left by deleted questions, oldest first
+		rows := sqlmock.NewRows([]string{"id", "question_id", "placement", "filename", "content_type", "size", "storage_key", "created_at"}).
+			AddRow(5, nil, "CONTENT", "a.png", "image/png", 10, "k5", time.Now())
+		mock.ExpectQuery(`SELECT .+ FROM attachments WHERE question_id IS NULL ORDER BY id ASC LIMIT 100`).
+			WillReturnRows(rows)
+
+		// Execute
+		orphans, err := repo.FindOrphans(context.Background(), 100)
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(orphans) != 1 || orphans[0].QuestionID != nil || orphans[0].StorageKey != "k5" {
+			t.Errorf("unexpected orphans: %v", orphans)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestAttachmentRepository_Delete_EmptyInput(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewAttachmentRepository(bunDB)
+
+		// Execute
+		err := repo.Delete(context.Background(), nil)
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		// No statement should run
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"quiz-log/models"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/uptrace/bun"
 )
 
@@ -13,13 +14,17 @@ import (
 // AttemptRepository defines the interface for attempt repository operations
 type AttemptRepository interface {
 	Create(ctx context.Context, quizID int, startedAt, completedAt time.Time, score, totalQuestions int) (int, error)
+	CreateWithAnswers(ctx context.Context, quizID int, startedAt, completedAt time.Time, score, totalQuestions int, answers []*models.Answer) (int, error)
 	UpdateScore(ctx context.Context, attemptID, score int) error
+	FindQuizStatus(ctx context.Context, quizID int) (string, error)
 	CountQuestionsByQuizID(ctx context.Context, quizID int) (int, error)
 	GetCorrectAnswer(ctx context.Context, questionID int) (string, error)
-	CreateAnswer(ctx context.Context, attemptID, questionID int, userAnswer string, isCorrect bool) error
+	CreateAnswer(ctx context.Context, attemptID, questionID int, userAnswer string, isCorrect bool, credit float64, blanks models.Blanks) error
 	FindByID(ctx context.Context, attemptID int) (*models.Attempt, error)
 	FindAll(ctx context.Context, quizID *int) ([]*models.Attempt, error)
 	FindAnswersByAttemptID(ctx context.Context, attemptID int) ([]*models.Answer, error)
+	FindAnswersByAttemptIDs(ctx context.Context, attemptIDs []int) (map[int][]*models.Answer, error)
+	FindByQuizIDs(ctx context.Context, quizIDs []int) (map[int][]*models.Attempt, error)
 }
 
 type attemptRepository struct {
@@ -47,6 +52,43 @@ func (r *attemptRepository) Create(ctx context.Context, quizID int, startedAt, c
 	return attemptID, nil
 }
 
+// CreateWithAnswers creates a scored attempt with its answers in one transaction and returns its ID.
+// The attempt IDs of the answers are ignored.
+func (r *attemptRepository) CreateWithAnswers(ctx context.Context, quizID int, startedAt, completedAt time.Time, score, totalQuestions int, answers []*models.Answer) (int, error) {
+	var attemptID int
+	err := r.DB.RunInTx(ctx, nil, func(ctx context.Context, bunTx bun.Tx) error {
+		tx := bunTx.Tx
+
+		query := psql.Insert("attempts").
+			Columns("quiz_id", "started_at", "completed_at", "score", "total_questions").
+			Values(quizID, startedAt, completedAt, score, totalQuestions).
+			Suffix("RETURNING id")
+
+		err := ExecTxQueryWithReturning(ctx, tx, query, &attemptID)
+		if err != nil {
+			return err
+		}
+
+		if len(answers) == 0 {
+			return nil
+		}
+
+		insert := psql.Insert("answers").
+			Columns("attempt_id", "question_id", "user_answer", "is_correct", "credit", "blanks")
+		for _, a := range answers {
+			insert = insert.Values(attemptID, a.QuestionID, a.UserAnswer, a.IsCorrect, a.Credit, a.Blanks)
+		}
+
+		_, err = ExecTxQuery(ctx, tx, insert)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return attemptID, nil
+}
+
 // UpdateScore updates the score of an attempt
 func (r *attemptRepository) UpdateScore(ctx context.Context, attemptID, score int) error {
 	query := psql.Update("attempts").
@@ -60,12 +102,28 @@ func (r *attemptRepository) UpdateScore(ctx context.Context, attemptID, score in
 	return nil
 }
 
+// FindQuizStatus retrieves the workflow status of the quiz being attempted
+func (r *attemptRepository) FindQuizStatus(ctx context.Context, quizID int) (string, error) {
+	var status string
+
+	query := psql.Select("status").
+		From("quizzes").
+		Where("id = ?", quizID)
+
+	err := ExecQueryWithReturning[string](ctx, r.DB, query, &status)
+	if err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
 // CountQuestionsByQuizID counts questions for a quiz
 func (r *attemptRepository) CountQuestionsByQuizID(ctx context.Context, quizID int) (int, error) {
 	var count int
 
 	query := psql.Select("COUNT(*)").
-		From("questions").
+		From("quiz_questions").
 		Where("quiz_id = ?", quizID)
 
 	sqlStr, args, err := query.ToSql()
@@ -98,10 +156,10 @@ func (r *attemptRepository) GetCorrectAnswer(ctx context.Context, questionID int
 }
 
 // CreateAnswer creates a new answer record
-func (r *attemptRepository) CreateAnswer(ctx context.Context, attemptID, questionID int, userAnswer string, isCorrect bool) error {
+func (r *attemptRepository) CreateAnswer(ctx context.Context, attemptID, questionID int, userAnswer string, isCorrect bool, credit float64, blanks models.Blanks) error {
 	query := psql.Insert("answers").
-		Columns("attempt_id", "question_id", "user_answer", "is_correct").
-		Values(attemptID, questionID, userAnswer, isCorrect)
+		Columns("attempt_id", "question_id", "user_answer", "is_correct", "credit", "blanks").
+		Values(attemptID, questionID, userAnswer, isCorrect, credit, blanks)
 
 	_, err := ExecQuery(ctx, r.DB, query)
 	if err != nil {
@@ -136,10 +194,56 @@ func (r *attemptRepository) FindAll(ctx context.Context, quizID *int) ([]*models
 
 // FindAnswersByAttemptID retrieves all answers for an attempt
 func (r *attemptRepository) FindAnswersByAttemptID(ctx context.Context, attemptID int) ([]*models.Answer, error) {
-	query := psql.Select("id", "attempt_id", "question_id", "user_answer", "is_correct").
+	query := psql.Select("id", "attempt_id", "question_id", "user_answer", "is_correct", "credit", "blanks").
 		From("answers").
 		Where("attempt_id = ?", attemptID).
 		OrderBy("id ASC")
 
 	return FindAll[models.Answer](ctx, r.DB, query)
 }
+
+// FindAnswersByAttemptIDs retrieves answers for multiple attempts, keyed by attempt ID
+func (r *attemptRepository) FindAnswersByAttemptIDs(ctx context.Context, attemptIDs []int) (map[int][]*models.Answer, error) {
+	if len(attemptIDs) == 0 {
+		return make(map[int][]*models.Answer), nil
+	}
+
+	query := psql.Select("id", "attempt_id", "question_id", "user_answer", "is_correct", "credit", "blanks").
+		From("answers").
+		Where(sq.Eq{"attempt_id": attemptIDs}).
+		OrderBy("id ASC")
+
+	answers, err := FindAll[models.Answer](ctx, r.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]*models.Answer)
+	for _, answer := range answers {
+		result[*answer.AttemptID] = append(result[*answer.AttemptID], answer)
+	}
+	return result, nil
+}
+
+// FindByQuizIDs retrieves attempts for multiple quizzes, keyed by quiz ID, latest first
+func (r *attemptRepository) FindByQuizIDs(ctx context.Context, quizIDs []int) (map[int][]*models.Attempt, error) {
+	if len(quizIDs) == 0 {
+		return make(map[int][]*models.Attempt), nil
+	}
+
+	query := psql.Select("id", "quiz_id", "started_at", "completed_at", "score", "total_questions").
+		From("attempts").
+		Where(sq.Eq{"quiz_id": quizIDs}).
+		OrderBy("started_at DESC", "id DESC")
+
+	attempts, err := FindAll[models.Attempt](ctx, r.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]*models.Attempt)
+	for _, attempt := range attempts {
+		result[*attempt.QuizID] = append(result[*attempt.QuizID], attempt)
+	}
+	return result, nil
+}
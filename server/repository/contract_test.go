@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/uptrace/bun"
+
+	"quiz-log/db"
+	"quiz-log/migrate"
+	"quiz-log/repository"
+	"quiz-log/repository/repositorytest"
+)
+
+func newRepositories(bunDB *bun.DB) repositorytest.Repositories {
+	return repositorytest.Repositories{
+		Quiz:       repository.NewQuizRepository(bunDB),
+		Question:   repository.NewQuestionRepository(bunDB),
+		Tag:        repository.NewTagRepository(bunDB),
+		Attempt:    repository.NewAttemptRepository(bunDB),
+		Statistics: repository.NewStatisticsRepository(bunDB),
+		Attachment: repository.NewAttachmentRepository(bunDB),
+	}
+}
+
+func migrateUp(t *testing.T, bunDB *bun.DB, driver string) {
+	migrations, err := migrate.Load(db.Migrations(driver))
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	migrator := migrate.NewMigrator(bunDB.DB, migrations)
+	migrator.SkipLock = driver == db.DriverSQLite
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+}
+
+func TestContract_SQLite(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		bunDB, err := db.Connect(db.Config{Driver: db.DriverSQLite, Path: ":memory:"})
+		if err != nil {
+			t.Fatalf("failed to open SQLite: %v", err)
+		}
+		t.Cleanup(func() { bunDB.Close() })
+
+		migrateUp(t, bunDB, db.DriverSQLite)
+		return newRepositories(bunDB)
+	})
+}
+
+// TestContract_Postgres runs against the database named by TEST_DB_NAME, which it empties before every test.
+// The connection is configured by the DB_* variables of the server.
+func TestContract_Postgres(t *testing.T) {
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" {
+		t.Skip("TEST_DB_NAME is not set")
+	}
+
+	port, err := strconv.Atoi(getenv("DB_PORT", "5432"))
+	if err != nil {
+		t.Fatalf("invalid DB_PORT: %v", err)
+	}
+
+	bunDB, err := db.Connect(db.Config{
+		Driver:   db.DriverPostgres,
+		Host:     getenv("DB_HOST", "localhost"),
+		Port:     port,
+		User:     getenv("DB_USER", "postgres"),
+		Password: getenv("DB_PASSWORD", "postgres"),
+		DBName:   name,
+	})
+	if err != nil {
+		t.Fatalf("failed to connect to Postgres: %v", err)
+	}
+	t.Cleanup(func() { bunDB.Close() })
+
+	migrateUp(t, bunDB, db.DriverPostgres)
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		truncateTables(t, bunDB)
+		return newRepositories(bunDB)
+	})
+}
+
+// truncateTables empties every table but the migration history, restarting the ID sequences
+func truncateTables(t *testing.T, bunDB *bun.DB) {
+	ctx := context.Background()
+
+	var tables []bun.Ident
+	err := bunDB.NewSelect().
+		Column("tablename").
+		Table("pg_tables").
+		Where("schemaname = current_schema()").
+		Where("tablename <> ?", migrate.DefaultTable).
+		Scan(ctx, &tables)
+	if err != nil {
+		t.Fatalf("failed to list tables: %v", err)
+	}
+
+	if _, err := bunDB.ExecContext(ctx, "TRUNCATE ? RESTART IDENTITY CASCADE", bun.In(tables)); err != nil {
+		t.Fatalf("failed to truncate tables: %v", err)
+	}
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
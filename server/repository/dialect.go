@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect"
+)
+
+// Dialect covers the SQL differences between the storage backends.
+// Both accept the $1 placeholders squirrel generates, so only column types differ.
+type Dialect interface {
+	// Array wraps a string slice as a query argument
+	Array(values []string) driver.Valuer
+	// ScanArray wraps a string slice as a scan destination
+	ScanArray(dest *[]string) sql.Scanner
+}
+
+// dialectOf returns the Dialect of the database the repositories run on
+func dialectOf(db *bun.DB) Dialect {
+	if db.Dialect().Name() == dialect.SQLite {
+		return sqliteDialect{}
+	}
+	return postgresDialect{}
+}
+
+// postgresDialect stores string slices as TEXT[]
+type postgresDialect struct{}
+
+func (postgresDialect) Array(values []string) driver.Valuer {
+	return pq.Array(values)
+}
+
+func (postgresDialect) ScanArray(dest *[]string) sql.Scanner {
+	return pq.Array(dest)
+}
+
+// sqliteDialect stores string slices as JSON arrays in TEXT columns
+type sqliteDialect struct{}
+
+func (sqliteDialect) Array(values []string) driver.Valuer {
+	return jsonArray{values: &values}
+}
+
+func (sqliteDialect) ScanArray(dest *[]string) sql.Scanner {
+	return jsonArray{values: dest}
+}
+
+// jsonArray converts a string slice to and from JSON, with nil stored as NULL
+type jsonArray struct {
+	values *[]string
+}
+
+func (a jsonArray) Value() (driver.Value, error) {
+	if *a.values == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(*a.values)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (a jsonArray) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*a.values = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), a.values)
+	case []byte:
+		return json.Unmarshal(src, a.values)
+	default:
+		return fmt.Errorf("cannot scan %T into a JSON array", src)
+	}
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"quiz-log/models"
+	"quiz-log/repository"
+)
+
+type attachmentRepository struct {
+	store *Store
+}
+
+// NewAttachmentRepository creates an AttachmentRepository on the store
+func NewAttachmentRepository(store *Store) repository.AttachmentRepository {
+	return &attachmentRepository{store: store}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, questionID int, placement string, filename string, contentType string, size int64, storageKey string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.question(questionID) == nil {
+		return 0, errForeignKey()
+	}
+
+	a := &models.Attachment{
+		ID:          s.nextID("attachments"),
+		QuestionID:  &questionID,
+		Placement:   placement,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  storageKey,
+		CreatedAt:   s.now(),
+	}
+	s.attachments = append(s.attachments, a)
+	return a.ID, nil
+}
+
+func (r *attachmentRepository) FindByID(ctx context.Context, id int) (*models.Attachment, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, a := range s.attachments {
+		if a.ID == id {
+			return copyAttachment(a), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *attachmentRepository) FindByQuestionIDs(ctx context.Context, questionIDs []int) (map[int][]*models.Attachment, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Attachments are kept in ID order, so each list comes out oldest first
+	result := make(map[int][]*models.Attachment)
+	for _, a := range s.attachments {
+		if a.QuestionID != nil && slices.Contains(questionIDs, *a.QuestionID) {
+			result[*a.QuestionID] = append(result[*a.QuestionID], copyAttachment(a))
+		}
+	}
+	return result, nil
+}
+
+func (r *attachmentRepository) FindOrphans(ctx context.Context, limit int) ([]*models.Attachment, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var orphans []*models.Attachment
+	for _, a := range s.attachments {
+		if a.QuestionID == nil && len(orphans) < limit {
+			orphans = append(orphans, copyAttachment(a))
+		}
+	}
+	return orphans, nil
+}
+
+func (r *attachmentRepository) FindSharedStorageKeys(ctx context.Context, ids []int) (map[string]bool, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shared := make(map[string]bool)
+	for _, a := range s.attachments {
+		if !slices.Contains(ids, a.ID) {
+			continue
+		}
+		for _, other := range s.attachments {
+			if other.StorageKey == a.StorageKey && !slices.Contains(ids, other.ID) {
+				shared[a.StorageKey] = true
+			}
+		}
+	}
+	return shared, nil
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, ids []int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attachments = slices.DeleteFunc(s.attachments, func(a *models.Attachment) bool { return slices.Contains(ids, a.ID) })
+	return nil
+}
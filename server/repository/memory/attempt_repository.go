@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"quiz-log/models"
+	"quiz-log/repository"
+)
+
+type attemptRepository struct {
+	store *Store
+}
+
+// NewAttemptRepository creates an AttemptRepository on the store
+func NewAttemptRepository(store *Store) repository.AttemptRepository {
+	return &attemptRepository{store: store}
+}
+
+func (r *attemptRepository) Create(ctx context.Context, quizID int, startedAt, completedAt time.Time, score, totalQuestions int) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.quiz(quizID) == nil {
+		return 0, errForeignKey()
+	}
+
+	a := copyAttempt(&models.Attempt{
+		ID:             s.nextID("attempts"),
+		QuizID:         &quizID,
+		StartedAt:      startedAt,
+		CompletedAt:    &completedAt,
+		Score:          score,
+		TotalQuestions: totalQuestions,
+	})
+	s.attempts = append(s.attempts, a)
+	return a.ID, nil
+}
+
+func (r *attemptRepository) CreateWithAnswers(ctx context.Context, quizID int, startedAt, completedAt time.Time, score, totalQuestions int, answers []*models.Answer) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check every answer first, as the transaction would roll the attempt back
+	if s.quiz(quizID) == nil {
+		return 0, errForeignKey()
+	}
+	for _, a := range answers {
+		if a.QuestionID == nil || s.question(*a.QuestionID) == nil {
+			return 0, errForeignKey()
+		}
+	}
+
+	attempt := copyAttempt(&models.Attempt{
+		ID:             s.nextID("attempts"),
+		QuizID:         &quizID,
+		StartedAt:      startedAt,
+		CompletedAt:    &completedAt,
+		Score:          score,
+		TotalQuestions: totalQuestions,
+	})
+	s.attempts = append(s.attempts, attempt)
+
+	for _, a := range answers {
+		attemptID, questionID := attempt.ID, *a.QuestionID
+		s.answers = append(s.answers, &models.Answer{
+			ID:         s.nextID("answers"),
+			AttemptID:  &attemptID,
+			QuestionID: &questionID,
+			UserAnswer: a.UserAnswer,
+			IsCorrect:  a.IsCorrect,
+			Credit:     a.Credit,
+			Blanks:     slices.Clone(a.Blanks),
+		})
+	}
+	return attempt.ID, nil
+}
+
+func (r *attemptRepository) UpdateScore(ctx context.Context, attemptID, score int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if a := s.attempt(attemptID); a != nil {
+		a.Score = score
+	}
+	return nil
+}
+
+func (r *attemptRepository) FindQuizStatus(ctx context.Context, quizID int) (string, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q := s.quiz(quizID)
+	if q == nil {
+		return "", sql.ErrNoRows
+	}
+	return q.Status, nil
+}
+
+func (r *attemptRepository) CountQuestionsByQuizID(ctx context.Context, quizID int) (int, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := 0
+	for _, qq := range s.quizQuestions {
+		if qq.QuizID == quizID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *attemptRepository) GetCorrectAnswer(ctx context.Context, questionID int) (string, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q := s.question(questionID)
+	if q == nil {
+		return "", sql.ErrNoRows
+	}
+	return q.CorrectAnswer, nil
+}
+
+func (r *attemptRepository) CreateAnswer(ctx context.Context, attemptID, questionID int, userAnswer string, isCorrect bool, credit float64, blanks models.Blanks) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.attempt(attemptID) == nil || s.question(questionID) == nil {
+		return errForeignKey()
+	}
+
+	s.answers = append(s.answers, &models.Answer{
+		ID:         s.nextID("answers"),
+		AttemptID:  &attemptID,
+		QuestionID: &questionID,
+		UserAnswer: userAnswer,
+		IsCorrect:  isCorrect,
+		Credit:     credit,
+		Blanks:     slices.Clone(blanks),
+	})
+	return nil
+}
+
+func (r *attemptRepository) FindByID(ctx context.Context, attemptID int) (*models.Attempt, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a := s.attempt(attemptID)
+	if a == nil {
+		return nil, nil
+	}
+	return copyAttempt(a), nil
+}
+
+func (r *attemptRepository) FindAll(ctx context.Context, quizID *int) ([]*models.Attempt, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var attempts []*models.Attempt
+	for _, a := range s.attempts {
+		if quizID == nil || (a.QuizID != nil && *a.QuizID == *quizID) {
+			attempts = append(attempts, copyAttempt(a))
+		}
+	}
+	slices.SortStableFunc(attempts, func(a, b *models.Attempt) int { return b.StartedAt.Compare(a.StartedAt) })
+	return attempts, nil
+}
+
+func (r *attemptRepository) FindAnswersByAttemptID(ctx context.Context, attemptID int) ([]*models.Answer, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Answers are appended with increasing IDs, so they are already in ID order
+	var answers []*models.Answer
+	for _, a := range s.answers {
+		if a.AttemptID != nil && *a.AttemptID == attemptID {
+			c := *a
+			c.Blanks = slices.Clone(a.Blanks)
+			answers = append(answers, &c)
+		}
+	}
+	return answers, nil
+}
+
+func (r *attemptRepository) FindAnswersByAttemptIDs(ctx context.Context, attemptIDs []int) (map[int][]*models.Answer, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int][]*models.Answer)
+	for _, a := range s.answers {
+		if a.AttemptID != nil && slices.Contains(attemptIDs, *a.AttemptID) {
+			c := *a
+			c.Blanks = slices.Clone(a.Blanks)
+			result[*a.AttemptID] = append(result[*a.AttemptID], &c)
+		}
+	}
+	return result, nil
+}
+
+func (r *attemptRepository) FindByQuizIDs(ctx context.Context, quizIDs []int) (map[int][]*models.Attempt, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int][]*models.Attempt)
+	for _, a := range s.attempts {
+		if a.QuizID != nil && slices.Contains(quizIDs, *a.QuizID) {
+			result[*a.QuizID] = append(result[*a.QuizID], copyAttempt(a))
+		}
+	}
+	for _, attempts := range result {
+		slices.SortFunc(attempts, func(a, b *models.Attempt) int {
+			if c := b.StartedAt.Compare(a.StartedAt); c != 0 {
+				return c
+			}
+			return b.ID - a.ID
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"quiz-log/repository/repositorytest"
+)
+
+func TestContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		store := NewStore()
+		return repositorytest.Repositories{
+			Quiz:       NewQuizRepository(store),
+			Question:   NewQuestionRepository(store),
+			Tag:        NewTagRepository(store),
+			Attempt:    NewAttemptRepository(store),
+			Statistics: NewStatisticsRepository(store),
+			Attachment: NewAttachmentRepository(store),
+		}
+	})
+}
+
+func TestSeed(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+
+	// Execute
+	if err := Seed(ctx, store); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	quizRepo := NewQuizRepository(store)
+	published, err := quizRepo.FindAll(ctx, "PUBLISHED")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(published) != 3 {
+		t.Errorf("expected 3 published quizzes, got %d", len(published))
+	}
+
+	templates, err := quizRepo.FindTemplates(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Errorf("expected 1 template, got %d", len(templates))
+	}
+
+	attempts, err := NewStatisticsRepository(store).CountTotalAttempts(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != len(published) {
+		t.Errorf("expected an attempt per published quiz, got %d", attempts)
+	}
+}
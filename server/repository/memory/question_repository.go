@@ -0,0 +1,504 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"quiz-log/models"
+	"quiz-log/repository"
+)
+
+type questionRepository struct {
+	store *Store
+}
+
+// NewQuestionRepository creates a QuestionRepository on the store
+func NewQuestionRepository(store *Store) repository.QuestionRepository {
+	return &questionRepository{store: store}
+}
+
+func (r *questionRepository) Create(ctx context.Context, questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertQuestion(questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty), nil
+}
+
+func (r *questionRepository) CreateWithLinks(ctx context.Context, questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string, tagIDs []string, quizID *int, points int) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check every link first, as the transaction would roll the question back
+	tags, err := s.parseTagIDs(tagIDs)
+	if err != nil {
+		return 0, err
+	}
+	for i, id := range tags {
+		if slices.Contains(tags[:i], id) {
+			return 0, errUniqueKey()
+		}
+	}
+	if quizID != nil && s.quiz(*quizID) == nil {
+		return 0, errForeignKey()
+	}
+
+	questionID := s.insertQuestion(questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty)
+	for _, id := range tags {
+		s.questionTags = append(s.questionTags, &models.QuestionTag{QuestionID: questionID, TagID: id})
+	}
+
+	if quizID != nil {
+		position := 0
+		for _, qq := range s.quizQuestions {
+			if qq.QuizID == *quizID {
+				position = max(position, qq.Position+1)
+			}
+		}
+		s.quizQuestions = append(s.quizQuestions, &models.QuizQuestion{
+			QuizID:     *quizID,
+			QuestionID: questionID,
+			Position:   position,
+			Points:     points,
+		})
+	}
+
+	return questionID, nil
+}
+
+func (s *Store) insertQuestion(questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string) int {
+	now := s.now()
+	q := copyQuestion(&models.Question{
+		ID:            s.nextID("questions"),
+		Type:          questionType,
+		Content:       content,
+		ContentFormat: contentFormat,
+		Options:       options,
+		Payload:       payload,
+		CorrectAnswer: correctAnswer,
+		Grading:       grading,
+		Explanation:   explanation,
+		Difficulty:    difficulty,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	s.questions = append(s.questions, q)
+	return q.ID
+}
+
+func (r *questionRepository) Update(ctx context.Context, id int, questionType *string, content *string, contentFormat *string, options []string, payload *models.Payload, correctAnswer *string, grading *string, explanation *string, difficulty *string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.question(id)
+	if q == nil {
+		return nil
+	}
+
+	updated := false
+	if questionType != nil {
+		q.Type = *questionType
+		updated = true
+	}
+	if content != nil {
+		q.Content = *content
+		updated = true
+	}
+	if contentFormat != nil {
+		q.ContentFormat = *contentFormat
+		updated = true
+	}
+	if options != nil {
+		q.Options = slices.Clone(options)
+		updated = true
+	}
+	if payload != nil {
+		q.Payload = copyPayload(*payload)
+		updated = true
+	}
+	if correctAnswer != nil {
+		q.CorrectAnswer = *correctAnswer
+		updated = true
+	}
+	if grading != nil {
+		q.Grading = *grading
+		updated = true
+	}
+	if explanation != nil {
+		e := *explanation
+		q.Explanation = &e
+		updated = true
+	}
+	if difficulty != nil {
+		q.Difficulty = *difficulty
+		updated = true
+	}
+
+	if updated {
+		q.UpdatedAt = s.now()
+	}
+	return nil
+}
+
+func (r *questionRepository) Delete(ctx context.Context, id int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteQuestionCascade(id)
+	return nil
+}
+
+func (r *questionRepository) FindAll(ctx context.Context, quizID *int) ([]*models.Question, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if quizID != nil {
+		return nilIfEmpty(s.questionsOfQuiz(*quizID)), nil
+	}
+
+	var questions []*models.Question
+	for _, q := range s.questions {
+		questions = append(questions, copyQuestion(q))
+	}
+	slices.SortStableFunc(questions, func(a, b *models.Question) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return questions, nil
+}
+
+func (r *questionRepository) FindByID(ctx context.Context, id int) (*models.Question, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q := s.question(id)
+	if q == nil {
+		return nil, nil
+	}
+	return copyQuestion(q), nil
+}
+
+func (r *questionRepository) FindByIDs(ctx context.Context, ids []int) ([]*models.Question, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var questions []*models.Question
+	for _, q := range s.questions {
+		if slices.Contains(ids, q.ID) {
+			questions = append(questions, copyQuestion(q))
+		}
+	}
+	slices.SortFunc(questions, func(a, b *models.Question) int { return a.ID - b.ID })
+	return questions, nil
+}
+
+func (r *questionRepository) FindWrongQuestions(ctx context.Context) ([]*models.Question, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var questions []*models.Question
+	for _, q := range s.questions {
+		if slices.ContainsFunc(s.answers, func(a *models.Answer) bool {
+			return !a.IsCorrect && a.QuestionID != nil && *a.QuestionID == q.ID
+		}) {
+			questions = append(questions, copyQuestion(q))
+		}
+	}
+	slices.SortStableFunc(questions, func(a, b *models.Question) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return questions, nil
+}
+
+func (r *questionRepository) FindTagsByQuestionID(ctx context.Context, questionID int) ([]*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tagsOf(s.questionTagIDs(questionID)), nil
+}
+
+func (s *Store) questionTagIDs(questionID int) []int {
+	var ids []int
+	for _, qt := range s.questionTags {
+		if qt.QuestionID == questionID {
+			ids = append(ids, qt.TagID)
+		}
+	}
+	return ids
+}
+
+func (r *questionRepository) AssociateTags(ctx context.Context, questionID int, tagIDs []string) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.parseTagIDs(tagIDs)
+	if err != nil {
+		return err
+	}
+	if s.question(questionID) == nil {
+		return errForeignKey()
+	}
+
+	existing := s.questionTagIDs(questionID)
+	for i, id := range ids {
+		if slices.Contains(existing, id) || slices.Contains(ids[:i], id) {
+			return errUniqueKey()
+		}
+	}
+
+	for _, id := range ids {
+		s.questionTags = append(s.questionTags, &models.QuestionTag{QuestionID: questionID, TagID: id})
+	}
+	return nil
+}
+
+func (r *questionRepository) ClearTags(ctx context.Context, questionID int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.questionTags = slices.DeleteFunc(s.questionTags, func(qt *models.QuestionTag) bool { return qt.QuestionID == questionID })
+	return nil
+}
+
+func (r *questionRepository) AddToQuiz(ctx context.Context, quizID, questionID int, points int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.quiz(quizID) == nil || s.question(questionID) == nil {
+		return errForeignKey()
+	}
+
+	position := 0
+	for _, qq := range s.quizQuestions {
+		if qq.QuizID != quizID {
+			continue
+		}
+		if qq.QuestionID == questionID {
+			qq.Points = points
+			return nil
+		}
+		position = max(position, qq.Position+1)
+	}
+
+	s.quizQuestions = append(s.quizQuestions, &models.QuizQuestion{
+		QuizID:     quizID,
+		QuestionID: questionID,
+		Position:   position,
+		Points:     points,
+	})
+	return nil
+}
+
+func (r *questionRepository) RemoveFromQuiz(ctx context.Context, quizID, questionID int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quizQuestions = slices.DeleteFunc(s.quizQuestions, func(qq *models.QuizQuestion) bool {
+		return qq.QuizID == quizID && qq.QuestionID == questionID
+	})
+	return nil
+}
+
+func (r *questionRepository) FindTagsByQuestionIDs(ctx context.Context, questionIDs []int) (map[int][]*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int][]*models.Tag)
+	for _, id := range questionIDs {
+		if tags := s.tagsOf(s.questionTagIDs(id)); len(tags) > 0 {
+			result[id] = tags
+		}
+	}
+	return result, nil
+}
+
+func (r *questionRepository) FindTaggedByKeywords(ctx context.Context, keywords []string, limit int) ([]*models.Question, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var questions []*models.Question
+	for _, q := range s.questions {
+		content := strings.ToLower(q.Content)
+		matches := slices.ContainsFunc(keywords, func(keyword string) bool {
+			return strings.Contains(content, strings.ToLower(keyword))
+		})
+		if matches && len(s.questionTagIDs(q.ID)) > 0 {
+			questions = append(questions, copyQuestion(q))
+		}
+	}
+	slices.SortFunc(questions, func(a, b *models.Question) int {
+		if c := b.UpdatedAt.Compare(a.UpdatedAt); c != 0 {
+			return c
+		}
+		return b.ID - a.ID
+	})
+	if len(questions) > limit {
+		questions = questions[:limit]
+	}
+	return questions, nil
+}
+
+func (r *questionRepository) BulkUpdate(ctx context.Context, ids []int, explanation *string, difficulty *string) error {
+	if explanation == nil && difficulty == nil {
+		return nil
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for _, q := range s.questions {
+		if !slices.Contains(ids, q.ID) {
+			continue
+		}
+		if explanation != nil {
+			e := *explanation
+			q.Explanation = &e
+		}
+		if difficulty != nil {
+			q.Difficulty = *difficulty
+		}
+		q.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r *questionRepository) BulkDelete(ctx context.Context, ids []int) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for _, id := range ids {
+		if s.question(id) != nil {
+			s.deleteQuestionCascade(id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *questionRepository) BulkTag(ctx context.Context, ids []int, addTagIDs, removeTagIDs []int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check the new associations first, as the transaction would roll the removals back
+	if len(addTagIDs) > 0 {
+		for _, id := range ids {
+			if s.question(id) == nil {
+				return errForeignKey()
+			}
+		}
+		for _, tagID := range addTagIDs {
+			if s.tag(tagID) == nil {
+				return errForeignKey()
+			}
+		}
+	}
+
+	s.questionTags = slices.DeleteFunc(s.questionTags, func(qt *models.QuestionTag) bool {
+		return slices.Contains(ids, qt.QuestionID) && slices.Contains(removeTagIDs, qt.TagID)
+	})
+
+	for _, id := range ids {
+		for _, tagID := range addTagIDs {
+			if !slices.Contains(s.questionTagIDs(id), tagID) {
+				s.questionTags = append(s.questionTags, &models.QuestionTag{QuestionID: id, TagID: tagID})
+			}
+		}
+	}
+	return nil
+}
+
+func (r *questionRepository) MoveToQuiz(ctx context.Context, ids []int, sourceQuizID *int, quizID int, points int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.quiz(quizID) == nil {
+		return errForeignKey()
+	}
+
+	next := 0
+	for _, qq := range s.quizQuestions {
+		if qq.QuizID == quizID {
+			next = max(next, qq.Position+1)
+		}
+	}
+
+	for i, id := range ids {
+		if s.question(id) == nil {
+			continue
+		}
+
+		var membership *models.QuizQuestion
+		p := points
+		for _, qq := range s.quizQuestions {
+			if qq.QuestionID != id {
+				continue
+			}
+			if qq.QuizID == quizID {
+				membership = qq
+			}
+			if sourceQuizID != nil && qq.QuizID == *sourceQuizID {
+				p = qq.Points
+			}
+		}
+
+		if membership != nil {
+			membership.Position = next + i
+			continue
+		}
+		s.quizQuestions = append(s.quizQuestions, &models.QuizQuestion{
+			QuizID:     quizID,
+			QuestionID: id,
+			Position:   next + i,
+			Points:     p,
+		})
+	}
+
+	if sourceQuizID == nil || *sourceQuizID == quizID {
+		return nil
+	}
+
+	s.quizQuestions = slices.DeleteFunc(s.quizQuestions, func(qq *models.QuizQuestion) bool {
+		return slices.Contains(ids, qq.QuestionID) && qq.QuizID == *sourceQuizID
+	})
+	return nil
+}
+
+func (r *questionRepository) Reorder(ctx context.Context, quizID int, orderedIDs []int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, qq := range s.quizQuestions {
+		if qq.QuizID != quizID {
+			continue
+		}
+		if i := slices.Index(orderedIDs, qq.QuestionID); i >= 0 {
+			qq.Position = i
+		}
+	}
+	return nil
+}
@@ -0,0 +1,329 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"quiz-log/models"
+	"quiz-log/repository"
+)
+
+type quizRepository struct {
+	store *Store
+}
+
+// NewQuizRepository creates a QuizRepository on the store
+func NewQuizRepository(store *Store) repository.QuizRepository {
+	return &quizRepository{store: store}
+}
+
+func (r *quizRepository) Create(ctx context.Context, title string, description *string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertQuiz(title, description), nil
+}
+
+func (s *Store) insertQuiz(title string, description *string) int {
+	now := s.now()
+	q := copyQuiz(&models.Quiz{
+		ID:          s.nextID("quizzes"),
+		Title:       title,
+		Description: description,
+		Status:      "DRAFT",
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	s.quizzes = append(s.quizzes, q)
+	return q.ID
+}
+
+func (r *quizRepository) Update(ctx context.Context, id int, title *string, description *string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := s.quiz(id)
+	if q == nil || (title == nil && description == nil) {
+		return nil
+	}
+
+	if title != nil {
+		q.Title = *title
+	}
+	if description != nil {
+		d := *description
+		q.Description = &d
+	}
+	q.UpdatedAt = s.now()
+	return nil
+}
+
+func (r *quizRepository) Delete(ctx context.Context, id int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteQuizCascade(id)
+	return nil
+}
+
+func (r *quizRepository) FindAll(ctx context.Context, status string) ([]*models.Quiz, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var quizzes []*models.Quiz
+	for _, q := range s.quizzes {
+		if !q.IsTemplate && q.Status == status {
+			quizzes = append(quizzes, copyQuiz(q))
+		}
+	}
+	slices.SortStableFunc(quizzes, func(a, b *models.Quiz) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return quizzes, nil
+}
+
+func (r *quizRepository) FindByID(ctx context.Context, id int) (*models.Quiz, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	q := s.quiz(id)
+	if q == nil {
+		return nil, nil
+	}
+	return copyQuiz(q), nil
+}
+
+func (r *quizRepository) FindQuestionsByQuizID(ctx context.Context, quizID int) ([]*models.Question, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return nilIfEmpty(s.questionsOfQuiz(quizID)), nil
+}
+
+func (r *quizRepository) FindTagsByQuizID(ctx context.Context, quizID int) ([]*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tagsOf(s.quizTagIDs(quizID)), nil
+}
+
+func (s *Store) quizTagIDs(quizID int) []int {
+	var ids []int
+	for _, qt := range s.quizTags {
+		if qt.QuizID == quizID {
+			ids = append(ids, qt.TagID)
+		}
+	}
+	return ids
+}
+
+func (r *quizRepository) AssociateTags(ctx context.Context, quizID int, tagIDs []string) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.parseTagIDs(tagIDs)
+	if err != nil {
+		return err
+	}
+	if s.quiz(quizID) == nil {
+		return errForeignKey()
+	}
+
+	// The rows are inserted by one statement, so either all or none of them are
+	existing := s.quizTagIDs(quizID)
+	for i, id := range ids {
+		if slices.Contains(existing, id) || slices.Contains(ids[:i], id) {
+			return errUniqueKey()
+		}
+	}
+
+	for _, id := range ids {
+		s.quizTags = append(s.quizTags, &models.QuizTag{QuizID: quizID, TagID: id})
+	}
+	return nil
+}
+
+func (r *quizRepository) ClearTags(ctx context.Context, quizID int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.quizTags = slices.DeleteFunc(s.quizTags, func(qt *models.QuizTag) bool { return qt.QuizID == quizID })
+	return nil
+}
+
+func (r *quizRepository) FindQuestionsByQuizIDs(ctx context.Context, quizIDs []int) (map[int][]*models.Question, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int][]*models.Question)
+	for _, quizID := range quizIDs {
+		if questions := s.questionsOfQuiz(quizID); len(questions) > 0 {
+			result[quizID] = questions
+		}
+	}
+	return result, nil
+}
+
+func (r *quizRepository) FindTagsByQuizIDs(ctx context.Context, quizIDs []int) (map[int][]*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int][]*models.Tag)
+	for _, quizID := range quizIDs {
+		if tags := s.tagsOf(s.quizTagIDs(quizID)); len(tags) > 0 {
+			result[quizID] = tags
+		}
+	}
+	return result, nil
+}
+
+func (r *quizRepository) FindTemplates(ctx context.Context) ([]*models.Quiz, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var quizzes []*models.Quiz
+	for _, q := range s.quizzes {
+		if q.IsTemplate {
+			quizzes = append(quizzes, copyQuiz(q))
+		}
+	}
+	slices.SortStableFunc(quizzes, func(a, b *models.Quiz) int { return compareStrings(a.Title, b.Title) })
+	return quizzes, nil
+}
+
+func (r *quizRepository) SetTemplate(ctx context.Context, id int, isTemplate bool) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if q := s.quiz(id); q != nil {
+		q.IsTemplate = isTemplate
+		q.UpdatedAt = s.now()
+	}
+	return nil
+}
+
+func (r *quizRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if q := s.quiz(id); q != nil {
+		q.Status = status
+		q.UpdatedAt = s.now()
+	}
+	return nil
+}
+
+func (r *quizRepository) Duplicate(ctx context.Context, id int, title string, includeTags bool, replace func(string) string) (int, error) {
+	if replace == nil {
+		replace = func(s string) string { return s }
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	source := s.quiz(id)
+	if source == nil {
+		return 0, sql.ErrNoRows
+	}
+
+	var description *string
+	if source.Description != nil {
+		d := replace(*source.Description)
+		description = &d
+	}
+	newQuizID := s.insertQuiz(title, description)
+
+	for _, q := range s.questionsOfQuiz(id) {
+		var explanation *string
+		if q.Explanation != nil {
+			e := replace(*q.Explanation)
+			explanation = &e
+		}
+
+		var options []string
+		for _, option := range q.Options {
+			options = append(options, replace(option))
+		}
+
+		// Numeric answers and their units are matched against what quiz takers type, so they are kept
+		payload := models.Payload{Numeric: q.Payload.Numeric}
+		for _, pair := range q.Payload.Pairs {
+			payload.Pairs = append(payload.Pairs, models.MatchingPair{Prompt: replace(pair.Prompt), Answer: replace(pair.Answer)})
+		}
+		for _, blank := range q.Payload.Blanks {
+			// Blanks compared as numbers are kept like numeric answers
+			accepted := blank.Accepted
+			if blank.Strategy != "NUMERIC" {
+				accepted = nil
+				for _, answer := range blank.Accepted {
+					accepted = append(accepted, replace(answer))
+				}
+			}
+			payload.Blanks = append(payload.Blanks, models.ClozeBlank{Accepted: accepted, Strategy: blank.Strategy})
+		}
+
+		// The answer to an ORDERING question refers to its options by index, that to a NUMERIC one is a value
+		correctAnswer := replace(q.CorrectAnswer)
+		if q.Type == "ORDERING" || q.Type == "NUMERIC" {
+			correctAnswer = q.CorrectAnswer
+		}
+
+		newQuestionID := s.insertQuestion(q.Type, replace(q.Content), q.ContentFormat, options, payload, correctAnswer, q.Grading, explanation, q.Difficulty)
+		s.quizQuestions = append(s.quizQuestions, &models.QuizQuestion{
+			QuizID:     newQuizID,
+			QuestionID: newQuestionID,
+			Position:   *q.Position,
+			Points:     *q.Points,
+		})
+
+		for _, tagID := range s.questionTagIDs(q.ID) {
+			s.questionTags = append(s.questionTags, &models.QuestionTag{QuestionID: newQuestionID, TagID: tagID})
+		}
+
+		// The copies share the blobs of the originals
+		for _, a := range s.attachments {
+			if a.QuestionID == nil || *a.QuestionID != q.ID {
+				continue
+			}
+			copied := copyAttachment(a)
+			copied.ID = s.nextID("attachments")
+			copied.QuestionID = &newQuestionID
+			copied.CreatedAt = s.now()
+			s.attachments = append(s.attachments, copied)
+		}
+	}
+
+	if includeTags {
+		for _, tagID := range s.quizTagIDs(id) {
+			s.quizTags = append(s.quizTags, &models.QuizTag{QuizID: newQuizID, TagID: tagID})
+		}
+	}
+
+	return newQuizID, nil
+}
+
+// nilIfEmpty returns nil for an empty slice, like the SQL repositories for an empty result
+func nilIfEmpty[T any](s []T) []T {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"quiz-log/models"
+)
+
+type seedQuestion struct {
+	questionType string
+	content      string
+	options      []string
+	answer       string
+	explanation  string
+	difficulty   string
+	tags         []string
+	format       string
+	grading      string
+}
+
+type seedQuiz struct {
+	title       string
+	description string
+	status      string
+	template    bool
+	tags        []string
+	questions   []seedQuestion
+}
+
+var demoQuizzes = []seedQuiz{
+	{
+		title:       "European Capitals",
+		description: "Match the countries of Europe with their capitals.",
+		status:      "PUBLISHED",
+		tags:        []string{"geography"},
+		questions: []seedQuestion{
+			{"MULTIPLE_CHOICE", "What is the capital of France?", []string{"Paris", "Lyon", "Marseille"}, "Paris", "Paris has been the capital since 987.", "EASY", []string{"geography", "europe"}, "PLAIN", "EXACT"},
+			{"MULTIPLE_CHOICE", "What is the capital of Switzerland?", []string{"Zurich", "Geneva", "Bern"}, "Bern", "Bern is the federal city, Zurich the largest one.", "MEDIUM", []string{"geography", "europe"}, "PLAIN", "EXACT"},
+			{"TRUE_FALSE", "Madrid is the capital of Portugal.", nil, "false", "Lisbon is the capital of Portugal.", "EASY", []string{"geography", "europe"}, "PLAIN", "EXACT"},
+			{"SHORT_ANSWER", "What is the capital of Norway?", nil, "Oslo", "", "MEDIUM", []string{"geography", "europe"}, "PLAIN", "EXACT"},
+		},
+	},
+	{
+		title:       "Basic Science",
+		description: "A few questions on physics and chemistry.",
+		status:      "PUBLISHED",
+		tags:        []string{"science"},
+		questions: []seedQuestion{
+			{"MULTIPLE_CHOICE", "What is the chemical symbol of gold?", []string{"Au", "Ag", "Gd"}, "Au", "From the Latin *aurum*.", "EASY", []string{"science", "chemistry"}, "MARKDOWN", "EXACT"},
+			{"TRUE_FALSE", "Sound travels faster than light.", nil, "false", "Light is about a million times faster.", "EASY", []string{"science", "physics"}, "PLAIN", "EXACT"},
+			{"SHORT_ANSWER", "How many protons does a carbon atom $^{12}_{6}\\mathrm{C}$ have?", nil, "6", "", "HARD", []string{"science", "chemistry"}, "MARKDOWN", "EXACT"},
+		},
+	},
+	{
+		title:       "Networking Basics",
+		description: "How machines find and talk to each other.",
+		status:      "PUBLISHED",
+		tags:        []string{"networking"},
+		questions: []seedQuestion{
+			{"ORDERING", "Order the steps of the TCP handshake.", []string{"ACK", "SYN", "SYN-ACK"}, "1,2,0", "The client sends SYN, the server answers with SYN-ACK and the client confirms with ACK.", "MEDIUM", []string{"networking"}, "PLAIN", "PARTIAL"},
+			{"TRUE_FALSE", "UDP delivers packets in the order they were sent.", nil, "false", "Ordering is left to the application, unlike with TCP.", "EASY", []string{"networking"}, "PLAIN", "EXACT"},
+		},
+	},
+	{
+		title:       "World History",
+		description: "Work in progress, not published yet.",
+		status:      "DRAFT",
+		tags:        []string{"history"},
+		questions: []seedQuestion{
+			{"SHORT_ANSWER", "In which year did the Berlin Wall fall?", nil, "1989", "", "MEDIUM", []string{"history"}, "PLAIN", "EXACT"},
+		},
+	},
+	{
+		title:       "Vocabulary Template",
+		description: "Copy this template to start a vocabulary quiz.",
+		status:      "DRAFT",
+		template:    true,
+		questions: []seedQuestion{
+			{"SHORT_ANSWER", "Translate: house", nil, "casa", "", "EASY", nil, "PLAIN", "EXACT"},
+		},
+	},
+}
+
+// demoTagParents nests the seeded tags, by name
+var demoTagParents = map[string]string{
+	"europe":    "geography",
+	"chemistry": "science",
+	"physics":   "science",
+}
+
+// Seed fills the store with sample quizzes for the demo mode. Every published quiz gets
+// one attempt missing its last question, so that the review and statistics have data.
+func Seed(ctx context.Context, store *Store) error {
+	quizRepo := NewQuizRepository(store)
+	questionRepo := NewQuestionRepository(store)
+	tagRepo := NewTagRepository(store)
+	attemptRepo := NewAttemptRepository(store)
+
+	tagIDs := func(names []string) ([]string, error) {
+		var ids []string
+		for _, name := range names {
+			id, err := tagRepo.Create(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, strconv.Itoa(id))
+		}
+		return ids, nil
+	}
+
+	for _, quiz := range demoQuizzes {
+		description := quiz.description
+		quizID, err := quizRepo.Create(ctx, quiz.title, &description)
+		if err != nil {
+			return err
+		}
+
+		ids, err := tagIDs(quiz.tags)
+		if err != nil {
+			return err
+		}
+		if err := quizRepo.AssociateTags(ctx, quizID, ids); err != nil {
+			return err
+		}
+
+		var questionIDs []int
+		for _, q := range quiz.questions {
+			var explanation *string
+			if q.explanation != "" {
+				explanation = &q.explanation
+			}
+
+			questionID, err := questionRepo.Create(ctx, q.questionType, q.content, q.format, q.options, models.Payload{}, q.answer, q.grading, explanation, q.difficulty)
+			if err != nil {
+				return err
+			}
+			questionIDs = append(questionIDs, questionID)
+
+			ids, err := tagIDs(q.tags)
+			if err != nil {
+				return err
+			}
+			if err := questionRepo.AssociateTags(ctx, questionID, ids); err != nil {
+				return err
+			}
+			if err := questionRepo.AddToQuiz(ctx, quizID, questionID, 1); err != nil {
+				return err
+			}
+		}
+
+		if err := quizRepo.UpdateStatus(ctx, quizID, quiz.status); err != nil {
+			return err
+		}
+		if err := quizRepo.SetTemplate(ctx, quizID, quiz.template); err != nil {
+			return err
+		}
+
+		if quiz.status != "PUBLISHED" {
+			continue
+		}
+
+		completedAt := store.now()
+		attemptID, err := attemptRepo.Create(ctx, quizID, completedAt.Add(-5*time.Minute), completedAt, len(questionIDs)-1, len(questionIDs))
+		if err != nil {
+			return err
+		}
+		for i, questionID := range questionIDs {
+			answer, credit := quiz.questions[i].answer, 1.0
+			if i == len(questionIDs)-1 {
+				answer, credit = "I don't know", 0
+			}
+			if err := attemptRepo.CreateAnswer(ctx, attemptID, questionID, answer, credit == 1, credit, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	for name, parent := range demoTagParents {
+		childID, err := tagRepo.Create(ctx, name)
+		if err != nil {
+			return err
+		}
+		parentID, err := tagRepo.Create(ctx, parent)
+		if err != nil {
+			return err
+		}
+		if err := tagRepo.SetParent(ctx, childID, &parentID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
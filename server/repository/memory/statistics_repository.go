@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"quiz-log/repository"
+)
+
+type statisticsRepository struct {
+	store *Store
+}
+
+// NewStatisticsRepository creates a StatisticsRepository on the store
+func NewStatisticsRepository(store *Store) repository.StatisticsRepository {
+	return &statisticsRepository{store: store}
+}
+
+func (r *statisticsRepository) CountTotalAttempts(ctx context.Context) (int, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.attempts), nil
+}
+
+func (r *statisticsRepository) CalculateAverageScore(ctx context.Context) (float64, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var sum float64
+	count := 0
+	for _, a := range s.attempts {
+		if a.TotalQuestions > 0 {
+			sum += float64(a.Score) / float64(a.TotalQuestions) * 100
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+	return sum / float64(count), nil
+}
+
+func (r *statisticsRepository) GetCategoryStats(ctx context.Context, rollUp bool) ([]*repository.CategoryStat, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Collect the answers counting for each tag, once per tag like the SQL join
+	answersByTag := make(map[int]map[int]bool)
+	for _, qt := range s.questionTags {
+		tagIDs := []int{qt.TagID}
+		if rollUp {
+			tagIDs = s.ancestorsOf(qt.TagID)
+		}
+
+		for _, a := range s.answers {
+			if a.QuestionID == nil || *a.QuestionID != qt.QuestionID {
+				continue
+			}
+			for _, tagID := range tagIDs {
+				if answersByTag[tagID] == nil {
+					answersByTag[tagID] = make(map[int]bool)
+				}
+				answersByTag[tagID][a.ID] = a.IsCorrect
+			}
+		}
+	}
+
+	var stats []*repository.CategoryStat
+	for tagID, answers := range answersByTag {
+		correct := 0
+		for _, isCorrect := range answers {
+			if isCorrect {
+				correct++
+			}
+		}
+		stats = append(stats, &repository.CategoryStat{
+			TagName:        s.tag(tagID).Name,
+			CorrectRate:    float64(correct) / float64(len(answers)) * 100,
+			TotalQuestions: len(answers),
+		})
+	}
+	slices.SortFunc(stats, func(a, b *repository.CategoryStat) int { return compareStrings(a.TagName, b.TagName) })
+	return stats, nil
+}
+
+// ancestorsOf returns a tag followed by its ancestors up to the top level
+func (s *Store) ancestorsOf(tagID int) []int {
+	ids := []int{tagID}
+	for t := s.tag(tagID); t.ParentID != nil && !slices.Contains(ids, *t.ParentID); t = s.tag(*t.ParentID) {
+		ids = append(ids, *t.ParentID)
+	}
+	return ids
+}
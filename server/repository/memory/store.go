@@ -0,0 +1,259 @@
+// Package memory implements the repositories in memory, for fast service tests and the demo mode.
+// They follow the SQL implementations, including cascading deletes and foreign key checks,
+// which the contract tests in repositorytest verify.
+package memory
+
+import (
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"quiz-log/apperrors"
+	"quiz-log/models"
+)
+
+// Store holds the tables shared by the in-memory repositories
+type Store struct {
+	mu  sync.RWMutex
+	now func() time.Time
+
+	quizzes       []*models.Quiz
+	questions     []*models.Question
+	tags          []*models.Tag
+	attempts      []*models.Attempt
+	answers       []*models.Answer
+	quizTags      []*models.QuizTag
+	questionTags  []*models.QuestionTag
+	quizQuestions []*models.QuizQuestion
+	attachments   []*models.Attachment
+
+	// lastID is the sequence of each table, like SERIAL columns IDs are never reused
+	lastID map[string]int
+}
+
+// NewStore creates an empty Store
+func NewStore() *Store {
+	return &Store{
+		now:    time.Now,
+		lastID: make(map[string]int),
+	}
+}
+
+func (s *Store) nextID(table string) int {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// errForeignKey mirrors the foreign key violation of the SQL schema
+func errForeignKey() error {
+	return apperrors.InvalidArgument("referenced record does not exist")
+}
+
+// errUniqueKey mirrors the unique violation of the SQL schema
+func errUniqueKey() error {
+	return apperrors.Conflict("record already exists")
+}
+
+func (s *Store) quiz(id int) *models.Quiz {
+	for _, q := range s.quizzes {
+		if q.ID == id {
+			return q
+		}
+	}
+	return nil
+}
+
+func (s *Store) question(id int) *models.Question {
+	for _, q := range s.questions {
+		if q.ID == id {
+			return q
+		}
+	}
+	return nil
+}
+
+func (s *Store) tag(id int) *models.Tag {
+	for _, t := range s.tags {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Store) attempt(id int) *models.Attempt {
+	for _, a := range s.attempts {
+		if a.ID == id {
+			return a
+		}
+	}
+	return nil
+}
+
+// parseTagIDs converts tag IDs like the SQL repositories, checking that every tag exists
+func (s *Store) parseTagIDs(tagIDs []string) ([]int, error) {
+	ids := make([]int, len(tagIDs))
+	for i, tagID := range tagIDs {
+		id, err := strconv.Atoi(tagID)
+		if err != nil {
+			return nil, apperrors.InvalidArgument("invalid tag ID %q", tagID)
+		}
+		if s.tag(id) == nil {
+			return nil, errForeignKey()
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+// deleteQuizCascade removes a quiz with the rows referencing it
+func (s *Store) deleteQuizCascade(id int) {
+	s.quizzes = slices.DeleteFunc(s.quizzes, func(q *models.Quiz) bool { return q.ID == id })
+	s.quizTags = slices.DeleteFunc(s.quizTags, func(qt *models.QuizTag) bool { return qt.QuizID == id })
+	s.quizQuestions = slices.DeleteFunc(s.quizQuestions, func(qq *models.QuizQuestion) bool { return qq.QuizID == id })
+
+	var attemptIDs []int
+	s.attempts = slices.DeleteFunc(s.attempts, func(a *models.Attempt) bool {
+		if a.QuizID != nil && *a.QuizID == id {
+			attemptIDs = append(attemptIDs, a.ID)
+			return true
+		}
+		return false
+	})
+	s.answers = slices.DeleteFunc(s.answers, func(a *models.Answer) bool {
+		return a.AttemptID != nil && slices.Contains(attemptIDs, *a.AttemptID)
+	})
+}
+
+// deleteQuestionCascade removes a question with the rows referencing it, orphaning its attachments
+func (s *Store) deleteQuestionCascade(id int) {
+	s.questions = slices.DeleteFunc(s.questions, func(q *models.Question) bool { return q.ID == id })
+	s.questionTags = slices.DeleteFunc(s.questionTags, func(qt *models.QuestionTag) bool { return qt.QuestionID == id })
+	s.quizQuestions = slices.DeleteFunc(s.quizQuestions, func(qq *models.QuizQuestion) bool { return qq.QuestionID == id })
+	s.answers = slices.DeleteFunc(s.answers, func(a *models.Answer) bool { return a.QuestionID != nil && *a.QuestionID == id })
+	for _, a := range s.attachments {
+		if a.QuestionID != nil && *a.QuestionID == id {
+			a.QuestionID = nil
+		}
+	}
+}
+
+// deleteTagCascade removes a tag with its associations, leaving the children pointing to it without a parent
+func (s *Store) deleteTagCascade(id int) {
+	s.tags = slices.DeleteFunc(s.tags, func(t *models.Tag) bool { return t.ID == id })
+	s.quizTags = slices.DeleteFunc(s.quizTags, func(qt *models.QuizTag) bool { return qt.TagID == id })
+	s.questionTags = slices.DeleteFunc(s.questionTags, func(qt *models.QuestionTag) bool { return qt.TagID == id })
+	for _, t := range s.tags {
+		if t.ParentID != nil && *t.ParentID == id {
+			t.ParentID = nil
+		}
+	}
+}
+
+// questionsOfQuiz returns copies of the questions of a quiz in quiz order, with their membership
+func (s *Store) questionsOfQuiz(quizID int) []*models.Question {
+	var memberships []*models.QuizQuestion
+	for _, qq := range s.quizQuestions {
+		if qq.QuizID == quizID {
+			memberships = append(memberships, qq)
+		}
+	}
+	slices.SortFunc(memberships, func(a, b *models.QuizQuestion) int {
+		if a.Position != b.Position {
+			return a.Position - b.Position
+		}
+		return a.QuestionID - b.QuestionID
+	})
+
+	questions := make([]*models.Question, 0, len(memberships))
+	for _, qq := range memberships {
+		q := copyQuestion(s.question(qq.QuestionID))
+		q.QuizID = &qq.QuizID
+		q.Position = &qq.Position
+		q.Points = &qq.Points
+		questions = append(questions, q)
+	}
+	return questions
+}
+
+// tagsOf returns copies of the tags with the given IDs ordered by name
+func (s *Store) tagsOf(ids []int) []*models.Tag {
+	var tags []*models.Tag
+	for _, id := range ids {
+		tags = append(tags, copyTag(s.tag(id)))
+	}
+	slices.SortFunc(tags, func(a, b *models.Tag) int { return compareStrings(a.Name, b.Name) })
+	return tags
+}
+
+func copyQuiz(q *models.Quiz) *models.Quiz {
+	c := *q
+	if q.Description != nil {
+		d := *q.Description
+		c.Description = &d
+	}
+	return &c
+}
+
+func copyTag(t *models.Tag) *models.Tag {
+	c := *t
+	if t.ParentID != nil {
+		p := *t.ParentID
+		c.ParentID = &p
+	}
+	return &c
+}
+
+func copyQuestion(q *models.Question) *models.Question {
+	c := *q
+	c.Options = slices.Clone(q.Options)
+	c.Payload = copyPayload(q.Payload)
+	if q.Explanation != nil {
+		e := *q.Explanation
+		c.Explanation = &e
+	}
+	c.QuizID, c.Position, c.Points = nil, nil, nil
+	return &c
+}
+
+func copyPayload(p models.Payload) models.Payload {
+	c := models.Payload{Pairs: slices.Clone(p.Pairs)}
+	if p.Numeric != nil {
+		n := *p.Numeric
+		n.Units = slices.Clone(p.Numeric.Units)
+		c.Numeric = &n
+	}
+	for _, blank := range p.Blanks {
+		c.Blanks = append(c.Blanks, models.ClozeBlank{Accepted: slices.Clone(blank.Accepted), Strategy: blank.Strategy})
+	}
+	return c
+}
+
+func copyAttachment(a *models.Attachment) *models.Attachment {
+	c := *a
+	if a.QuestionID != nil {
+		id := *a.QuestionID
+		c.QuestionID = &id
+	}
+	return &c
+}
+
+func copyAttempt(a *models.Attempt) *models.Attempt {
+	c := *a
+	if a.CompletedAt != nil {
+		t := *a.CompletedAt
+		c.CompletedAt = &t
+	}
+	return &c
+}
+
+func compareStrings(a, b string) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,283 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"quiz-log/apperrors"
+	"quiz-log/models"
+	"quiz-log/repository"
+)
+
+type tagRepository struct {
+	store *Store
+}
+
+// NewTagRepository creates a TagRepository on the store
+func NewTagRepository(store *Store) repository.TagRepository {
+	return &tagRepository{store: store}
+}
+
+func (r *tagRepository) Create(ctx context.Context, name string) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.tags {
+		if t.Name == name {
+			return t.ID, nil
+		}
+	}
+
+	t := &models.Tag{ID: s.nextID("tags"), Name: name}
+	s.tags = append(s.tags, t)
+	return t.ID, nil
+}
+
+func (r *tagRepository) CreateWithParent(ctx context.Context, name string, parentID int) (int, error) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tag(parentID) == nil {
+		return 0, errForeignKey()
+	}
+	if slices.ContainsFunc(s.tags, func(t *models.Tag) bool { return t.Name == name }) {
+		return 0, errUniqueKey()
+	}
+
+	t := &models.Tag{ID: s.nextID("tags"), Name: name, ParentID: &parentID}
+	s.tags = append(s.tags, t)
+	return t.ID, nil
+}
+
+func (r *tagRepository) FindAll(ctx context.Context) ([]*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tags []*models.Tag
+	for _, t := range s.tags {
+		tags = append(tags, copyTag(t))
+	}
+	slices.SortFunc(tags, func(a, b *models.Tag) int { return compareStrings(a.Name, b.Name) })
+	return tags, nil
+}
+
+func (r *tagRepository) FindByID(ctx context.Context, id int) (*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t := s.tag(id)
+	if t == nil {
+		return nil, nil
+	}
+	return copyTag(t), nil
+}
+
+func (r *tagRepository) FindByIDs(ctx context.Context, ids []int) ([]*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var tags []*models.Tag
+	for _, t := range s.tags {
+		if slices.Contains(ids, t.ID) {
+			tags = append(tags, copyTag(t))
+		}
+	}
+	slices.SortFunc(tags, func(a, b *models.Tag) int { return a.ID - b.ID })
+	return tags, nil
+}
+
+func (r *tagRepository) FindByParentIDs(ctx context.Context, parentIDs []int) (map[int][]*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int][]*models.Tag)
+	for _, t := range s.tags {
+		if t.ParentID != nil && slices.Contains(parentIDs, *t.ParentID) {
+			result[*t.ParentID] = append(result[*t.ParentID], copyTag(t))
+		}
+	}
+	for _, tags := range result {
+		slices.SortFunc(tags, func(a, b *models.Tag) int { return compareStrings(a.Name, b.Name) })
+	}
+	return result, nil
+}
+
+func (r *tagRepository) Rename(ctx context.Context, id int, name string) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tag(id)
+	if t == nil {
+		return nil
+	}
+
+	if slices.ContainsFunc(s.tags, func(other *models.Tag) bool { return other.ID != id && other.Name == name }) {
+		return errUniqueKey()
+	}
+
+	t.Name = name
+	return nil
+}
+
+func (r *tagRepository) SetParent(ctx context.Context, id int, parentID *int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tag(id)
+	if t == nil {
+		return nil
+	}
+
+	if parentID == nil {
+		t.ParentID = nil
+		return nil
+	}
+	if s.tag(*parentID) == nil {
+		return errForeignKey()
+	}
+
+	// Walking up from the parent reaches the tag when the move would make a loop
+	for ancestor, seen := s.tag(*parentID), 0; ancestor != nil && seen <= len(s.tags); seen++ {
+		if ancestor.ID == id {
+			return apperrors.InvalidArgument("tag %d cannot be moved under itself or one of its descendants", id)
+		}
+		if ancestor.ParentID == nil {
+			break
+		}
+		ancestor = s.tag(*ancestor.ParentID)
+	}
+
+	p := *parentID
+	t.ParentID = &p
+	return nil
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id int) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tag(id)
+	if t == nil {
+		return nil
+	}
+
+	for _, child := range s.tags {
+		if child.ParentID != nil && *child.ParentID == id {
+			child.ParentID = t.ParentID
+		}
+	}
+	s.deleteTagCascade(id)
+	return nil
+}
+
+func (r *tagRepository) Merge(ctx context.Context, sourceIDs []int, targetID int) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tag(targetID) == nil {
+		return errForeignKey()
+	}
+
+	isSource := func(tagID int) bool { return slices.Contains(sourceIDs, tagID) }
+	for _, qt := range slices.Clone(s.quizTags) {
+		if isSource(qt.TagID) && !slices.Contains(s.quizTagIDs(qt.QuizID), targetID) {
+			s.quizTags = append(s.quizTags, &models.QuizTag{QuizID: qt.QuizID, TagID: targetID})
+		}
+	}
+	for _, qt := range slices.Clone(s.questionTags) {
+		if isSource(qt.TagID) && !slices.Contains(s.questionTagIDs(qt.QuestionID), targetID) {
+			s.questionTags = append(s.questionTags, &models.QuestionTag{QuestionID: qt.QuestionID, TagID: targetID})
+		}
+	}
+	for _, t := range s.tags {
+		if t.ParentID != nil && isSource(*t.ParentID) {
+			p := targetID
+			t.ParentID = &p
+		}
+	}
+
+	for _, id := range sourceIDs {
+		s.deleteTagCascade(id)
+	}
+	return nil
+}
+
+func (r *tagRepository) FindUsageByTagIDs(ctx context.Context, tagIDs []int) (map[int]*repository.TagUsage, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[int]*repository.TagUsage)
+	for _, id := range tagIDs {
+		if s.tag(id) != nil {
+			result[id] = s.tagUsage(id)
+		}
+	}
+	return result, nil
+}
+
+func (s *Store) tagUsage(tagID int) *repository.TagUsage {
+	usage := &repository.TagUsage{TagID: tagID}
+	used := func(t time.Time) {
+		if usage.LastUsedAt == nil || t.After(*usage.LastUsedAt) {
+			usage.LastUsedAt = &t
+		}
+	}
+
+	for _, qt := range s.quizTags {
+		if qt.TagID == tagID {
+			usage.QuizCount++
+			used(s.quiz(qt.QuizID).UpdatedAt)
+		}
+	}
+	for _, qt := range s.questionTags {
+		if qt.TagID == tagID {
+			usage.QuestionCount++
+			used(s.question(qt.QuestionID).UpdatedAt)
+		}
+	}
+	return usage
+}
+
+func (r *tagRepository) FindByPrefix(ctx context.Context, prefix string, limit int) ([]*models.Tag, error) {
+	s := r.store
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prefix = strings.ToLower(prefix)
+	uses := make(map[int]int)
+	var tags []*models.Tag
+	for _, t := range s.tags {
+		if strings.HasPrefix(strings.ToLower(t.Name), prefix) {
+			usage := s.tagUsage(t.ID)
+			uses[t.ID] = usage.QuizCount + usage.QuestionCount
+			tags = append(tags, copyTag(t))
+		}
+	}
+	slices.SortFunc(tags, func(a, b *models.Tag) int {
+		if uses[a.ID] != uses[b.ID] {
+			return uses[b.ID] - uses[a.ID]
+		}
+		return compareStrings(a.Name, b.Name)
+	})
+	if len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags, nil
+}
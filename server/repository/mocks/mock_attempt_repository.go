@@ -11,7 +11,7 @@ package mocks
 
 import (
 	context "context"
-	db "quiz-log/models"
+	models "quiz-log/models"
 	reflect "reflect"
 	time "time"
 
@@ -73,24 +73,39 @@ func (mr *MockAttemptRepositoryMockRecorder) Create(ctx, quizID, startedAt, comp
 }
 
 // CreateAnswer mocks base method.
-func (m *MockAttemptRepository) CreateAnswer(ctx context.Context, attemptID, questionID int, userAnswer string, isCorrect bool) error {
+func (m *MockAttemptRepository) CreateAnswer(ctx context.Context, attemptID, questionID int, userAnswer string, isCorrect bool, credit float64, blanks models.Blanks) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAnswer", ctx, attemptID, questionID, userAnswer, isCorrect)
+	ret := m.ctrl.Call(m, "CreateAnswer", ctx, attemptID, questionID, userAnswer, isCorrect, credit, blanks)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateAnswer indicates an expected call of CreateAnswer.
-func (mr *MockAttemptRepositoryMockRecorder) CreateAnswer(ctx, attemptID, questionID, userAnswer, isCorrect any) *gomock.Call {
+func (mr *MockAttemptRepositoryMockRecorder) CreateAnswer(ctx, attemptID, questionID, userAnswer, isCorrect, credit, blanks any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnswer", reflect.TypeOf((*MockAttemptRepository)(nil).CreateAnswer), ctx, attemptID, questionID, userAnswer, isCorrect)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAnswer", reflect.TypeOf((*MockAttemptRepository)(nil).CreateAnswer), ctx, attemptID, questionID, userAnswer, isCorrect, credit, blanks)
+}
+
+// CreateWithAnswers mocks base method.
+func (m *MockAttemptRepository) CreateWithAnswers(ctx context.Context, quizID int, startedAt, completedAt time.Time, score, totalQuestions int, answers []*models.Answer) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithAnswers", ctx, quizID, startedAt, completedAt, score, totalQuestions, answers)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithAnswers indicates an expected call of CreateWithAnswers.
+func (mr *MockAttemptRepositoryMockRecorder) CreateWithAnswers(ctx, quizID, startedAt, completedAt, score, totalQuestions, answers any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithAnswers", reflect.TypeOf((*MockAttemptRepository)(nil).CreateWithAnswers), ctx, quizID, startedAt, completedAt, score, totalQuestions, answers)
 }
 
 // FindAll mocks base method.
-func (m *MockAttemptRepository) FindAll(ctx context.Context, quizID *int) ([]*db.Attempt, error) {
+func (m *MockAttemptRepository) FindAll(ctx context.Context, quizID *int) ([]*models.Attempt, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FindAll", ctx, quizID)
-	ret0, _ := ret[0].([]*db.Attempt)
+	ret0, _ := ret[0].([]*models.Attempt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -102,10 +117,10 @@ func (mr *MockAttemptRepositoryMockRecorder) FindAll(ctx, quizID any) *gomock.Ca
 }
 
 // FindAnswersByAttemptID mocks base method.
-func (m *MockAttemptRepository) FindAnswersByAttemptID(ctx context.Context, attemptID int) ([]*db.Answer, error) {
+func (m *MockAttemptRepository) FindAnswersByAttemptID(ctx context.Context, attemptID int) ([]*models.Answer, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FindAnswersByAttemptID", ctx, attemptID)
-	ret0, _ := ret[0].([]*db.Answer)
+	ret0, _ := ret[0].([]*models.Answer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -116,11 +131,26 @@ func (mr *MockAttemptRepositoryMockRecorder) FindAnswersByAttemptID(ctx, attempt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAnswersByAttemptID", reflect.TypeOf((*MockAttemptRepository)(nil).FindAnswersByAttemptID), ctx, attemptID)
 }
 
+// FindAnswersByAttemptIDs mocks base method.
+func (m *MockAttemptRepository) FindAnswersByAttemptIDs(ctx context.Context, attemptIDs []int) (map[int][]*models.Answer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAnswersByAttemptIDs", ctx, attemptIDs)
+	ret0, _ := ret[0].(map[int][]*models.Answer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAnswersByAttemptIDs indicates an expected call of FindAnswersByAttemptIDs.
+func (mr *MockAttemptRepositoryMockRecorder) FindAnswersByAttemptIDs(ctx, attemptIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAnswersByAttemptIDs", reflect.TypeOf((*MockAttemptRepository)(nil).FindAnswersByAttemptIDs), ctx, attemptIDs)
+}
+
 // FindByID mocks base method.
-func (m *MockAttemptRepository) FindByID(ctx context.Context, attemptID int) (*db.Attempt, error) {
+func (m *MockAttemptRepository) FindByID(ctx context.Context, attemptID int) (*models.Attempt, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FindByID", ctx, attemptID)
-	ret0, _ := ret[0].(*db.Attempt)
+	ret0, _ := ret[0].(*models.Attempt)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -131,6 +161,36 @@ func (mr *MockAttemptRepositoryMockRecorder) FindByID(ctx, attemptID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockAttemptRepository)(nil).FindByID), ctx, attemptID)
 }
 
+// FindByQuizIDs mocks base method.
+func (m *MockAttemptRepository) FindByQuizIDs(ctx context.Context, quizIDs []int) (map[int][]*models.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByQuizIDs", ctx, quizIDs)
+	ret0, _ := ret[0].(map[int][]*models.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByQuizIDs indicates an expected call of FindByQuizIDs.
+func (mr *MockAttemptRepositoryMockRecorder) FindByQuizIDs(ctx, quizIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByQuizIDs", reflect.TypeOf((*MockAttemptRepository)(nil).FindByQuizIDs), ctx, quizIDs)
+}
+
+// FindQuizStatus mocks base method.
+func (m *MockAttemptRepository) FindQuizStatus(ctx context.Context, quizID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindQuizStatus", ctx, quizID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindQuizStatus indicates an expected call of FindQuizStatus.
+func (mr *MockAttemptRepositoryMockRecorder) FindQuizStatus(ctx, quizID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindQuizStatus", reflect.TypeOf((*MockAttemptRepository)(nil).FindQuizStatus), ctx, quizID)
+}
+
 // GetCorrectAnswer mocks base method.
 func (m *MockAttemptRepository) GetCorrectAnswer(ctx context.Context, questionID int) (string, error) {
 	m.ctrl.T.Helper()
@@ -11,7 +11,7 @@ package mocks
 
 import (
 	context "context"
-	"quiz-log/models"
+	models "quiz-log/models"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -41,6 +41,20 @@ func (m *MockQuestionRepository) EXPECT() *MockQuestionRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddToQuiz mocks base method.
+func (m *MockQuestionRepository) AddToQuiz(ctx context.Context, quizID, questionID, points int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToQuiz", ctx, quizID, questionID, points)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToQuiz indicates an expected call of AddToQuiz.
+func (mr *MockQuestionRepositoryMockRecorder) AddToQuiz(ctx, quizID, questionID, points any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToQuiz", reflect.TypeOf((*MockQuestionRepository)(nil).AddToQuiz), ctx, quizID, questionID, points)
+}
+
 // AssociateTags mocks base method.
 func (m *MockQuestionRepository) AssociateTags(ctx context.Context, questionID int, tagIDs []string) error {
 	m.ctrl.T.Helper()
@@ -55,6 +69,49 @@ func (mr *MockQuestionRepositoryMockRecorder) AssociateTags(ctx, questionID, tag
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssociateTags", reflect.TypeOf((*MockQuestionRepository)(nil).AssociateTags), ctx, questionID, tagIDs)
 }
 
+// BulkDelete mocks base method.
+func (m *MockQuestionRepository) BulkDelete(ctx context.Context, ids []int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkDelete", ctx, ids)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkDelete indicates an expected call of BulkDelete.
+func (mr *MockQuestionRepositoryMockRecorder) BulkDelete(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkDelete", reflect.TypeOf((*MockQuestionRepository)(nil).BulkDelete), ctx, ids)
+}
+
+// BulkTag mocks base method.
+func (m *MockQuestionRepository) BulkTag(ctx context.Context, ids, addTagIDs, removeTagIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkTag", ctx, ids, addTagIDs, removeTagIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkTag indicates an expected call of BulkTag.
+func (mr *MockQuestionRepositoryMockRecorder) BulkTag(ctx, ids, addTagIDs, removeTagIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkTag", reflect.TypeOf((*MockQuestionRepository)(nil).BulkTag), ctx, ids, addTagIDs, removeTagIDs)
+}
+
+// BulkUpdate mocks base method.
+func (m *MockQuestionRepository) BulkUpdate(ctx context.Context, ids []int, explanation, difficulty *string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpdate", ctx, ids, explanation, difficulty)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BulkUpdate indicates an expected call of BulkUpdate.
+func (mr *MockQuestionRepositoryMockRecorder) BulkUpdate(ctx, ids, explanation, difficulty any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdate", reflect.TypeOf((*MockQuestionRepository)(nil).BulkUpdate), ctx, ids, explanation, difficulty)
+}
+
 // ClearTags mocks base method.
 func (m *MockQuestionRepository) ClearTags(ctx context.Context, questionID int) error {
 	m.ctrl.T.Helper()
@@ -70,18 +127,33 @@ func (mr *MockQuestionRepositoryMockRecorder) ClearTags(ctx, questionID any) *go
 }
 
 // Create mocks base method.
-func (m *MockQuestionRepository) Create(ctx context.Context, quizID, questionType, content string, options []string, correctAnswer string, explanation *string, difficulty string) (int, error) {
+func (m *MockQuestionRepository) Create(ctx context.Context, questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, quizID, questionType, content, options, correctAnswer, explanation, difficulty)
+	ret := m.ctrl.Call(m, "Create", ctx, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockQuestionRepositoryMockRecorder) Create(ctx, quizID, questionType, content, options, correctAnswer, explanation, difficulty any) *gomock.Call {
+func (mr *MockQuestionRepositoryMockRecorder) Create(ctx, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuestionRepository)(nil).Create), ctx, quizID, questionType, content, options, correctAnswer, explanation, difficulty)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockQuestionRepository)(nil).Create), ctx, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty)
+}
+
+// CreateWithLinks mocks base method.
+func (m *MockQuestionRepository) CreateWithLinks(ctx context.Context, questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string, tagIDs []string, quizID *int, points int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithLinks", ctx, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty, tagIDs, quizID, points)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithLinks indicates an expected call of CreateWithLinks.
+func (mr *MockQuestionRepositoryMockRecorder) CreateWithLinks(ctx, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty, tagIDs, quizID, points any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithLinks", reflect.TypeOf((*MockQuestionRepository)(nil).CreateWithLinks), ctx, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty, tagIDs, quizID, points)
 }
 
 // Delete mocks base method.
@@ -128,6 +200,36 @@ func (mr *MockQuestionRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockQuestionRepository)(nil).FindByID), ctx, id)
 }
 
+// FindByIDs mocks base method.
+func (m *MockQuestionRepository) FindByIDs(ctx context.Context, ids []int) ([]*models.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]*models.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockQuestionRepositoryMockRecorder) FindByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockQuestionRepository)(nil).FindByIDs), ctx, ids)
+}
+
+// FindTaggedByKeywords mocks base method.
+func (m *MockQuestionRepository) FindTaggedByKeywords(ctx context.Context, keywords []string, limit int) ([]*models.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTaggedByKeywords", ctx, keywords, limit)
+	ret0, _ := ret[0].([]*models.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTaggedByKeywords indicates an expected call of FindTaggedByKeywords.
+func (mr *MockQuestionRepositoryMockRecorder) FindTaggedByKeywords(ctx, keywords, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTaggedByKeywords", reflect.TypeOf((*MockQuestionRepository)(nil).FindTaggedByKeywords), ctx, keywords, limit)
+}
+
 // FindTagsByQuestionID mocks base method.
 func (m *MockQuestionRepository) FindTagsByQuestionID(ctx context.Context, questionID int) ([]*models.Tag, error) {
 	m.ctrl.T.Helper()
@@ -143,6 +245,21 @@ func (mr *MockQuestionRepositoryMockRecorder) FindTagsByQuestionID(ctx, question
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTagsByQuestionID", reflect.TypeOf((*MockQuestionRepository)(nil).FindTagsByQuestionID), ctx, questionID)
 }
 
+// FindTagsByQuestionIDs mocks base method.
+func (m *MockQuestionRepository) FindTagsByQuestionIDs(ctx context.Context, questionIDs []int) (map[int][]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTagsByQuestionIDs", ctx, questionIDs)
+	ret0, _ := ret[0].(map[int][]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTagsByQuestionIDs indicates an expected call of FindTagsByQuestionIDs.
+func (mr *MockQuestionRepositoryMockRecorder) FindTagsByQuestionIDs(ctx, questionIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTagsByQuestionIDs", reflect.TypeOf((*MockQuestionRepository)(nil).FindTagsByQuestionIDs), ctx, questionIDs)
+}
+
 // FindWrongQuestions mocks base method.
 func (m *MockQuestionRepository) FindWrongQuestions(ctx context.Context) ([]*models.Question, error) {
 	m.ctrl.T.Helper()
@@ -158,16 +275,58 @@ func (mr *MockQuestionRepositoryMockRecorder) FindWrongQuestions(ctx any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindWrongQuestions", reflect.TypeOf((*MockQuestionRepository)(nil).FindWrongQuestions), ctx)
 }
 
+// MoveToQuiz mocks base method.
+func (m *MockQuestionRepository) MoveToQuiz(ctx context.Context, ids []int, sourceQuizID *int, quizID, points int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToQuiz", ctx, ids, sourceQuizID, quizID, points)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveToQuiz indicates an expected call of MoveToQuiz.
+func (mr *MockQuestionRepositoryMockRecorder) MoveToQuiz(ctx, ids, sourceQuizID, quizID, points any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToQuiz", reflect.TypeOf((*MockQuestionRepository)(nil).MoveToQuiz), ctx, ids, sourceQuizID, quizID, points)
+}
+
+// RemoveFromQuiz mocks base method.
+func (m *MockQuestionRepository) RemoveFromQuiz(ctx context.Context, quizID, questionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromQuiz", ctx, quizID, questionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromQuiz indicates an expected call of RemoveFromQuiz.
+func (mr *MockQuestionRepositoryMockRecorder) RemoveFromQuiz(ctx, quizID, questionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromQuiz", reflect.TypeOf((*MockQuestionRepository)(nil).RemoveFromQuiz), ctx, quizID, questionID)
+}
+
+// Reorder mocks base method.
+func (m *MockQuestionRepository) Reorder(ctx context.Context, quizID int, orderedIDs []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, quizID, orderedIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockQuestionRepositoryMockRecorder) Reorder(ctx, quizID, orderedIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockQuestionRepository)(nil).Reorder), ctx, quizID, orderedIDs)
+}
+
 // Update mocks base method.
-func (m *MockQuestionRepository) Update(ctx context.Context, id int, questionType, content *string, options []string, correctAnswer, explanation, difficulty *string) error {
+func (m *MockQuestionRepository) Update(ctx context.Context, id int, questionType, content, contentFormat *string, options []string, payload *models.Payload, correctAnswer, grading, explanation, difficulty *string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, questionType, content, options, correctAnswer, explanation, difficulty)
+	ret := m.ctrl.Call(m, "Update", ctx, id, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockQuestionRepositoryMockRecorder) Update(ctx, id, questionType, content, options, correctAnswer, explanation, difficulty any) *gomock.Call {
+func (mr *MockQuestionRepositoryMockRecorder) Update(ctx, id, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockQuestionRepository)(nil).Update), ctx, id, questionType, content, options, correctAnswer, explanation, difficulty)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockQuestionRepository)(nil).Update), ctx, id, questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty)
 }
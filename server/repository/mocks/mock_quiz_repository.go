@@ -11,7 +11,7 @@ package mocks
 
 import (
 	context "context"
-	"quiz-log/models"
+	models "quiz-log/models"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -98,19 +98,34 @@ func (mr *MockQuizRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockQuizRepository)(nil).Delete), ctx, id)
 }
 
+// Duplicate mocks base method.
+func (m *MockQuizRepository) Duplicate(ctx context.Context, id int, title string, includeTags bool, replace func(string) string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Duplicate", ctx, id, title, includeTags, replace)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Duplicate indicates an expected call of Duplicate.
+func (mr *MockQuizRepositoryMockRecorder) Duplicate(ctx, id, title, includeTags, replace any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Duplicate", reflect.TypeOf((*MockQuizRepository)(nil).Duplicate), ctx, id, title, includeTags, replace)
+}
+
 // FindAll mocks base method.
-func (m *MockQuizRepository) FindAll(ctx context.Context) ([]*models.Quiz, error) {
+func (m *MockQuizRepository) FindAll(ctx context.Context, status string) ([]*models.Quiz, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret := m.ctrl.Call(m, "FindAll", ctx, status)
 	ret0, _ := ret[0].([]*models.Quiz)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockQuizRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
+func (mr *MockQuizRepositoryMockRecorder) FindAll(ctx, status any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockQuizRepository)(nil).FindAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockQuizRepository)(nil).FindAll), ctx, status)
 }
 
 // FindByID mocks base method.
@@ -188,6 +203,35 @@ func (mr *MockQuizRepositoryMockRecorder) FindTagsByQuizIDs(ctx, quizIDs any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTagsByQuizIDs", reflect.TypeOf((*MockQuizRepository)(nil).FindTagsByQuizIDs), ctx, quizIDs)
 }
 
+// FindTemplates mocks base method.
+func (m *MockQuizRepository) FindTemplates(ctx context.Context) ([]*models.Quiz, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTemplates", ctx)
+	ret0, _ := ret[0].([]*models.Quiz)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTemplates indicates an expected call of FindTemplates.
+func (mr *MockQuizRepositoryMockRecorder) FindTemplates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTemplates", reflect.TypeOf((*MockQuizRepository)(nil).FindTemplates), ctx)
+}
+
+// SetTemplate mocks base method.
+func (m *MockQuizRepository) SetTemplate(ctx context.Context, id int, isTemplate bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTemplate", ctx, id, isTemplate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTemplate indicates an expected call of SetTemplate.
+func (mr *MockQuizRepositoryMockRecorder) SetTemplate(ctx, id, isTemplate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTemplate", reflect.TypeOf((*MockQuizRepository)(nil).SetTemplate), ctx, id, isTemplate)
+}
+
 // Update mocks base method.
 func (m *MockQuizRepository) Update(ctx context.Context, id int, title, description *string) error {
 	m.ctrl.T.Helper()
@@ -201,3 +245,17 @@ func (mr *MockQuizRepositoryMockRecorder) Update(ctx, id, title, description any
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockQuizRepository)(nil).Update), ctx, id, title, description)
 }
+
+// UpdateStatus mocks base method.
+func (m *MockQuizRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, id, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockQuizRepositoryMockRecorder) UpdateStatus(ctx, id, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockQuizRepository)(nil).UpdateStatus), ctx, id, status)
+}
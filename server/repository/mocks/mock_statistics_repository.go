@@ -72,16 +72,16 @@ func (mr *MockStatisticsRepositoryMockRecorder) CountTotalAttempts(ctx any) *gom
 }
 
 // GetCategoryStats mocks base method.
-func (m *MockStatisticsRepository) GetCategoryStats(ctx context.Context) ([]*repository.CategoryStat, error) {
+func (m *MockStatisticsRepository) GetCategoryStats(ctx context.Context, rollUp bool) ([]*repository.CategoryStat, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCategoryStats", ctx)
+	ret := m.ctrl.Call(m, "GetCategoryStats", ctx, rollUp)
 	ret0, _ := ret[0].([]*repository.CategoryStat)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCategoryStats indicates an expected call of GetCategoryStats.
-func (mr *MockStatisticsRepositoryMockRecorder) GetCategoryStats(ctx any) *gomock.Call {
+func (mr *MockStatisticsRepositoryMockRecorder) GetCategoryStats(ctx, rollUp any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryStats", reflect.TypeOf((*MockStatisticsRepository)(nil).GetCategoryStats), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryStats", reflect.TypeOf((*MockStatisticsRepository)(nil).GetCategoryStats), ctx, rollUp)
 }
@@ -11,7 +11,8 @@ package mocks
 
 import (
 	context "context"
-	db "quiz-log/models"
+	models "quiz-log/models"
+	repository "quiz-log/repository"
 	reflect "reflect"
 
 	gomock "go.uber.org/mock/gomock"
@@ -56,11 +57,40 @@ func (mr *MockTagRepositoryMockRecorder) Create(ctx, name any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTagRepository)(nil).Create), ctx, name)
 }
 
+// CreateWithParent mocks base method.
+func (m *MockTagRepository) CreateWithParent(ctx context.Context, name string, parentID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWithParent", ctx, name, parentID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWithParent indicates an expected call of CreateWithParent.
+func (mr *MockTagRepositoryMockRecorder) CreateWithParent(ctx, name, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithParent", reflect.TypeOf((*MockTagRepository)(nil).CreateWithParent), ctx, name, parentID)
+}
+
+// Delete mocks base method.
+func (m *MockTagRepository) Delete(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTagRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTagRepository)(nil).Delete), ctx, id)
+}
+
 // FindAll mocks base method.
-func (m *MockTagRepository) FindAll(ctx context.Context) ([]*db.Tag, error) {
+func (m *MockTagRepository) FindAll(ctx context.Context) ([]*models.Tag, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "FindAll", ctx)
-	ret0, _ := ret[0].([]*db.Tag)
+	ret0, _ := ret[0].([]*models.Tag)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -70,3 +100,120 @@ func (mr *MockTagRepositoryMockRecorder) FindAll(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockTagRepository)(nil).FindAll), ctx)
 }
+
+// FindByID mocks base method.
+func (m *MockTagRepository) FindByID(ctx context.Context, id int) (*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockTagRepositoryMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockTagRepository)(nil).FindByID), ctx, id)
+}
+
+// FindByIDs mocks base method.
+func (m *MockTagRepository) FindByIDs(ctx context.Context, ids []int) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockTagRepositoryMockRecorder) FindByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockTagRepository)(nil).FindByIDs), ctx, ids)
+}
+
+// FindByParentIDs mocks base method.
+func (m *MockTagRepository) FindByParentIDs(ctx context.Context, parentIDs []int) (map[int][]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByParentIDs", ctx, parentIDs)
+	ret0, _ := ret[0].(map[int][]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByParentIDs indicates an expected call of FindByParentIDs.
+func (mr *MockTagRepositoryMockRecorder) FindByParentIDs(ctx, parentIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByParentIDs", reflect.TypeOf((*MockTagRepository)(nil).FindByParentIDs), ctx, parentIDs)
+}
+
+// FindByPrefix mocks base method.
+func (m *MockTagRepository) FindByPrefix(ctx context.Context, prefix string, limit int) ([]*models.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPrefix", ctx, prefix, limit)
+	ret0, _ := ret[0].([]*models.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPrefix indicates an expected call of FindByPrefix.
+func (mr *MockTagRepositoryMockRecorder) FindByPrefix(ctx, prefix, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPrefix", reflect.TypeOf((*MockTagRepository)(nil).FindByPrefix), ctx, prefix, limit)
+}
+
+// FindUsageByTagIDs mocks base method.
+func (m *MockTagRepository) FindUsageByTagIDs(ctx context.Context, tagIDs []int) (map[int]*repository.TagUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUsageByTagIDs", ctx, tagIDs)
+	ret0, _ := ret[0].(map[int]*repository.TagUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUsageByTagIDs indicates an expected call of FindUsageByTagIDs.
+func (mr *MockTagRepositoryMockRecorder) FindUsageByTagIDs(ctx, tagIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUsageByTagIDs", reflect.TypeOf((*MockTagRepository)(nil).FindUsageByTagIDs), ctx, tagIDs)
+}
+
+// Merge mocks base method.
+func (m *MockTagRepository) Merge(ctx context.Context, sourceIDs []int, targetID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Merge", ctx, sourceIDs, targetID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Merge indicates an expected call of Merge.
+func (mr *MockTagRepositoryMockRecorder) Merge(ctx, sourceIDs, targetID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Merge", reflect.TypeOf((*MockTagRepository)(nil).Merge), ctx, sourceIDs, targetID)
+}
+
+// Rename mocks base method.
+func (m *MockTagRepository) Rename(ctx context.Context, id int, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, id, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockTagRepositoryMockRecorder) Rename(ctx, id, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockTagRepository)(nil).Rename), ctx, id, name)
+}
+
+// SetParent mocks base method.
+func (m *MockTagRepository) SetParent(ctx context.Context, id int, parentID *int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParent", ctx, id, parentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetParent indicates an expected call of SetParent.
+func (mr *MockTagRepositoryMockRecorder) SetParent(ctx, id, parentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParent", reflect.TypeOf((*MockTagRepository)(nil).SetParent), ctx, id, parentID)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"quiz-log/telemetry"
+)
+
+// slowQueryThreshold is the duration above which statements are logged, disabled when zero
+var slowQueryThreshold atomic.Int64
+
+// SetSlowQueryThreshold sets the duration above which SQL statements are logged; zero disables the log
+func SetSlowQueryThreshold(d time.Duration) {
+	slowQueryThreshold.Store(int64(d))
+}
+
+// startQuery instruments a statement run by the query helpers.
+// Call the returned function with the statement's error once it completes.
+func startQuery(ctx context.Context, sqlStr string, args []interface{}) (context.Context, func(err error)) {
+	ctx, done := telemetry.StartQuery(ctx, sqlStr)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		done(err)
+
+		threshold := time.Duration(slowQueryThreshold.Load())
+		if duration := time.Since(start); threshold > 0 && duration >= threshold {
+			slog.WarnContext(ctx, "slow query",
+				slog.Duration("duration", duration),
+				slog.String("query", sqlStr),
+				slog.Any("args", redactArgs(args)),
+			)
+		}
+	}
+}
+
+// redactArgs describes query arguments by type only, so logs never hold user data
+func redactArgs(args []interface{}) []string {
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		v := reflect.ValueOf(arg)
+		switch {
+		case arg == nil || (v.Kind() == reflect.Pointer && v.IsNil()):
+			redacted[i] = "NULL"
+		case v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+			redacted[i] = fmt.Sprintf("%T(len=%d)", arg, v.Len())
+		default:
+			redacted[i] = fmt.Sprintf("%T", arg)
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactArgs(t *testing.T) {
+	var missing *string
+
+	// Execute
+	redacted := redactArgs([]interface{}{"secret answer", 42, nil, missing, []string{"a", "b", "c"}})
+
+	// Assert
+	expected := []string{"string", "int", "NULL", "NULL", "[]string(len=3)"}
+	for i := range expected {
+		if redacted[i] != expected[i] {
+			t.Errorf("expected arg %d to be '%s', got '%s'", i, expected[i], redacted[i])
+		}
+	}
+}
+
+func TestStartQuery_SlowQuery(t *testing.T) {
+	var buf bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	defer SetSlowQueryThreshold(0)
+
+	// Fast queries are not logged
+	SetSlowQueryThreshold(time.Hour)
+	_, done := startQuery(context.Background(), "SELECT 1", nil)
+	done(nil)
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected no log entry, got '%s'", buf.String())
+	}
+
+	// Execute
+	SetSlowQueryThreshold(time.Nanosecond)
+	_, done = startQuery(context.Background(), "SELECT id FROM quizzes WHERE title = $1", []interface{}{"private"})
+	time.Sleep(time.Millisecond)
+	done(nil)
+
+	// Assert
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if record["msg"] != "slow query" {
+		t.Errorf("expected message 'slow query', got %v", record["msg"])
+	}
+
+	if strings.Contains(buf.String(), "private") {
+		t.Errorf("expected arguments to be redacted, got '%s'", buf.String())
+	}
+}
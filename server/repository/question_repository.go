@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"quiz-log/apperrors"
 	"quiz-log/models"
 	"strconv"
+	"strings"
 
-	"github.com/lib/pq"
+	sq "github.com/Masterminds/squirrel"
 	"github.com/uptrace/bun"
 )
 
@@ -13,33 +15,42 @@ import (
 //
 //go:generate mockgen -destination=mocks/mock_question_repository.go -package=mocks quiz-log/repository QuestionRepository
 type QuestionRepository interface {
-	Create(ctx context.Context, quizID, questionType, content string, options []string, correctAnswer string, explanation *string, difficulty string) (int, error)
-	Update(ctx context.Context, id int, questionType *string, content *string, options []string, correctAnswer *string, explanation *string, difficulty *string) error
+	Create(ctx context.Context, questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string) (int, error)
+	CreateWithLinks(ctx context.Context, questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string, tagIDs []string, quizID *int, points int) (int, error)
+	Update(ctx context.Context, id int, questionType *string, content *string, contentFormat *string, options []string, payload *models.Payload, correctAnswer *string, grading *string, explanation *string, difficulty *string) error
 	Delete(ctx context.Context, id int) error
 	FindAll(ctx context.Context, quizID *int) ([]*models.Question, error)
 	FindByID(ctx context.Context, id int) (*models.Question, error)
+	FindByIDs(ctx context.Context, ids []int) ([]*models.Question, error)
 	FindWrongQuestions(ctx context.Context) ([]*models.Question, error)
 	FindTagsByQuestionID(ctx context.Context, questionID int) ([]*models.Tag, error)
+	FindTagsByQuestionIDs(ctx context.Context, questionIDs []int) (map[int][]*models.Tag, error)
+	FindTaggedByKeywords(ctx context.Context, keywords []string, limit int) ([]*models.Question, error)
 	AssociateTags(ctx context.Context, questionID int, tagIDs []string) error
 	ClearTags(ctx context.Context, questionID int) error
+	AddToQuiz(ctx context.Context, quizID, questionID int, points int) error
+	RemoveFromQuiz(ctx context.Context, quizID, questionID int) error
+	BulkUpdate(ctx context.Context, ids []int, explanation *string, difficulty *string) error
+	BulkDelete(ctx context.Context, ids []int) (int, error)
+	BulkTag(ctx context.Context, ids []int, addTagIDs, removeTagIDs []int) error
+	MoveToQuiz(ctx context.Context, ids []int, sourceQuizID *int, quizID int, points int) error
+	Reorder(ctx context.Context, quizID int, orderedIDs []int) error
 }
 
 type questionRepository struct {
-	DB *bun.DB
+	DB      *bun.DB
+	dialect Dialect
 }
 
 func NewQuestionRepository(database *bun.DB) QuestionRepository {
-	return &questionRepository{DB: database}
+	return &questionRepository{DB: database, dialect: dialectOf(database)}
 }
 
-// Create creates a new question and returns its ID
-func (r *questionRepository) Create(ctx context.Context, quizID, questionType, content string, options []string, correctAnswer string, explanation *string, difficulty string) (int, error) {
+// Create creates a new question in the question bank and returns its ID
+func (r *questionRepository) Create(ctx context.Context, questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string) (int, error) {
 	var questionID int
 
-	query := psql.Insert("questions").
-		Columns("quiz_id", "type", "content", "options", "correct_answer", "explanation", "difficulty").
-		Values(quizID, questionType, content, pq.Array(options), correctAnswer, explanation, difficulty).
-		Suffix("RETURNING id")
+	query := r.insertQuestion(questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty)
 
 	err := ExecQueryWithReturning[int](ctx, r.DB, query, &questionID)
 	if err != nil {
@@ -49,8 +60,62 @@ func (r *questionRepository) Create(ctx context.Context, quizID, questionType, c
 	return questionID, nil
 }
 
+// CreateWithLinks creates a question with its tags and, when a quiz is given, appends it to that quiz,
+// in one transaction so that a missing tag or quiz leaves no question behind
+func (r *questionRepository) CreateWithLinks(ctx context.Context, questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string, tagIDs []string, quizID *int, points int) (int, error) {
+	tags, err := parseTagIDs(tagIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	var questionID int
+	err = r.DB.RunInTx(ctx, nil, func(ctx context.Context, bunTx bun.Tx) error {
+		tx := bunTx.Tx
+
+		query := r.insertQuestion(questionType, content, contentFormat, options, payload, correctAnswer, grading, explanation, difficulty)
+
+		err := ExecTxQueryWithReturning(ctx, tx, query, &questionID)
+		if err != nil {
+			return err
+		}
+
+		if len(tags) > 0 {
+			tag := psql.Insert("question_tags").Columns("question_id", "tag_id")
+			for _, tagID := range tags {
+				tag = tag.Values(questionID, tagID)
+			}
+
+			_, err = ExecTxQuery(ctx, tx, tag)
+			if err != nil {
+				return err
+			}
+		}
+
+		if quizID != nil {
+			_, err = ExecTxQuery(ctx, tx, addToQuiz(*quizID, questionID, points))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return questionID, nil
+}
+
+func (r *questionRepository) insertQuestion(questionType, content, contentFormat string, options []string, payload models.Payload, correctAnswer, grading string, explanation *string, difficulty string) sq.InsertBuilder {
+	return psql.Insert("questions").
+		Columns("type", "content", "content_format", "options", "payload", "correct_answer", "grading", "explanation", "difficulty").
+		Values(questionType, content, contentFormat, r.dialect.Array(options), payload, correctAnswer, grading, explanation, difficulty).
+		Suffix("RETURNING id")
+}
+
 // Update updates an existing question
-func (r *questionRepository) Update(ctx context.Context, id int, questionType *string, content *string, options []string, correctAnswer *string, explanation *string, difficulty *string) error {
+func (r *questionRepository) Update(ctx context.Context, id int, questionType *string, content *string, contentFormat *string, options []string, payload *models.Payload, correctAnswer *string, grading *string, explanation *string, difficulty *string) error {
 	query := psql.Update("questions").Where("id = ?", id)
 	hasUpdates := false
 
@@ -64,8 +129,18 @@ func (r *questionRepository) Update(ctx context.Context, id int, questionType *s
 		hasUpdates = true
 	}
 
+	if contentFormat != nil {
+		query = query.Set("content_format", *contentFormat)
+		hasUpdates = true
+	}
+
 	if options != nil {
-		query = query.Set("options", pq.Array(options))
+		query = query.Set("options", r.dialect.Array(options))
+		hasUpdates = true
+	}
+
+	if payload != nil {
+		query = query.Set("payload", *payload)
 		hasUpdates = true
 	}
 
@@ -74,6 +149,11 @@ func (r *questionRepository) Update(ctx context.Context, id int, questionType *s
 		hasUpdates = true
 	}
 
+	if grading != nil {
+		query = query.Set("grading", *grading)
+		hasUpdates = true
+	}
+
 	if explanation != nil {
 		query = query.Set("explanation", *explanation)
 		hasUpdates = true
@@ -88,7 +168,7 @@ func (r *questionRepository) Update(ctx context.Context, id int, questionType *s
 		return nil
 	}
 
-	query = query.Set("updated_at", psql.Select("NOW()"))
+	query = query.Set("updated_at", sq.Expr("CURRENT_TIMESTAMP"))
 
 	_, err := ExecQuery(ctx, r.DB, query)
 	if err != nil {
@@ -111,32 +191,49 @@ func (r *questionRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-// FindAll retrieves all questions, optionally filtered by quiz ID
+// FindAll retrieves all questions in the bank, or the questions of a quiz in quiz order
 func (r *questionRepository) FindAll(ctx context.Context, quizID *int) ([]*models.Question, error) {
-	queryBuilder := psql.Select("id", "quiz_id", "type", "content", "options", "correct_answer", "explanation", "difficulty", "created_at", "updated_at").
-		From("questions")
-
 	if quizID != nil {
-		queryBuilder = queryBuilder.Where("quiz_id = ?", *quizID)
+		queryBuilder := selectQuizQuestions().
+			Where("qq.quiz_id = ?", *quizID).
+			OrderBy("qq.position ASC", "q.id ASC")
+
+		return FindAll[models.Question](ctx, r.DB, queryBuilder)
 	}
 
-	queryBuilder = queryBuilder.OrderBy("created_at ASC")
+	queryBuilder := psql.Select("id", "type", "content", "content_format", "options", "payload", "correct_answer", "grading", "explanation", "difficulty", "created_at", "updated_at").
+		From("questions").
+		OrderBy("created_at ASC")
 
 	return FindAll[models.Question](ctx, r.DB, queryBuilder)
 }
 
 // FindByID retrieves a question by its ID
 func (r *questionRepository) FindByID(ctx context.Context, id int) (*models.Question, error) {
-	query := psql.Select("id", "quiz_id", "type", "content", "options", "correct_answer", "explanation", "difficulty", "created_at", "updated_at").
+	query := psql.Select("id", "type", "content", "content_format", "options", "payload", "correct_answer", "grading", "explanation", "difficulty", "created_at", "updated_at").
 		From("questions").
 		Where("id = ?", id)
 
 	return FindOne[models.Question](ctx, r.DB, query)
 }
 
+// FindByIDs retrieves the questions with the given IDs in ID order, leaving out missing ones
+func (r *questionRepository) FindByIDs(ctx context.Context, ids []int) ([]*models.Question, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := psql.Select("id", "type", "content", "content_format", "options", "payload", "correct_answer", "grading", "explanation", "difficulty", "created_at", "updated_at").
+		From("questions").
+		Where(sq.Eq{"id": ids}).
+		OrderBy("id ASC")
+
+	return FindAll[models.Question](ctx, r.DB, query)
+}
+
 // FindWrongQuestions retrieves questions that were answered incorrectly
 func (r *questionRepository) FindWrongQuestions(ctx context.Context) ([]*models.Question, error) {
-	query := psql.Select("DISTINCT q.id", "q.quiz_id", "q.type", "q.content", "q.options", "q.correct_answer", "q.explanation", "q.difficulty", "q.created_at", "q.updated_at").
+	query := psql.Select("DISTINCT q.id", "q.type", "q.content", "q.content_format", "q.options", "q.payload", "q.correct_answer", "q.grading", "q.explanation", "q.difficulty", "q.created_at", "q.updated_at").
 		From("questions q").
 		Join("answers a ON q.id = a.question_id").
 		Where("a.is_correct = false").
@@ -147,7 +244,7 @@ func (r *questionRepository) FindWrongQuestions(ctx context.Context) ([]*models.
 
 // FindTagsByQuestionID retrieves all tags for a question
 func (r *questionRepository) FindTagsByQuestionID(ctx context.Context, questionID int) ([]*models.Tag, error) {
-	query := psql.Select("t.id", "t.name").
+	query := psql.Select("t.id", "t.name", "t.parent_id").
 		From("tags t").
 		Join("question_tags qt ON t.id = qt.tag_id").
 		Where("qt.question_id = ?", questionID).
@@ -156,19 +253,76 @@ func (r *questionRepository) FindTagsByQuestionID(ctx context.Context, questionI
 	return FindAll[models.Tag](ctx, r.DB, query)
 }
 
+// questionTag is a tag joined with the question carrying it
+type questionTag struct {
+	models.Tag
+	QuestionID int `bun:"question_id"`
+}
+
+// FindTagsByQuestionIDs retrieves tags for multiple questions, keyed by question ID
+func (r *questionRepository) FindTagsByQuestionIDs(ctx context.Context, questionIDs []int) (map[int][]*models.Tag, error) {
+	if len(questionIDs) == 0 {
+		return make(map[int][]*models.Tag), nil
+	}
+
+	query := psql.Select("t.id", "t.name", "t.parent_id", "qt.question_id").
+		From("tags t").
+		Join("question_tags qt ON t.id = qt.tag_id").
+		Where(sq.Eq{"qt.question_id": questionIDs}).
+		OrderBy("qt.question_id ASC", "t.name ASC")
+
+	rows, err := FindAll[questionTag](ctx, r.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]*models.Tag)
+	for _, row := range rows {
+		tag := row.Tag
+		result[row.QuestionID] = append(result[row.QuestionID], &tag)
+	}
+	return result, nil
+}
+
+// FindTaggedByKeywords retrieves tagged questions whose content contains any of the keywords,
+// ignoring case, most recently updated first
+func (r *questionRepository) FindTaggedByKeywords(ctx context.Context, keywords []string, limit int) ([]*models.Question, error) {
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+
+	matches := sq.Or{}
+	for _, keyword := range keywords {
+		matches = append(matches, sq.Expr("LOWER(q.content) LIKE ? ESCAPE '\\'", "%"+escapeLike(strings.ToLower(keyword))+"%"))
+	}
+
+	query := psql.Select("q.id", "q.type", "q.content", "q.content_format", "q.options", "q.payload", "q.correct_answer", "q.grading", "q.explanation", "q.difficulty", "q.created_at", "q.updated_at").
+		From("questions q").
+		Where("EXISTS (SELECT 1 FROM question_tags qt WHERE qt.question_id = q.id)").
+		Where(matches).
+		OrderBy("q.updated_at DESC", "q.id DESC").
+		Limit(uint64(limit))
+
+	return FindAll[models.Question](ctx, r.DB, query)
+}
+
 // AssociateTags associates tags with a question
 func (r *questionRepository) AssociateTags(ctx context.Context, questionID int, tagIDs []string) error {
 	if len(tagIDs) == 0 {
 		return nil
 	}
 
+	ids, err := parseTagIDs(tagIDs)
+	if err != nil {
+		return err
+	}
+
 	query := psql.Insert("question_tags").Columns("question_id", "tag_id")
-	for _, tagID := range tagIDs {
-		id, _ := strconv.Atoi(tagID)
+	for _, id := range ids {
 		query = query.Values(questionID, id)
 	}
 
-	_, err := ExecQuery(ctx, r.DB, query)
+	_, err = ExecQuery(ctx, r.DB, query)
 	if err != nil {
 		return err
 	}
@@ -176,15 +330,228 @@ func (r *questionRepository) AssociateTags(ctx context.Context, questionID int,
 	return nil
 }
 
+// parseTagIDs converts tag IDs given as strings by the API
+func parseTagIDs(tagIDs []string) ([]int, error) {
+	ids := make([]int, len(tagIDs))
+	for i, tagID := range tagIDs {
+		id, err := strconv.Atoi(tagID)
+		if err != nil {
+			return nil, apperrors.InvalidArgument("invalid tag ID %q", tagID)
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
 // ClearTags removes all tag associations for a question
 func (r *questionRepository) ClearTags(ctx context.Context, questionID int) error {
-	deleteSql, deleteArgs, err := psql.Delete("question_tags").
-		Where("question_id = ?", questionID).
-		ToSql()
+	query := psql.Delete("question_tags").
+		Where("question_id = ?", questionID)
+
+	_, err := ExecQuery(ctx, r.DB, query)
 	if err != nil {
 		return err
 	}
 
-	_, err = r.DB.ExecContext(ctx, deleteSql, deleteArgs...)
-	return err
+	return nil
+}
+
+// AddToQuiz appends a question to the end of a quiz, or updates its points if already a member
+func (r *questionRepository) AddToQuiz(ctx context.Context, quizID, questionID int, points int) error {
+	_, err := ExecQuery(ctx, r.DB, addToQuiz(quizID, questionID, points))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func addToQuiz(quizID, questionID int, points int) sq.InsertBuilder {
+	return psql.Insert("quiz_questions").
+		Columns("quiz_id", "question_id", "position", "points").
+		Values(quizID, questionID, sq.Expr("(SELECT COALESCE(MAX(position) + 1, 0) FROM quiz_questions WHERE quiz_id = ?)", quizID), points).
+		Suffix("ON CONFLICT (quiz_id, question_id) DO UPDATE SET points = EXCLUDED.points")
+}
+
+// RemoveFromQuiz removes a question from a quiz, keeping it in the question bank
+func (r *questionRepository) RemoveFromQuiz(ctx context.Context, quizID, questionID int) error {
+	query := psql.Delete("quiz_questions").
+		Where("quiz_id = ?", quizID).
+		Where("question_id = ?", questionID)
+
+	_, err := ExecQuery(ctx, r.DB, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BulkUpdate sets the explanation and difficulty of several questions at once, leaving nil fields unchanged
+func (r *questionRepository) BulkUpdate(ctx context.Context, ids []int, explanation *string, difficulty *string) error {
+	if len(ids) == 0 || (explanation == nil && difficulty == nil) {
+		return nil
+	}
+
+	query := psql.Update("questions").Where(sq.Eq{"id": ids})
+
+	if explanation != nil {
+		query = query.Set("explanation", *explanation)
+	}
+
+	if difficulty != nil {
+		query = query.Set("difficulty", *difficulty)
+	}
+
+	query = query.Set("updated_at", sq.Expr("CURRENT_TIMESTAMP"))
+
+	_, err := ExecQuery(ctx, r.DB, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// BulkDelete deletes several questions at once and returns how many existed
+func (r *questionRepository) BulkDelete(ctx context.Context, ids []int) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result, err := ExecQuery(ctx, r.DB, psql.Delete("questions").Where(sq.Eq{"id": ids}))
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(deleted), nil
+}
+
+// BulkTag removes then adds tags on several questions in one transaction.
+// Tags a question already carries are kept once.
+func (r *questionRepository) BulkTag(ctx context.Context, ids []int, addTagIDs, removeTagIDs []int) error {
+	if len(ids) == 0 || (len(addTagIDs) == 0 && len(removeTagIDs) == 0) {
+		return nil
+	}
+
+	return r.DB.RunInTx(ctx, nil, func(ctx context.Context, bunTx bun.Tx) error {
+		tx := bunTx.Tx
+
+		if len(removeTagIDs) > 0 {
+			untag := psql.Delete("question_tags").
+				Where(sq.Eq{"question_id": ids}).
+				Where(sq.Eq{"tag_id": removeTagIDs})
+
+			_, err := ExecTxQuery(ctx, tx, untag)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(addTagIDs) == 0 {
+			return nil
+		}
+
+		tag := psql.Insert("question_tags").
+			Columns("question_id", "tag_id").
+			Suffix("ON CONFLICT DO NOTHING")
+		for _, id := range ids {
+			for _, tagID := range addTagIDs {
+				tag = tag.Values(id, tagID)
+			}
+		}
+
+		_, err := ExecTxQuery(ctx, tx, tag)
+		return err
+	})
+}
+
+// MoveToQuiz appends questions to the end of a quiz in the given order and takes them out of the source quiz
+// when one is given, in one transaction. Their other quizzes keep them. A moved question keeps its points
+// in the source quiz, or gets the given points if it was not in it.
+func (r *questionRepository) MoveToQuiz(ctx context.Context, ids []int, sourceQuizID *int, quizID int, points int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return r.DB.RunInTx(ctx, nil, func(ctx context.Context, bunTx bun.Tx) error {
+		tx := bunTx.Tx
+
+		var next int
+		nextPosition := psql.Select("COALESCE(MAX(position) + 1, 0)").
+			From("quiz_questions").
+			Where("quiz_id = ?", quizID)
+
+		err := ExecTxQueryWithReturning(ctx, tx, nextPosition, &next)
+		if err != nil {
+			return err
+		}
+
+		position := sq.Case("q.id")
+		for i, id := range ids {
+			position = position.When(sq.Expr("?", id), sq.Expr("CAST(? AS INTEGER)", next+i))
+		}
+
+		movedPoints := sq.Expr("CAST(? AS INTEGER)", points)
+		if sourceQuizID != nil {
+			movedPoints = sq.Expr("COALESCE((SELECT qq.points FROM quiz_questions qq WHERE qq.question_id = q.id AND qq.quiz_id = ?), CAST(? AS INTEGER))", *sourceQuizID, points)
+		}
+
+		// Questions already in the quiz only move to the end
+		insert := psql.Insert("quiz_questions").
+			Columns("quiz_id", "question_id", "position", "points").
+			Select(sq.Select().
+				Column("CAST(? AS INTEGER)", quizID).
+				Column("q.id").
+				Column(position).
+				Column(movedPoints).
+				From("questions q").
+				Where(sq.Eq{"q.id": ids})).
+			Suffix("ON CONFLICT (quiz_id, question_id) DO UPDATE SET position = EXCLUDED.position")
+
+		_, err = ExecTxQuery(ctx, tx, insert)
+		if err != nil {
+			return err
+		}
+
+		if sourceQuizID == nil || *sourceQuizID == quizID {
+			return nil
+		}
+
+		leave := psql.Delete("quiz_questions").
+			Where(sq.Eq{"question_id": ids}).
+			Where("quiz_id = ?", *sourceQuizID)
+
+		_, err = ExecTxQuery(ctx, tx, leave)
+		return err
+	})
+}
+
+// Reorder numbers the questions of a quiz in the given order. Questions of the quiz left out keep their position.
+func (r *questionRepository) Reorder(ctx context.Context, quizID int, orderedIDs []int) error {
+	if len(orderedIDs) == 0 {
+		return nil
+	}
+
+	position := sq.Case("question_id")
+	for i, id := range orderedIDs {
+		position = position.When(sq.Expr("?", id), sq.Expr("CAST(? AS INTEGER)", i))
+	}
+
+	query := psql.Update("quiz_questions").
+		Set("position", position).
+		Where("quiz_id = ?", quizID).
+		Where(sq.Eq{"question_id": orderedIDs})
+
+	_, err := ExecQuery(ctx, r.DB, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/uptrace/bun"
+
+	"quiz-log/models"
+)
+
+func TestQuestionRepository_Create(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuestionRepository(bunDB)
+
+		expectedID := 1
+
+		// An empty payload is stored as NULL
+		mock.ExpectQuery(`INSERT INTO questions \(type,content,content_format,options,payload,correct_answer,grading,explanation,difficulty\)`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), "MARKDOWN", sqlmock.AnyArg(), nil, sqlmock.AnyArg(), "EXACT", sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+
+		ctx := context.Background()
+		id, err := repo.Create(ctx, "MULTIPLE_CHOICE", "Question 1", "MARKDOWN", []string{"A", "B"}, models.Payload{}, "A", "EXACT", nil, "EASY")
+
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if id != expectedID {
+			t.Errorf("expected id %d, got %d", expectedID, id)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestQuestionRepository_AddToQuiz(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuestionRepository(bunDB)
+
+		quizID := 1
+		questionID := 2
+		points := 3
+
+		mock.ExpectExec(`INSERT INTO quiz_questions (.+) VALUES \(\$1,\$2,\(SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM quiz_questions WHERE quiz_id = \$3\),\$4\) ON CONFLICT`).
+			WithArgs(quizID, questionID, quizID, points).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx := context.Background()
+		err := repo.AddToQuiz(ctx, quizID, questionID, points)
+
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestQuestionRepository_RemoveFromQuiz(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuestionRepository(bunDB)
+
+		mock.ExpectExec(`DELETE FROM quiz_questions WHERE quiz_id = \$1 AND question_id = \$2`).
+			WithArgs(1, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		ctx := context.Background()
+		err := repo.RemoveFromQuiz(ctx, 1, 2)
+
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestQuestionRepository_Reorder(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuestionRepository(bunDB)
+
+		// Expect every position to be set by a single statement
+		mock.ExpectExec(`UPDATE quiz_questions SET position = CASE question_id WHEN \$1 THEN CAST\(\$2 AS INTEGER\) WHEN \$3 THEN CAST\(\$4 AS INTEGER\) END WHERE quiz_id = \$5 AND question_id IN \(\$6,\$7\)`).
+			WithArgs(3, 0, 2, 1, 1, 3, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		// Execute
+		err := repo.Reorder(context.Background(), 1, []int{3, 2})
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestQuestionRepository_MoveToQuiz(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuestionRepository(bunDB)
+		source := 5
+
+		// Expect the questions to be appended after the last position, then to leave the source quiz only
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM quiz_questions WHERE quiz_id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"next"}).AddRow(4))
+		mock.ExpectExec(`INSERT INTO quiz_questions \(quiz_id,question_id,position,points\) SELECT CAST\(\$1 AS INTEGER\), q.id, CASE q.id WHEN \$2 THEN CAST\(\$3 AS INTEGER\) WHEN \$4 THEN CAST\(\$5 AS INTEGER\) END, COALESCE\(\(SELECT qq.points FROM quiz_questions qq WHERE qq.question_id = q.id AND qq.quiz_id = \$6\), CAST\(\$7 AS INTEGER\)\) FROM questions q WHERE q.id IN \(\$8,\$9\) ON CONFLICT \(quiz_id, question_id\) DO UPDATE SET position = EXCLUDED.position`).
+			WithArgs(1, 3, 4, 2, 5, source, 1, 3, 2).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM quiz_questions WHERE question_id IN \(\$1,\$2\) AND quiz_id = \$3`).
+			WithArgs(3, 2, source).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// Execute
+		err := repo.MoveToQuiz(context.Background(), []int{3, 2}, &source, 1, 1)
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestQuestionRepository_BulkTag_RollbackOnError(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuestionRepository(bunDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM question_tags WHERE question_id IN \(\$1,\$2\) AND tag_id IN \(\$3\)`).
+			WithArgs(1, 2, 5).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO question_tags \(question_id,tag_id\) VALUES \(\$1,\$2\),\(\$3,\$4\) ON CONFLICT DO NOTHING`).
+			WithArgs(1, 4, 2, 4).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		// Execute
+		err := repo.BulkTag(context.Background(), []int{1, 2}, []int{4}, []int{5})
+
+		// Assert
+		if err != sql.ErrConnDone {
+			t.Errorf("expected sql.ErrConnDone, got %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestQuestionRepository_CreateWithLinks_RollbackOnError(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuestionRepository(bunDB)
+		quizID := 9
+
+		// A failing quiz insert rolls the question and its tags back
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO questions \(type,content,content_format,options,payload,correct_answer,grading,explanation,difficulty\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectExec(`INSERT INTO question_tags \(question_id,tag_id\) VALUES \(\$1,\$2\),\(\$3,\$4\)`).
+			WithArgs(1, 4, 1, 5).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO quiz_questions (.+) VALUES \(\$1,\$2,\(SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM quiz_questions WHERE quiz_id = \$3\),\$4\) ON CONFLICT`).
+			WithArgs(quizID, 1, quizID, 2).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		// Execute
+		_, err := repo.CreateWithLinks(context.Background(), "TRUE_FALSE", "Question 1", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY", []string{"4", "5"}, &quizID, 2)
+
+		// Assert
+		if err != sql.ErrConnDone {
+			t.Errorf("expected sql.ErrConnDone, got %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"quiz-log/apperrors"
 	"quiz-log/models"
 	"strconv"
 
@@ -16,7 +17,7 @@ type QuizRepository interface {
 	Create(ctx context.Context, title string, description *string) (int, error)
 	Update(ctx context.Context, id int, title *string, description *string) error
 	Delete(ctx context.Context, id int) error
-	FindAll(ctx context.Context) ([]*models.Quiz, error)
+	FindAll(ctx context.Context, status string) ([]*models.Quiz, error)
 	FindByID(ctx context.Context, id int) (*models.Quiz, error)
 	FindQuestionsByQuizID(ctx context.Context, quizID int) ([]*models.Question, error)
 	FindTagsByQuizID(ctx context.Context, quizID int) ([]*models.Tag, error)
@@ -24,14 +25,19 @@ type QuizRepository interface {
 	ClearTags(ctx context.Context, quizID int) error
 	FindQuestionsByQuizIDs(ctx context.Context, quizIDs []int) (map[int][]*models.Question, error)
 	FindTagsByQuizIDs(ctx context.Context, quizIDs []int) (map[int][]*models.Tag, error)
+	FindTemplates(ctx context.Context) ([]*models.Quiz, error)
+	SetTemplate(ctx context.Context, id int, isTemplate bool) error
+	UpdateStatus(ctx context.Context, id int, status string) error
+	Duplicate(ctx context.Context, id int, title string, includeTags bool, replace func(string) string) (int, error)
 }
 
 type quizRepository struct {
-	DB *bun.DB
+	DB      *bun.DB
+	dialect Dialect
 }
 
 func NewQuizRepository(database *bun.DB) QuizRepository {
-	return &quizRepository{DB: database}
+	return &quizRepository{DB: database, dialect: dialectOf(database)}
 }
 
 // Create creates a new quiz and returns its ID
@@ -75,7 +81,7 @@ func (r *quizRepository) Update(ctx context.Context, id int, title *string, desc
 		return nil
 	}
 
-	updateBuilder = updateBuilder.Set("updated_at", sq.Expr("NOW()"))
+	updateBuilder = updateBuilder.Set("updated_at", sq.Expr("CURRENT_TIMESTAMP"))
 
 	_, err := ExecQuery(ctx, r.DB, updateBuilder)
 	if err != nil {
@@ -96,10 +102,12 @@ func (r *quizRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
-// FindAll retrieves all quizzes
-func (r *quizRepository) FindAll(ctx context.Context) ([]*models.Quiz, error) {
-	query := psql.Select("id", "title", "description", "created_at", "updated_at").
+// FindAll retrieves all quizzes with the given status except templates
+func (r *quizRepository) FindAll(ctx context.Context, status string) ([]*models.Quiz, error) {
+	query := psql.Select("id", "title", "description", "is_template", "status", "created_at", "updated_at").
 		From("quizzes").
+		Where("is_template = ?", false).
+		Where("status = ?", status).
 		OrderBy("created_at DESC")
 
 	return FindAll[models.Quiz](ctx, r.DB, query)
@@ -107,26 +115,25 @@ func (r *quizRepository) FindAll(ctx context.Context) ([]*models.Quiz, error) {
 
 // FindByID retrieves a quiz by its ID
 func (r *quizRepository) FindByID(ctx context.Context, id int) (*models.Quiz, error) {
-	query := psql.Select("id", "title", "description", "created_at", "updated_at").
+	query := psql.Select("id", "title", "description", "is_template", "status", "created_at", "updated_at").
 		From("quizzes").
 		Where("id = ?", id)
 
 	return FindOne[models.Quiz](ctx, r.DB, query)
 }
 
-// FindQuestionsByQuizID retrieves all questions for a quiz
+// FindQuestionsByQuizID retrieves all questions for a quiz in quiz order
 func (r *quizRepository) FindQuestionsByQuizID(ctx context.Context, quizID int) ([]*models.Question, error) {
-	query := psql.Select("id", "quiz_id", "type", "content", "options", "correct_answer", "explanation", "difficulty", "created_at", "updated_at").
-		From("questions").
-		Where("quiz_id = ?", quizID).
-		OrderBy("created_at ASC")
+	query := selectQuizQuestions().
+		Where("qq.quiz_id = ?", quizID).
+		OrderBy("qq.position ASC", "q.id ASC")
 
 	return FindAll[models.Question](ctx, r.DB, query)
 }
 
 // FindTagsByQuizID retrieves all tags for a quiz
 func (r *quizRepository) FindTagsByQuizID(ctx context.Context, quizID int) ([]*models.Tag, error) {
-	query := psql.Select("t.id", "t.name").
+	query := psql.Select("t.id", "t.name", "t.parent_id").
 		From("tags t").
 		Join("quiz_tags qt ON t.id = qt.tag_id").
 		Where("qt.quiz_id = ?", quizID).
@@ -143,7 +150,10 @@ func (r *quizRepository) AssociateTags(ctx context.Context, quizID int, tagIDs [
 
 	insertBuilder := psql.Insert("quiz_tags").Columns("quiz_id", "tag_id")
 	for _, tagID := range tagIDs {
-		id, _ := strconv.Atoi(tagID)
+		id, err := strconv.Atoi(tagID)
+		if err != nil {
+			return apperrors.InvalidArgument("invalid tag ID %q", tagID)
+		}
 		insertBuilder = insertBuilder.Values(quizID, id)
 	}
 	_, err := ExecQuery(ctx, r.DB, insertBuilder)
@@ -171,10 +181,9 @@ func (r *quizRepository) FindQuestionsByQuizIDs(ctx context.Context, quizIDs []i
 		return make(map[int][]*models.Question), nil
 	}
 
-	query := psql.Select("id", "quiz_id", "type", "content", "options", "correct_answer", "explanation", "difficulty", "created_at", "updated_at").
-		From("questions").
-		Where(sq.Eq{"quiz_id": quizIDs}).
-		OrderBy("quiz_id ASC", "created_at ASC")
+	query := selectQuizQuestions().
+		Where(sq.Eq{"qq.quiz_id": quizIDs}).
+		OrderBy("qq.quiz_id ASC", "qq.position ASC", "q.id ASC")
 
 	questions, err := FindAll[models.Question](ctx, r.DB, query)
 	if err != nil {
@@ -198,7 +207,7 @@ func (r *quizRepository) FindTagsByQuizIDs(ctx context.Context, quizIDs []int) (
 		return make(map[int][]*models.Tag), nil
 	}
 
-	query := psql.Select("t.id", "t.name", "qt.quiz_id").
+	query := psql.Select("t.id", "t.name", "t.parent_id", "qt.quiz_id").
 		From("tags t").
 		Join("quiz_tags qt ON t.id = qt.tag_id").
 		Where(sq.Eq{"qt.quiz_id": quizIDs}).
@@ -221,14 +230,16 @@ func (r *quizRepository) FindTagsByQuizIDs(ctx context.Context, quizIDs []int) (
 	for dbRows.Next() {
 		var tagID int
 		var tagName string
+		var parentID *int
 		var quizID int
-		err = dbRows.Scan(&tagID, &tagName, &quizID)
+		err = dbRows.Scan(&tagID, &tagName, &parentID, &quizID)
 		if err != nil {
 			return nil, err
 		}
 		tag := &models.Tag{
-			ID:   tagID,
-			Name: tagName,
+			ID:       tagID,
+			Name:     tagName,
+			ParentID: parentID,
 		}
 		result[quizID] = append(result[quizID], tag)
 	}
@@ -239,3 +250,223 @@ func (r *quizRepository) FindTagsByQuizIDs(ctx context.Context, quizIDs []int) (
 
 	return result, nil
 }
+
+// FindTemplates retrieves all quizzes marked as templates
+func (r *quizRepository) FindTemplates(ctx context.Context) ([]*models.Quiz, error) {
+	query := psql.Select("id", "title", "description", "is_template", "status", "created_at", "updated_at").
+		From("quizzes").
+		Where("is_template = ?", true).
+		OrderBy("title ASC")
+
+	return FindAll[models.Quiz](ctx, r.DB, query)
+}
+
+// SetTemplate marks or unmarks a quiz as a template
+func (r *quizRepository) SetTemplate(ctx context.Context, id int, isTemplate bool) error {
+	query := psql.Update("quizzes").
+		Set("is_template", isTemplate).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where("id = ?", id)
+
+	_, err := ExecQuery(ctx, r.DB, query)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateStatus changes the workflow status of a quiz
+func (r *quizRepository) UpdateStatus(ctx context.Context, id int, status string) error {
+	query := psql.Update("quizzes").
+		Set("status", status).
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where("id = ?", id)
+
+	_, err := ExecQuery(ctx, r.DB, query)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Duplicate deep-copies a quiz, its questions with their tags, attachments and quiz settings in one transaction.
+// The copied attachments share the blobs of the originals. Every copied text is passed through replace,
+// and the ID of the new quiz is returned.
+func (r *quizRepository) Duplicate(ctx context.Context, id int, title string, includeTags bool, replace func(string) string) (int, error) {
+	if replace == nil {
+		replace = func(s string) string { return s }
+	}
+
+	var newQuizID int
+	err := r.DB.RunInTx(ctx, nil, func(ctx context.Context, bunTx bun.Tx) error {
+		// Use the raw transaction so that squirrel placeholders are passed through unchanged
+		tx := bunTx.Tx
+
+		var description *string
+		err := ExecTxQueryWithReturning(ctx, tx, psql.Select("description").From("quizzes").Where("id = ?", id), &description)
+		if err != nil {
+			return err
+		}
+
+		if description != nil {
+			d := replace(*description)
+			description = &d
+		}
+
+		insertQuiz := psql.Insert("quizzes").
+			Columns("title", "description").
+			Values(title, description).
+			Suffix("RETURNING id")
+
+		err = ExecTxQueryWithReturning(ctx, tx, insertQuiz, &newQuizID)
+		if err != nil {
+			return err
+		}
+
+		questions, err := findQuestionsToCopy(ctx, tx, r.dialect, id)
+		if err != nil {
+			return err
+		}
+
+		for _, q := range questions {
+			var explanation *string
+			if q.Explanation != nil {
+				e := replace(*q.Explanation)
+				explanation = &e
+			}
+
+			var options []string
+			for _, option := range q.Options {
+				options = append(options, replace(option))
+			}
+
+			// Numeric answers and their units are matched against what quiz takers type, so they are kept
+			payload := models.Payload{Numeric: q.Payload.Numeric}
+			for _, pair := range q.Payload.Pairs {
+				payload.Pairs = append(payload.Pairs, models.MatchingPair{Prompt: replace(pair.Prompt), Answer: replace(pair.Answer)})
+			}
+			for _, blank := range q.Payload.Blanks {
+				// Blanks compared as numbers are kept like numeric answers
+				accepted := blank.Accepted
+				if blank.Strategy != "NUMERIC" {
+					accepted = nil
+					for _, answer := range blank.Accepted {
+						accepted = append(accepted, replace(answer))
+					}
+				}
+				payload.Blanks = append(payload.Blanks, models.ClozeBlank{Accepted: accepted, Strategy: blank.Strategy})
+			}
+
+			// The answer to an ORDERING question refers to its options by index, that to a NUMERIC one is a value
+			correctAnswer := replace(q.CorrectAnswer)
+			if q.Type == "ORDERING" || q.Type == "NUMERIC" {
+				correctAnswer = q.CorrectAnswer
+			}
+
+			var newQuestionID int
+			insertQuestion := psql.Insert("questions").
+				Columns("type", "content", "content_format", "options", "payload", "correct_answer", "grading", "explanation", "difficulty").
+				Values(q.Type, replace(q.Content), q.ContentFormat, r.dialect.Array(options), payload, correctAnswer, q.Grading, explanation, q.Difficulty).
+				Suffix("RETURNING id")
+
+			err = ExecTxQueryWithReturning(ctx, tx, insertQuestion, &newQuestionID)
+			if err != nil {
+				return err
+			}
+
+			insertMembership := psql.Insert("quiz_questions").
+				Columns("quiz_id", "question_id", "position", "points").
+				Values(newQuizID, newQuestionID, *q.Position, *q.Points)
+
+			_, err = ExecTxQuery(ctx, tx, insertMembership)
+			if err != nil {
+				return err
+			}
+
+			copyQuestionTags := psql.Insert("question_tags").
+				Columns("question_id", "tag_id").
+				Select(sq.Select().Column("CAST(? AS INTEGER)", newQuestionID).Column("tag_id").From("question_tags").Where("question_id = ?", q.ID))
+
+			_, err = ExecTxQuery(ctx, tx, copyQuestionTags)
+			if err != nil {
+				return err
+			}
+
+			copyAttachments := psql.Insert("attachments").
+				Columns("question_id", "placement", "filename", "content_type", "size", "storage_key").
+				Select(sq.Select().Column("CAST(? AS INTEGER)", newQuestionID).
+					Columns("placement", "filename", "content_type", "size", "storage_key").
+					From("attachments").Where("question_id = ?", q.ID).OrderBy("id ASC"))
+
+			_, err = ExecTxQuery(ctx, tx, copyAttachments)
+			if err != nil {
+				return err
+			}
+		}
+
+		if includeTags {
+			copyQuizTags := psql.Insert("quiz_tags").
+				Columns("quiz_id", "tag_id").
+				Select(sq.Select().Column("CAST(? AS INTEGER)", newQuizID).Column("tag_id").From("quiz_tags").Where("quiz_id = ?", id))
+
+			_, err = ExecTxQuery(ctx, tx, copyQuizTags)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return newQuizID, nil
+}
+
+// findQuestionsToCopy reads all questions of a quiz with their membership settings inside a transaction
+func findQuestionsToCopy(ctx context.Context, tx DBExecutor, d Dialect, quizID int) ([]*models.Question, error) {
+	query := psql.Select("q.id", "q.type", "q.content", "q.content_format", "q.options", "q.payload", "q.correct_answer", "q.grading", "q.explanation", "q.difficulty", "qq.position", "qq.points").
+		From("questions q").
+		Join("quiz_questions qq ON q.id = qq.question_id").
+		Where("qq.quiz_id = ?", quizID).
+		OrderBy("qq.position ASC", "q.id ASC")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	// Rows must be fully read before issuing further statements on the transaction
+	dbRows, err := tx.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer dbRows.Close()
+
+	var questions []*models.Question
+	for dbRows.Next() {
+		q := &models.Question{}
+		var position, points int
+		err = dbRows.Scan(&q.ID, &q.Type, &q.Content, &q.ContentFormat, d.ScanArray(&q.Options), &q.Payload, &q.CorrectAnswer, &q.Grading, &q.Explanation, &q.Difficulty, &position, &points)
+		if err != nil {
+			return nil, err
+		}
+		q.Position = &position
+		q.Points = &points
+		questions = append(questions, q)
+	}
+
+	if err := dbRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// selectQuizQuestions selects questions through their quiz membership
+func selectQuizQuestions() sq.SelectBuilder {
+	return psql.Select("q.id", "qq.quiz_id", "q.type", "q.content", "q.content_format", "q.options", "q.payload", "q.correct_answer", "q.grading", "q.explanation", "q.difficulty", "q.created_at", "q.updated_at", "qq.position", "qq.points").
+		From("questions q").
+		Join("quiz_questions qq ON q.id = qq.question_id")
+}
@@ -3,16 +3,19 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/lib/pq"
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+	"github.com/uptrace/bun/schema"
 )
 
-func setupMockDB(t *testing.T) (*bun.DB, sqlmock.Sqlmock, func()) {
+func setupMockDB(t *testing.T, d schema.Dialect) (*bun.DB, sqlmock.Sqlmock, func()) {
 	sqlDB, mock, err := sqlmock.New(
 		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp),
 		sqlmock.MonitorPingsOption(false),
@@ -22,7 +25,7 @@ func setupMockDB(t *testing.T) (*bun.DB, sqlmock.Sqlmock, func()) {
 	}
 
 	// Use QueryRowContext through bun.DB.DB to properly interact with sqlmock
-	bunDB := bun.NewDB(sqlDB, pgdialect.New())
+	bunDB := bun.NewDB(sqlDB, d)
 
 	cleanup := func() {
 		bunDB.Close()
@@ -31,374 +34,511 @@ func setupMockDB(t *testing.T) (*bun.DB, sqlmock.Sqlmock, func()) {
 	return bunDB, mock, cleanup
 }
 
-func TestQuizRepository_Create(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+// forEachDialect runs a test against a mocked database of every storage dialect
+func forEachDialect(t *testing.T, test func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock)) {
+	for _, d := range []schema.Dialect{pgdialect.New(), sqlitedialect.New()} {
+		t.Run(d.Name().String(), func(t *testing.T) {
+			bunDB, mock, cleanup := setupMockDB(t, d)
+			defer cleanup()
 
-	repo := NewQuizRepository(bunDB)
+			test(t, bunDB, mock)
+		})
+	}
+}
 
-	title := "Test Quiz"
-	description := "Test Description"
-	expectedID := 1
+// arrayValue encodes a string slice the way the dialect of bunDB stores it
+func arrayValue(t *testing.T, bunDB *bun.DB, values []string) driver.Value {
+	v, err := dialectOf(bunDB).Array(values).Value()
+	if err != nil {
+		t.Fatalf("failed to encode array: %v", err)
+	}
+	return v
+}
 
-	mock.ExpectQuery(`INSERT INTO quizzes`).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+func TestQuizRepository_Create(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	ctx := context.Background()
-	id, err := repo.Create(ctx, title, &description)
+		title := "Test Quiz"
+		description := "Test Description"
+		expectedID := 1
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		mock.ExpectQuery(`INSERT INTO quizzes`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
 
-	if id != expectedID {
-		t.Errorf("expected id %d, got %d", expectedID, id)
-	}
+		ctx := context.Background()
+		id, err := repo.Create(ctx, title, &description)
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if id != expectedID {
+			t.Errorf("expected id %d, got %d", expectedID, id)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_Create_WithNullDescription(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
-
-	repo := NewQuizRepository(bunDB)
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	title := "Test Quiz"
-	expectedID := 1
+		title := "Test Quiz"
+		expectedID := 1
 
-	mock.ExpectQuery(`INSERT INTO quizzes`).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
-		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
+		mock.ExpectQuery(`INSERT INTO quizzes`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(expectedID))
 
-	ctx := context.Background()
-	id, err := repo.Create(ctx, title, nil)
+		ctx := context.Background()
+		id, err := repo.Create(ctx, title, nil)
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if id != expectedID {
-		t.Errorf("expected id %d, got %d", expectedID, id)
-	}
+		if id != expectedID {
+			t.Errorf("expected id %d, got %d", expectedID, id)
+		}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_Update(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
-
-	repo := NewQuizRepository(bunDB)
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	id := 1
-	title := "Updated Quiz"
-	description := "Updated Description"
+		id := 1
+		title := "Updated Quiz"
+		description := "Updated Description"
 
-	mock.ExpectExec(`UPDATE quizzes`).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE quizzes`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
 
-	ctx := context.Background()
-	err := repo.Update(ctx, id, &title, &description)
+		ctx := context.Background()
+		err := repo.Update(ctx, id, &title, &description)
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_Update_NoUpdates(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	repo := NewQuizRepository(bunDB)
+		id := 1
 
-	id := 1
+		ctx := context.Background()
+		err := repo.Update(ctx, id, nil, nil)
 
-	ctx := context.Background()
-	err := repo.Update(ctx, id, nil, nil)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_Delete(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	repo := NewQuizRepository(bunDB)
+		id := 1
 
-	id := 1
+		mock.ExpectExec(`DELETE FROM quizzes`).
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
 
-	mock.ExpectExec(`DELETE FROM quizzes`).
-		WithArgs(sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+		ctx := context.Background()
+		err := repo.Delete(ctx, id)
 
-	ctx := context.Background()
-	err := repo.Delete(ctx, id)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
-
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_FindByID(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
-
-	repo := NewQuizRepository(bunDB)
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	id := 1
-	title := "Test Quiz"
-	description := "Test Description"
-	createdAt := time.Now()
-	updatedAt := time.Now()
+		id := 1
+		title := "Test Quiz"
+		description := "Test Description"
+		createdAt := time.Now()
+		updatedAt := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "created_at", "updated_at"}).
-		AddRow(id, title, description, createdAt, updatedAt)
+		rows := sqlmock.NewRows([]string{"id", "title", "description", "created_at", "updated_at"}).
+			AddRow(id, title, description, createdAt, updatedAt)
 
-	mock.ExpectQuery(`SELECT (.+) FROM quizzes`).
-		WithArgs(sqlmock.AnyArg()).
-		WillReturnRows(rows)
+		mock.ExpectQuery(`SELECT (.+) FROM quizzes`).
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnRows(rows)
 
-	ctx := context.Background()
-	quiz, err := repo.FindByID(ctx, id)
+		ctx := context.Background()
+		quiz, err := repo.FindByID(ctx, id)
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if quiz == nil {
-		t.Fatal("expected quiz, got nil")
-	}
+		if quiz == nil {
+			t.Fatal("expected quiz, got nil")
+		}
 
-	if quiz.ID != id {
-		t.Errorf("expected id %d, got %d", id, quiz.ID)
-	}
+		if quiz.ID != id {
+			t.Errorf("expected id %d, got %d", id, quiz.ID)
+		}
 
-	if quiz.Title != title {
-		t.Errorf("expected title %s, got %s", title, quiz.Title)
-	}
+		if quiz.Title != title {
+			t.Errorf("expected title %s, got %s", title, quiz.Title)
+		}
 
-	if quiz.Description == nil || *quiz.Description != description {
-		t.Errorf("expected description %s, got %v", description, quiz.Description)
-	}
+		if quiz.Description == nil || *quiz.Description != description {
+			t.Errorf("expected description %s, got %v", description, quiz.Description)
+		}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_FindByID_NotFound(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	repo := NewQuizRepository(bunDB)
+		id := 999
 
-	id := 999
+		mock.ExpectQuery(`SELECT (.+) FROM quizzes`).
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnError(sql.ErrNoRows)
 
-	mock.ExpectQuery(`SELECT (.+) FROM quizzes`).
-		WithArgs(sqlmock.AnyArg()).
-		WillReturnError(sql.ErrNoRows)
+		ctx := context.Background()
+		quiz, err := repo.FindByID(ctx, id)
 
-	ctx := context.Background()
-	quiz, err := repo.FindByID(ctx, id)
+		if err != sql.ErrNoRows {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
 
-	if err != sql.ErrNoRows {
-		t.Errorf("expected sql.ErrNoRows, got %v", err)
-	}
-
-	if quiz != nil {
-		t.Errorf("expected nil quiz, got %v", quiz)
-	}
+		if quiz != nil {
+			t.Errorf("expected nil quiz, got %v", quiz)
+		}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_FindAll(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
-
-	repo := NewQuizRepository(bunDB)
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	createdAt := time.Now()
-	updatedAt := time.Now()
+		createdAt := time.Now()
+		updatedAt := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "title", "description", "created_at", "updated_at"}).
-		AddRow(1, "Quiz 1", "Description 1", createdAt, updatedAt).
-		AddRow(2, "Quiz 2", "Description 2", createdAt, updatedAt)
+		rows := sqlmock.NewRows([]string{"id", "title", "description", "created_at", "updated_at"}).
+			AddRow(1, "Quiz 1", "Description 1", createdAt, updatedAt).
+			AddRow(2, "Quiz 2", "Description 2", createdAt, updatedAt)
 
-	mock.ExpectQuery(`SELECT (.+) FROM quizzes`).
-		WillReturnRows(rows)
+		mock.ExpectQuery(`SELECT (.+) FROM quizzes WHERE is_template = \$1 AND status = \$2`).
+			WithArgs(false, "PUBLISHED").
+			WillReturnRows(rows)
 
-	ctx := context.Background()
-	quizzes, err := repo.FindAll(ctx)
+		ctx := context.Background()
+		quizzes, err := repo.FindAll(ctx, "PUBLISHED")
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if len(quizzes) != 2 {
-		t.Errorf("expected 2 quizzes, got %d", len(quizzes))
-	}
+		if len(quizzes) != 2 {
+			t.Errorf("expected 2 quizzes, got %d", len(quizzes))
+		}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_FindQuestionsByQuizID(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	repo := NewQuizRepository(bunDB)
+		quizID := 1
+		createdAt := time.Now()
+		updatedAt := time.Now()
 
-	quizID := 1
-	createdAt := time.Now()
-	updatedAt := time.Now()
+		rows := sqlmock.NewRows([]string{"id", "quiz_id", "type", "content", "options", "correct_answer", "explanation", "difficulty", "created_at", "updated_at", "position", "points"}).
+			AddRow(1, quizID, "MULTIPLE_CHOICE", "Question 1", arrayValue(t, bunDB, []string{"A", "B", "C"}), "A", "Explanation", "EASY", createdAt, updatedAt, 0, 1).
+			AddRow(2, quizID, "TRUE_FALSE", "Question 2", arrayValue(t, bunDB, []string{"True", "False"}), "True", "Explanation", "MEDIUM", createdAt, updatedAt, 1, 2)
 
-	rows := sqlmock.NewRows([]string{"id", "quiz_id", "type", "content", "options", "correct_answer", "explanation", "difficulty", "created_at", "updated_at"}).
-		AddRow(1, quizID, "MULTIPLE_CHOICE", "Question 1", pq.Array([]string{"A", "B", "C"}), "A", "Explanation", "EASY", createdAt, updatedAt).
-		AddRow(2, quizID, "TRUE_FALSE", "Question 2", pq.Array([]string{"True", "False"}), "True", "Explanation", "MEDIUM", createdAt, updatedAt)
+		mock.ExpectQuery(`SELECT (.+) FROM questions q JOIN quiz_questions qq ON (.+) WHERE qq.quiz_id = (.+) ORDER BY qq.position ASC`).
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnRows(rows)
 
-	mock.ExpectQuery(`SELECT (.+) FROM questions`).
-		WithArgs(sqlmock.AnyArg()).
-		WillReturnRows(rows)
+		ctx := context.Background()
+		questions, err := repo.FindQuestionsByQuizID(ctx, quizID)
 
-	ctx := context.Background()
-	questions, err := repo.FindQuestionsByQuizID(ctx, quizID)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		if len(questions) != 2 {
+			t.Fatalf("expected 2 questions, got %d", len(questions))
+		}
 
-	if len(questions) != 2 {
-		t.Errorf("expected 2 questions, got %d", len(questions))
-	}
+		if questions[1].Position == nil || *questions[1].Position != 1 {
+			t.Errorf("expected position 1, got %v", questions[1].Position)
+		}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if questions[1].Points == nil || *questions[1].Points != 2 {
+			t.Errorf("expected points 2, got %v", questions[1].Points)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
-func TestQuizRepository_AssociateTags(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+func TestQuizRepository_FindQuestionsByQuizIDs(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	repo := NewQuizRepository(bunDB)
+		createdAt := time.Now()
+		updatedAt := time.Now()
 
-	quizID := 1
-	tagIDs := []string{"1", "2", "3"}
+		// Question 1 belongs to both quizzes
+		rows := sqlmock.NewRows([]string{"id", "quiz_id", "type", "content", "options", "correct_answer", "explanation", "difficulty", "created_at", "updated_at", "position", "points"}).
+			AddRow(1, 1, "MULTIPLE_CHOICE", "Question 1", arrayValue(t, bunDB, []string{"A", "B", "C"}), "A", "Explanation", "EASY", createdAt, updatedAt, 0, 1).
+			AddRow(2, 1, "TRUE_FALSE", "Question 2", arrayValue(t, bunDB, []string{"True", "False"}), "True", "Explanation", "MEDIUM", createdAt, updatedAt, 1, 1).
+			AddRow(1, 2, "MULTIPLE_CHOICE", "Question 1", arrayValue(t, bunDB, []string{"A", "B", "C"}), "A", "Explanation", "EASY", createdAt, updatedAt, 0, 3)
 
-	mock.ExpectExec(`INSERT INTO quiz_tags`).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 3))
+		mock.ExpectQuery(`SELECT (.+) FROM questions q JOIN quiz_questions qq ON (.+) WHERE qq.quiz_id IN \(\$1,\$2\)`).
+			WithArgs(1, 2).
+			WillReturnRows(rows)
 
-	ctx := context.Background()
-	err := repo.AssociateTags(ctx, quizID, tagIDs)
+		ctx := context.Background()
+		questions, err := repo.FindQuestionsByQuizIDs(ctx, []int{1, 2})
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if len(questions[1]) != 2 {
+			t.Errorf("expected 2 questions for quiz 1, got %d", len(questions[1]))
+		}
+
+		if len(questions[2]) != 1 {
+			t.Fatalf("expected 1 question for quiz 2, got %d", len(questions[2]))
+		}
+
+		if questions[2][0].ID != 1 || *questions[2][0].Points != 3 {
+			t.Errorf("expected question 1 worth 3 points in quiz 2, got question %d worth %v", questions[2][0].ID, questions[2][0].Points)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
-func TestQuizRepository_AssociateTags_Empty(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+func TestQuizRepository_AssociateTags(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	repo := NewQuizRepository(bunDB)
+		quizID := 1
+		tagIDs := []string{"1", "2", "3"}
 
-	quizID := 1
-	tagIDs := []string{}
+		mock.ExpectExec(`INSERT INTO quiz_tags`).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 3))
 
-	ctx := context.Background()
-	err := repo.AssociateTags(ctx, quizID, tagIDs)
+		ctx := context.Background()
+		err := repo.AssociateTags(ctx, quizID, tagIDs)
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
-func TestQuizRepository_ClearTags(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+func TestQuizRepository_AssociateTags_Empty(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
+
+		quizID := 1
+		tagIDs := []string{}
 
-	repo := NewQuizRepository(bunDB)
+		ctx := context.Background()
+		err := repo.AssociateTags(ctx, quizID, tagIDs)
 
-	quizID := 1
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	mock.ExpectExec(`DELETE FROM quiz_tags`).
-		WithArgs(sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 3))
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
 
-	ctx := context.Background()
-	err := repo.ClearTags(ctx, quizID)
+func TestQuizRepository_ClearTags(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		quizID := 1
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+		mock.ExpectExec(`DELETE FROM quiz_tags`).
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		ctx := context.Background()
+		err := repo.ClearTags(ctx, quizID)
+
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
 
 func TestQuizRepository_FindTagsByQuizID(t *testing.T) {
-	bunDB, mock, cleanup := setupMockDB(t)
-	defer cleanup()
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
 
-	repo := NewQuizRepository(bunDB)
+		quizID := 1
 
-	quizID := 1
+		rows := sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "Tag 1").
+			AddRow(2, "Tag 2")
 
-	rows := sqlmock.NewRows([]string{"id", "name"}).
-		AddRow(1, "Tag 1").
-		AddRow(2, "Tag 2")
+		mock.ExpectQuery(`SELECT (.+) FROM tags`).
+			WithArgs(sqlmock.AnyArg()).
+			WillReturnRows(rows)
 
-	mock.ExpectQuery(`SELECT (.+) FROM tags`).
-		WithArgs(sqlmock.AnyArg()).
-		WillReturnRows(rows)
+		ctx := context.Background()
+		tags, err := repo.FindTagsByQuizID(ctx, quizID)
 
-	ctx := context.Background()
-	tags, err := repo.FindTagsByQuizID(ctx, quizID)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
 
-	if err != nil {
-		t.Errorf("unexpected error: %v", err)
-	}
+		if len(tags) != 2 {
+			t.Errorf("expected 2 tags, got %d", len(tags))
+		}
 
-	if len(tags) != 2 {
-		t.Errorf("expected 2 tags, got %d", len(tags))
-	}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
 
-	if err := mock.ExpectationsWereMet(); err != nil {
-		t.Errorf("unfulfilled expectations: %v", err)
-	}
+func TestQuizRepository_Duplicate(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT description FROM quizzes WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"description"}).AddRow("About {{topic}}"))
+		mock.ExpectQuery(`INSERT INTO quizzes \(title,description\)`).
+			WithArgs("Copy", "About Go").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+		mock.ExpectQuery(`SELECT (.+) FROM questions q JOIN quiz_questions qq`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "type", "content", "content_format", "options", "payload", "correct_answer", "grading", "explanation", "difficulty", "position", "points"}).
+				AddRow(10, "SHORT_ANSWER", "Explain {{topic}}", "MARKDOWN", nil, nil, "{{topic}} answer", "EXACT", nil, "EASY", 0, 2))
+		mock.ExpectQuery(`INSERT INTO questions`).
+			WithArgs("SHORT_ANSWER", "Explain Go", "MARKDOWN", sqlmock.AnyArg(), nil, "Go answer", "EXACT", nil, "EASY").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(20))
+		mock.ExpectExec(`INSERT INTO quiz_questions`).
+			WithArgs(2, 20, 0, 2).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO question_tags \(question_id,tag_id\) SELECT CAST\(\$1 AS INTEGER\), tag_id FROM question_tags WHERE question_id = \$2`).
+			WithArgs(20, 10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO attachments \(question_id,placement,filename,content_type,size,storage_key\) SELECT CAST\(\$1 AS INTEGER\), placement, filename, content_type, size, storage_key FROM attachments WHERE question_id = \$2 ORDER BY id ASC`).
+			WithArgs(20, 10).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`INSERT INTO quiz_tags \(quiz_id,tag_id\) SELECT CAST\(\$1 AS INTEGER\), tag_id FROM quiz_tags WHERE quiz_id = \$2`).
+			WithArgs(2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		replace := func(s string) string {
+			return strings.ReplaceAll(s, "{{topic}}", "Go")
+		}
+
+		ctx := context.Background()
+		id, err := repo.Duplicate(ctx, 1, "Copy", true, replace)
+
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if id != 2 {
+			t.Errorf("expected id 2, got %d", id)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestQuizRepository_Duplicate_RollbackOnError(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewQuizRepository(bunDB)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT description FROM quizzes WHERE id = \$1`).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"description"}).AddRow(nil))
+		mock.ExpectQuery(`INSERT INTO quizzes`).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		ctx := context.Background()
+		_, err := repo.Duplicate(ctx, 1, "Copy", false, nil)
+
+		if err != sql.ErrConnDone {
+			t.Errorf("expected sql.ErrConnDone, got %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
 }
@@ -20,13 +20,41 @@ type DBExecutor interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 }
 
+// ExecTxQuery executes a query on the given executor, typically the *sql.Tx of a bun.Tx
+func ExecTxQuery(ctx context.Context, exec DBExecutor, sqlBuilder squirrel.Sqlizer) (sql.Result, error) {
+	sqlStr, args, err := sqlBuilder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, done := startQuery(ctx, sqlStr, args)
+	ret, err := exec.ExecContext(ctx, sqlStr, args...)
+	done(err)
+	return ret, err
+}
+
+// ExecTxQueryWithReturning executes a query on the given executor and scans the returned value
+func ExecTxQueryWithReturning[T any](ctx context.Context, exec DBExecutor, sqlBuilder squirrel.Sqlizer, returningValue *T) error {
+	sqlStr, args, err := sqlBuilder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	ctx, done := startQuery(ctx, sqlStr, args)
+	err = exec.QueryRowContext(ctx, sqlStr, args...).Scan(returningValue)
+	done(err)
+	return err
+}
+
 func ExecQuery(ctx context.Context, db *bun.DB, sqlBuilder squirrel.Sqlizer) (sql.Result, error) {
 	sqlStr, args, err := sqlBuilder.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
+	ctx, done := startQuery(ctx, sqlStr, args)
 	ret, err := db.DB.ExecContext(ctx, sqlStr, args...)
+	done(err)
 	return ret, err
 }
 
@@ -36,15 +64,21 @@ func ExecQueryWithReturning[T any](ctx context.Context, db *bun.DB, sqlBuilder s
 		return err
 	}
 
-	return db.DB.QueryRowContext(ctx, sqlStr, args...).Scan(returningValue)
+	ctx, done := startQuery(ctx, sqlStr, args)
+	err = db.DB.QueryRowContext(ctx, sqlStr, args...).Scan(returningValue)
+	done(err)
+	return err
 }
 
-func FindOne[T any](ctx context.Context, db *bun.DB, query squirrel.SelectBuilder) (*T, error) {
+func FindOne[T any](ctx context.Context, db *bun.DB, query squirrel.SelectBuilder) (_ *T, err error) {
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
+	ctx, done := startQuery(ctx, sqlStr, args)
+	defer func() { done(err) }()
+
 	rows, err := db.DB.QueryContext(ctx, sqlStr, args...)
 	if err != nil {
 		return nil, err
@@ -67,12 +101,15 @@ func FindOne[T any](ctx context.Context, db *bun.DB, query squirrel.SelectBuilde
 	return &record, nil
 }
 
-func FindAll[T any](ctx context.Context, db *bun.DB, query squirrel.SelectBuilder) ([]*T, error) {
+func FindAll[T any](ctx context.Context, db *bun.DB, query squirrel.SelectBuilder) (_ []*T, err error) {
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
+	ctx, done := startQuery(ctx, sqlStr, args)
+	defer func() { done(err) }()
+
 	rows, err := db.DB.QueryContext(ctx, sqlStr, args...)
 	if err != nil {
 		return nil, err
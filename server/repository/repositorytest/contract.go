@@ -0,0 +1,1334 @@
+// Package repositorytest holds the contract every repository implementation must satisfy.
+// The SQL repositories run it against SQLite and Postgres, the in-memory ones against a fresh store.
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"quiz-log/models"
+	"quiz-log/repository"
+)
+
+// Repositories bundles the implementations under test, which must share one empty store
+type Repositories struct {
+	Quiz       repository.QuizRepository
+	Question   repository.QuestionRepository
+	Tag        repository.TagRepository
+	Attempt    repository.AttemptRepository
+	Statistics repository.StatisticsRepository
+	Attachment repository.AttachmentRepository
+}
+
+// Run runs the contract as subtests, calling newRepos for a fresh store in each of them
+func Run(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, r Repositories)
+	}{
+		{"QuizCRUD", testQuizCRUD},
+		{"QuizFindAll", testQuizFindAll},
+		{"QuizTags", testQuizTags},
+		{"Tags", testTags},
+		{"TagHierarchy", testTagHierarchy},
+		{"TagMerge", testTagMerge},
+		{"TagUsage", testTagUsage},
+		{"TagPrefix", testTagPrefix},
+		{"QuestionCRUD", testQuestionCRUD},
+		{"QuestionPayload", testQuestionPayload},
+		{"QuestionTags", testQuestionTags},
+		{"QuestionCreateWithLinks", testQuestionCreateWithLinks},
+		{"QuestionKeywords", testQuestionKeywords},
+		{"QuizMembership", testQuizMembership},
+		{"QuestionBulk", testQuestionBulk},
+		{"QuestionBulkTag", testQuestionBulkTag},
+		{"QuestionMove", testQuestionMove},
+		{"QuestionReorder", testQuestionReorder},
+		{"DeleteCascades", testDeleteCascades},
+		{"Duplicate", testDuplicate},
+		{"Attachments", testAttachments},
+		{"Attempts", testAttempts},
+		{"AttemptWithAnswers", testAttemptWithAnswers},
+		{"Statistics", testStatistics},
+		{"StatisticsRollUp", testStatisticsRollUp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil {
+					f, ok := r.(failure)
+					if !ok {
+						panic(r)
+					}
+					t.Fatalf("%s: unexpected error: %v", f.caller, f.err)
+				}
+			}()
+
+			tt.run(t, newRepos(t))
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// failure carries an unexpected error from must to Run, which fails the test with it
+type failure struct {
+	caller string
+	err    error
+}
+
+// must returns the value of a repository call, failing the test on an error
+func must[T any](v T, err error) T {
+	if err != nil {
+		_, file, line, _ := runtime.Caller(1)
+		panic(failure{caller: fmt.Sprintf("%s:%d", filepath.Base(file), line), err: err})
+	}
+	return v
+}
+
+func check(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func quizIDs(quizzes []*models.Quiz) []int {
+	var ids []int
+	for _, q := range quizzes {
+		ids = append(ids, q.ID)
+	}
+	return ids
+}
+
+func questionIDs(questions []*models.Question) []int {
+	var ids []int
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+	}
+	return ids
+}
+
+func tagNames(tags []*models.Tag) []string {
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func testQuizCRUD(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	id := must(r.Quiz.Create(ctx, "Capitals", ptr("European capitals")))
+
+	quiz := must(r.Quiz.FindByID(ctx, id))
+	if quiz == nil {
+		t.Fatalf("expected quiz %d to exist", id)
+	}
+	if quiz.Title != "Capitals" || quiz.Description == nil || *quiz.Description != "European capitals" {
+		t.Errorf("unexpected quiz: %+v", quiz)
+	}
+	if quiz.Status != "DRAFT" || quiz.IsTemplate {
+		t.Errorf("expected a draft non-template quiz, got status %s and template %v", quiz.Status, quiz.IsTemplate)
+	}
+	if quiz.CreatedAt.IsZero() || quiz.UpdatedAt.IsZero() {
+		t.Errorf("expected timestamps to be set, got %+v", quiz)
+	}
+
+	// Fields left nil are kept
+	check(t, r.Quiz.Update(ctx, id, ptr("World capitals"), nil))
+	quiz = must(r.Quiz.FindByID(ctx, id))
+	if quiz.Title != "World capitals" || quiz.Description == nil || *quiz.Description != "European capitals" {
+		t.Errorf("unexpected quiz after update: %+v", quiz)
+	}
+
+	// Updating or deleting a missing quiz is not an error
+	check(t, r.Quiz.Update(ctx, id+1000, ptr("Missing"), nil))
+	check(t, r.Quiz.Delete(ctx, id+1000))
+
+	check(t, r.Quiz.Delete(ctx, id))
+	if quiz := must(r.Quiz.FindByID(ctx, id)); quiz != nil {
+		t.Errorf("expected deleted quiz to be nil, got %+v", quiz)
+	}
+}
+
+func testQuizFindAll(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	draftID := must(r.Quiz.Create(ctx, "Draft", nil))
+	publishedID := must(r.Quiz.Create(ctx, "Published", nil))
+	templateBID := must(r.Quiz.Create(ctx, "Template B", nil))
+	templateAID := must(r.Quiz.Create(ctx, "Template A", nil))
+
+	check(t, r.Quiz.UpdateStatus(ctx, publishedID, "PUBLISHED"))
+	check(t, r.Quiz.SetTemplate(ctx, templateBID, true))
+	check(t, r.Quiz.SetTemplate(ctx, templateAID, true))
+
+	if ids := quizIDs(must(r.Quiz.FindAll(ctx, "DRAFT"))); !slices.Equal(ids, []int{draftID}) {
+		t.Errorf("expected drafts %v, got %v", []int{draftID}, ids)
+	}
+	if ids := quizIDs(must(r.Quiz.FindAll(ctx, "PUBLISHED"))); !slices.Equal(ids, []int{publishedID}) {
+		t.Errorf("expected published %v, got %v", []int{publishedID}, ids)
+	}
+	if ids := quizIDs(must(r.Quiz.FindAll(ctx, "ARCHIVED"))); len(ids) != 0 {
+		t.Errorf("expected no archived quizzes, got %v", ids)
+	}
+
+	// Templates are ordered by title
+	if ids := quizIDs(must(r.Quiz.FindTemplates(ctx))); !slices.Equal(ids, []int{templateAID, templateBID}) {
+		t.Errorf("expected templates %v, got %v", []int{templateAID, templateBID}, ids)
+	}
+}
+
+func testQuizTags(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	quizID := must(r.Quiz.Create(ctx, "Capitals", nil))
+	otherID := must(r.Quiz.Create(ctx, "Rivers", nil))
+	geography := must(r.Tag.Create(ctx, "geography"))
+	europe := must(r.Tag.Create(ctx, "europe"))
+
+	check(t, r.Quiz.AssociateTags(ctx, quizID, []string{itoa(geography), itoa(europe)}))
+	check(t, r.Quiz.AssociateTags(ctx, otherID, []string{itoa(geography)}))
+	check(t, r.Quiz.AssociateTags(ctx, quizID, nil))
+
+	// Tags are ordered by name
+	if names := tagNames(must(r.Quiz.FindTagsByQuizID(ctx, quizID))); !slices.Equal(names, []string{"europe", "geography"}) {
+		t.Errorf("expected tags [europe geography], got %v", names)
+	}
+
+	byQuiz := must(r.Quiz.FindTagsByQuizIDs(ctx, []int{quizID, otherID, otherID + 1000}))
+	if len(byQuiz) != 2 || len(byQuiz[quizID]) != 2 || len(byQuiz[otherID]) != 1 {
+		t.Errorf("unexpected tags by quiz: %v", byQuiz)
+	}
+	if byQuiz := must(r.Quiz.FindTagsByQuizIDs(ctx, nil)); len(byQuiz) != 0 {
+		t.Errorf("expected no tags for no quizzes, got %v", byQuiz)
+	}
+
+	if err := r.Quiz.AssociateTags(ctx, quizID, []string{"abc"}); err == nil {
+		t.Error("expected an error for a malformed tag ID")
+	}
+	if err := r.Quiz.AssociateTags(ctx, quizID, []string{itoa(europe + 1000)}); err == nil {
+		t.Error("expected an error for a missing tag")
+	}
+	if err := r.Quiz.AssociateTags(ctx, quizID, []string{itoa(europe)}); err == nil {
+		t.Error("expected an error for a tag already associated")
+	}
+
+	check(t, r.Quiz.ClearTags(ctx, quizID))
+	if tags := must(r.Quiz.FindTagsByQuizID(ctx, quizID)); len(tags) != 0 {
+		t.Errorf("expected no tags after clearing, got %v", tagNames(tags))
+	}
+	if tags := must(r.Quiz.FindTagsByQuizID(ctx, otherID)); len(tags) != 1 {
+		t.Errorf("expected clearing to keep the tags of other quizzes, got %v", tagNames(tags))
+	}
+}
+
+func testTags(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	if tags := must(r.Tag.FindAll(ctx)); len(tags) != 0 {
+		t.Errorf("expected no tags, got %v", tagNames(tags))
+	}
+
+	history := must(r.Tag.Create(ctx, "history"))
+	art := must(r.Tag.Create(ctx, "art"))
+
+	// Creating an existing name returns its ID
+	if id := must(r.Tag.Create(ctx, "history")); id != history {
+		t.Errorf("expected existing tag %d, got %d", history, id)
+	}
+	if history == art {
+		t.Errorf("expected distinct IDs, got %d twice", art)
+	}
+
+	if names := tagNames(must(r.Tag.FindAll(ctx))); !slices.Equal(names, []string{"art", "history"}) {
+		t.Errorf("expected tags [art history], got %v", names)
+	}
+}
+
+func testTagHierarchy(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	goID := must(r.Tag.Create(ctx, "Go"))
+	concurrency := must(r.Tag.Create(ctx, "Concurrency"))
+	channels := must(r.Tag.Create(ctx, "Chanels"))
+
+	check(t, r.Tag.Rename(ctx, channels, "Channels"))
+	if err := r.Tag.Rename(ctx, channels, "Go"); err == nil {
+		t.Error("expected an error renaming to a name in use")
+	}
+
+	check(t, r.Tag.SetParent(ctx, concurrency, &goID))
+	check(t, r.Tag.SetParent(ctx, channels, &concurrency))
+	if err := r.Tag.SetParent(ctx, channels, ptr(channels+1000)); err == nil {
+		t.Error("expected an error for a missing parent")
+	}
+	for _, parent := range []int{goID, channels} {
+		if err := r.Tag.SetParent(ctx, goID, &parent); err == nil {
+			t.Errorf("expected an error moving the tag under %d", parent)
+		}
+	}
+	if tag := must(r.Tag.FindByID(ctx, goID)); tag.ParentID != nil {
+		t.Errorf("expected a rejected move to leave the tag in place, got parent %d", *tag.ParentID)
+	}
+
+	// Creating under a parent never moves an existing tag
+	goroutines := must(r.Tag.CreateWithParent(ctx, "Goroutines", concurrency))
+	if tag := must(r.Tag.FindByID(ctx, goroutines)); tag == nil || tag.ParentID == nil || *tag.ParentID != concurrency {
+		t.Errorf("expected the new tag under %d, got %+v", concurrency, tag)
+	}
+	if _, err := r.Tag.CreateWithParent(ctx, "Go", concurrency); err == nil {
+		t.Error("expected an error creating a tag with a name in use")
+	}
+	if _, err := r.Tag.CreateWithParent(ctx, "Select", channels+1000); err == nil {
+		t.Error("expected an error creating under a missing parent")
+	}
+	check(t, r.Tag.Delete(ctx, goroutines))
+
+	tag := must(r.Tag.FindByID(ctx, channels))
+	if tag == nil || tag.Name != "Channels" || tag.ParentID == nil || *tag.ParentID != concurrency {
+		t.Fatalf("unexpected tag: %+v", tag)
+	}
+	if tag := must(r.Tag.FindByID(ctx, channels+1000)); tag != nil {
+		t.Errorf("expected a missing tag to be nil, got %+v", tag)
+	}
+
+	if names := tagNames(must(r.Tag.FindByIDs(ctx, []int{channels, goID, channels + 1000}))); !slices.Equal(names, []string{"Go", "Channels"}) {
+		t.Errorf("expected tags [Go Channels] in ID order, got %v", names)
+	}
+
+	children := must(r.Tag.FindByParentIDs(ctx, []int{goID, concurrency, channels}))
+	if names := tagNames(children[goID]); !slices.Equal(names, []string{"Concurrency"}) {
+		t.Errorf("expected children [Concurrency], got %v", names)
+	}
+	if names := tagNames(children[concurrency]); !slices.Equal(names, []string{"Channels"}) {
+		t.Errorf("expected children [Channels], got %v", names)
+	}
+	if len(children[channels]) != 0 {
+		t.Errorf("expected no children, got %v", tagNames(children[channels]))
+	}
+
+	// Tags of quizzes and questions carry their parent
+	quizID := must(r.Quiz.Create(ctx, "Goroutines", nil))
+	questionID := must(r.Question.Create(ctx, "TRUE_FALSE", "Channels can be closed", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	check(t, r.Quiz.AssociateTags(ctx, quizID, []string{itoa(concurrency)}))
+	check(t, r.Question.AssociateTags(ctx, questionID, []string{itoa(concurrency)}))
+
+	if tags := must(r.Quiz.FindTagsByQuizIDs(ctx, []int{quizID})); len(tags[quizID]) != 1 || tags[quizID][0].ParentID == nil || *tags[quizID][0].ParentID != goID {
+		t.Errorf("expected the quiz tag under %d, got %+v", goID, tags[quizID])
+	}
+
+	// Deleting a tag moves its children up to its parent and removes its associations
+	check(t, r.Tag.Delete(ctx, concurrency))
+
+	if tag := must(r.Tag.FindByID(ctx, channels)); tag.ParentID == nil || *tag.ParentID != goID {
+		t.Errorf("expected the child to move under %d, got %v", goID, tag.ParentID)
+	}
+	if tags := must(r.Quiz.FindTagsByQuizID(ctx, quizID)); len(tags) != 0 {
+		t.Errorf("expected the quiz tag to be removed, got %v", tagNames(tags))
+	}
+	if tags := must(r.Question.FindTagsByQuestionID(ctx, questionID)); len(tags) != 0 {
+		t.Errorf("expected the question tag to be removed, got %v", tagNames(tags))
+	}
+
+	check(t, r.Tag.SetParent(ctx, channels, nil))
+	if tag := must(r.Tag.FindByID(ctx, channels)); tag.ParentID != nil {
+		t.Errorf("expected a top-level tag, got parent %d", *tag.ParentID)
+	}
+}
+
+func testTagMerge(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	target := must(r.Tag.Create(ctx, "golang"))
+	typo := must(r.Tag.Create(ctx, "goalng"))
+	alias := must(r.Tag.Create(ctx, "go"))
+	child := must(r.Tag.Create(ctx, "generics"))
+	check(t, r.Tag.SetParent(ctx, child, &alias))
+
+	both := must(r.Quiz.Create(ctx, "Tagged twice", nil))
+	single := must(r.Quiz.Create(ctx, "Tagged once", nil))
+	check(t, r.Quiz.AssociateTags(ctx, both, []string{itoa(target), itoa(typo), itoa(alias)}))
+	check(t, r.Quiz.AssociateTags(ctx, single, []string{itoa(typo)}))
+
+	questionID := must(r.Question.Create(ctx, "TRUE_FALSE", "Go has generics", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	check(t, r.Question.AssociateTags(ctx, questionID, []string{itoa(typo), itoa(alias)}))
+
+	// Execute
+	check(t, r.Tag.Merge(ctx, []int{typo, alias}, target))
+
+	// Every item keeps a single association with the target
+	byQuiz := must(r.Quiz.FindTagsByQuizIDs(ctx, []int{both, single}))
+	for _, quizID := range []int{both, single} {
+		if names := tagNames(byQuiz[quizID]); !slices.Equal(names, []string{"golang"}) {
+			t.Errorf("expected quiz %d tagged [golang], got %v", quizID, names)
+		}
+	}
+	if names := tagNames(must(r.Question.FindTagsByQuestionID(ctx, questionID))); !slices.Equal(names, []string{"golang"}) {
+		t.Errorf("expected the question tagged [golang], got %v", names)
+	}
+
+	if names := tagNames(must(r.Tag.FindAll(ctx))); !slices.Equal(names, []string{"generics", "golang"}) {
+		t.Errorf("expected the sources to be deleted, got %v", names)
+	}
+	if tag := must(r.Tag.FindByID(ctx, child)); tag.ParentID == nil || *tag.ParentID != target {
+		t.Errorf("expected the child of a source to move under %d, got %v", target, tag.ParentID)
+	}
+}
+
+func testTagUsage(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	popular := must(r.Tag.Create(ctx, "popular"))
+	unused := must(r.Tag.Create(ctx, "unused"))
+
+	first := must(r.Quiz.Create(ctx, "First", nil))
+	second := must(r.Quiz.Create(ctx, "Second", nil))
+	check(t, r.Quiz.AssociateTags(ctx, first, []string{itoa(popular)}))
+	check(t, r.Quiz.AssociateTags(ctx, second, []string{itoa(popular)}))
+
+	questionID := must(r.Question.Create(ctx, "TRUE_FALSE", "Popular question", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	check(t, r.Question.AssociateTags(ctx, questionID, []string{itoa(popular)}))
+
+	// Execute
+	usages := must(r.Tag.FindUsageByTagIDs(ctx, []int{popular, unused, unused + 1000}))
+
+	if len(usages) != 2 {
+		t.Fatalf("expected the usage of 2 tags, got %d", len(usages))
+	}
+	if u := usages[popular]; u.QuizCount != 2 || u.QuestionCount != 1 || u.LastUsedAt == nil {
+		t.Errorf("unexpected usage of the popular tag: %+v", u)
+	}
+	if u := usages[unused]; u.QuizCount != 0 || u.QuestionCount != 0 || u.LastUsedAt != nil {
+		t.Errorf("unexpected usage of the unused tag: %+v", u)
+	}
+
+	if usages := must(r.Tag.FindUsageByTagIDs(ctx, nil)); len(usages) != 0 {
+		t.Errorf("expected no usage without IDs, got %d", len(usages))
+	}
+}
+
+func testTagPrefix(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	must(r.Tag.Create(ctx, "Geography"))
+	geology := must(r.Tag.Create(ctx, "geology"))
+	must(r.Tag.Create(ctx, "history"))
+	must(r.Tag.Create(ctx, "ge_ometry"))
+
+	quizID := must(r.Quiz.Create(ctx, "Rocks", nil))
+	check(t, r.Quiz.AssociateTags(ctx, quizID, []string{itoa(geology)}))
+
+	// Execute
+	tags := must(r.Tag.FindByPrefix(ctx, "GEO", 10))
+
+	// Most used first, then by name, ignoring case
+	if names := tagNames(tags); !slices.Equal(names, []string{"geology", "Geography"}) {
+		t.Errorf("expected [geology Geography], got %v", names)
+	}
+
+	if names := tagNames(must(r.Tag.FindByPrefix(ctx, "ge", 1))); !slices.Equal(names, []string{"geology"}) {
+		t.Errorf("expected the limit to keep [geology], got %v", names)
+	}
+
+	// Wildcards in the prefix match literally
+	if names := tagNames(must(r.Tag.FindByPrefix(ctx, "ge_", 10))); !slices.Equal(names, []string{"ge_ometry"}) {
+		t.Errorf("expected [ge_ometry], got %v", names)
+	}
+	if tags := must(r.Tag.FindByPrefix(ctx, "%", 10)); len(tags) != 0 {
+		t.Errorf("expected no tag to start with %%, got %v", tagNames(tags))
+	}
+}
+
+func testQuestionCRUD(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	id := must(r.Question.Create(ctx, "MULTIPLE_CHOICE", "Capital of France?", "PLAIN", []string{"Paris", "Lyon"}, models.Payload{}, "Paris", "EXACT", ptr("Seat of government"), "EASY"))
+
+	question := must(r.Question.FindByID(ctx, id))
+	if question == nil {
+		t.Fatalf("expected question %d to exist", id)
+	}
+	if question.Type != "MULTIPLE_CHOICE" || question.Content != "Capital of France?" || question.CorrectAnswer != "Paris" || question.Difficulty != "EASY" {
+		t.Errorf("unexpected question: %+v", question)
+	}
+	if !slices.Equal(question.Options, []string{"Paris", "Lyon"}) {
+		t.Errorf("expected options [Paris Lyon], got %v", question.Options)
+	}
+	if question.Explanation == nil || *question.Explanation != "Seat of government" {
+		t.Errorf("unexpected explanation: %v", question.Explanation)
+	}
+	if question.QuizID != nil || question.Position != nil || question.Points != nil {
+		t.Errorf("expected no quiz membership outside a quiz, got %+v", question)
+	}
+
+	// Fields left nil are kept
+	check(t, r.Question.Update(ctx, id, nil, ptr("Capital of Italy?"), nil, []string{"Rome", "Milan"}, nil, ptr("Rome"), nil, nil, nil))
+	question = must(r.Question.FindByID(ctx, id))
+	if question.Content != "Capital of Italy?" || question.CorrectAnswer != "Rome" || question.Type != "MULTIPLE_CHOICE" || question.Difficulty != "EASY" {
+		t.Errorf("unexpected question after update: %+v", question)
+	}
+	if !slices.Equal(question.Options, []string{"Rome", "Milan"}) {
+		t.Errorf("expected options [Rome Milan], got %v", question.Options)
+	}
+	if question.ContentFormat != "PLAIN" || question.Grading != "EXACT" {
+		t.Errorf("expected the content format and grading to be kept, got %s and %s", question.ContentFormat, question.Grading)
+	}
+
+	check(t, r.Question.Update(ctx, id, nil, nil, ptr("MARKDOWN"), nil, nil, nil, nil, nil, nil))
+	if question := must(r.Question.FindByID(ctx, id)); question.ContentFormat != "MARKDOWN" {
+		t.Errorf("expected content format MARKDOWN, got %s", question.ContentFormat)
+	}
+
+	check(t, r.Question.Update(ctx, id, nil, nil, nil, nil, nil, nil, ptr("PARTIAL"), nil, nil))
+	if question := must(r.Question.FindByID(ctx, id)); question.Grading != "PARTIAL" {
+		t.Errorf("expected grading PARTIAL, got %s", question.Grading)
+	}
+
+	other := must(r.Question.Create(ctx, "TRUE_FALSE", "The Earth is **flat**", "MARKDOWN", nil, models.Payload{}, "false", "EXACT", nil, "EASY"))
+	if question := must(r.Question.FindByID(ctx, other)); question.ContentFormat != "MARKDOWN" {
+		t.Errorf("expected content format MARKDOWN, got %s", question.ContentFormat)
+	}
+	if ids := questionIDs(must(r.Question.FindAll(ctx, nil))); len(ids) != 2 || !slices.Contains(ids, id) || !slices.Contains(ids, other) {
+		t.Errorf("expected questions %d and %d, got %v", id, other, ids)
+	}
+
+	check(t, r.Question.Delete(ctx, id))
+	if question := must(r.Question.FindByID(ctx, id)); question != nil {
+		t.Errorf("expected deleted question to be nil, got %+v", question)
+	}
+}
+
+func testQuestionPayload(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	pairs := []models.MatchingPair{{Prompt: "H2O", Answer: "water"}, {Prompt: "NaCl", Answer: "salt"}}
+	id := must(r.Question.Create(ctx, "MATCHING", "Match the compounds", "PLAIN", nil, models.Payload{Pairs: pairs}, "", "PARTIAL", nil, "EASY"))
+
+	question := must(r.Question.FindByID(ctx, id))
+	if !slices.Equal(question.Payload.Pairs, pairs) || question.Grading != "PARTIAL" {
+		t.Errorf("expected pairs %v graded PARTIAL, got %+v", pairs, question)
+	}
+	if questions := must(r.Question.FindByIDs(ctx, []int{id})); len(questions) != 1 || !slices.Equal(questions[0].Payload.Pairs, pairs) {
+		t.Errorf("expected the pairs from FindByIDs, got %v", questions)
+	}
+
+	// A nil payload is kept, an empty one clears the pairs
+	check(t, r.Question.Update(ctx, id, nil, ptr("Match them"), nil, nil, nil, nil, nil, nil, nil))
+	if question := must(r.Question.FindByID(ctx, id)); !slices.Equal(question.Payload.Pairs, pairs) {
+		t.Errorf("expected the pairs to be kept, got %v", question.Payload.Pairs)
+	}
+
+	pairs = append(pairs, models.MatchingPair{Prompt: "CO2", Answer: "carbon dioxide"})
+	check(t, r.Question.Update(ctx, id, nil, nil, nil, nil, &models.Payload{Pairs: pairs}, nil, nil, nil, nil))
+	if question := must(r.Question.FindByID(ctx, id)); !slices.Equal(question.Payload.Pairs, pairs) {
+		t.Errorf("expected pairs %v, got %v", pairs, question.Payload.Pairs)
+	}
+
+	quizID := must(r.Quiz.Create(ctx, "Chemistry", nil))
+	check(t, r.Question.AddToQuiz(ctx, quizID, id, 1))
+	copyID := must(r.Quiz.Duplicate(ctx, quizID, "Copy", false, strings.ToUpper))
+	questions := must(r.Quiz.FindQuestionsByQuizID(ctx, copyID))
+	if len(questions) != 1 || len(questions[0].Payload.Pairs) != 3 || questions[0].Payload.Pairs[2] != (models.MatchingPair{Prompt: "CO2", Answer: "CARBON DIOXIDE"}) {
+		t.Errorf("expected the pairs copied with replace, got %v", questions)
+	}
+
+	check(t, r.Question.Update(ctx, id, nil, nil, nil, nil, &models.Payload{}, nil, nil, nil, nil))
+	if question := must(r.Question.FindByID(ctx, id)); len(question.Payload.Pairs) != 0 {
+		t.Errorf("expected no pairs, got %v", question.Payload.Pairs)
+	}
+	if source := must(r.Quiz.FindQuestionsByQuizID(ctx, quizID)); len(source[0].Payload.Pairs) != 0 {
+		t.Errorf("expected the source to lose its pairs, got %v", source[0].Payload.Pairs)
+	}
+	if copied := must(r.Quiz.FindQuestionsByQuizID(ctx, copyID)); len(copied[0].Payload.Pairs) != 3 {
+		t.Errorf("expected the copy to keep its pairs, got %v", copied[0].Payload.Pairs)
+	}
+
+	blanks := []models.ClozeBlank{{Accepted: []string{"Seine", "la Seine"}, Strategy: "IGNORE_CASE"}, {Accepted: []string{"777"}, Strategy: "NUMERIC"}}
+	clozeID := must(r.Question.Create(ctx, "CLOZE", "The {{1}} is {{2}} km long", "PLAIN", nil, models.Payload{Blanks: blanks}, "", "PARTIAL", nil, "MEDIUM"))
+	if question := must(r.Question.FindByID(ctx, clozeID)); len(question.Payload.Blanks) != 2 || !slices.Equal(question.Payload.Blanks[0].Accepted, blanks[0].Accepted) || question.Payload.Blanks[1].Strategy != "NUMERIC" {
+		t.Errorf("expected blanks %v, got %v", blanks, question.Payload.Blanks)
+	}
+
+	// Accepted answers are copied with replace, unless compared as numbers
+	clozeQuiz := must(r.Quiz.Create(ctx, "Rivers of France", nil))
+	check(t, r.Question.AddToQuiz(ctx, clozeQuiz, clozeID, 1))
+	clozeCopy := must(r.Quiz.FindQuestionsByQuizID(ctx, must(r.Quiz.Duplicate(ctx, clozeQuiz, "Copy", false, strings.ToUpper))))
+	if copied := clozeCopy[0].Payload.Blanks; len(copied) != 2 || !slices.Equal(copied[0].Accepted, []string{"SEINE", "LA SEINE"}) || !slices.Equal(copied[1].Accepted, []string{"777"}) {
+		t.Errorf("unexpected copied blanks %v", copied)
+	}
+
+	numeric := &models.NumericAnswer{Tolerance: 0.05, ToleranceMode: "RELATIVE", Units: []string{"km"}}
+	numericID := must(r.Question.Create(ctx, "NUMERIC", "Length of the Nile", "PLAIN", nil, models.Payload{Numeric: numeric}, "6650", "EXACT", nil, "HARD"))
+	question = must(r.Question.FindByID(ctx, numericID))
+	if n := question.Payload.Numeric; n == nil || n.Tolerance != 0.05 || n.ToleranceMode != "RELATIVE" || !slices.Equal(n.Units, []string{"km"}) || question.Payload.Pairs != nil {
+		t.Errorf("expected tolerance %+v, got %+v", numeric, question.Payload)
+	}
+
+	// Values and units are copied as they are
+	numericQuiz := must(r.Quiz.Create(ctx, "Rivers", nil))
+	check(t, r.Question.AddToQuiz(ctx, numericQuiz, numericID, 1))
+	numericCopy := must(r.Quiz.FindQuestionsByQuizID(ctx, must(r.Quiz.Duplicate(ctx, numericQuiz, "Copy", false, func(s string) string { return s + "!" }))))
+	if n := numericCopy[0].Payload.Numeric; n == nil || !slices.Equal(n.Units, []string{"km"}) || numericCopy[0].CorrectAnswer != "6650" || numericCopy[0].Content != "Length of the Nile!" {
+		t.Errorf("expected the value and units copied unchanged, got %+v", numericCopy[0])
+	}
+}
+
+func testQuestionTags(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	id := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	geography := must(r.Tag.Create(ctx, "geography"))
+	europe := must(r.Tag.Create(ctx, "europe"))
+
+	check(t, r.Question.AssociateTags(ctx, id, []string{itoa(geography), itoa(europe)}))
+	if names := tagNames(must(r.Question.FindTagsByQuestionID(ctx, id))); !slices.Equal(names, []string{"europe", "geography"}) {
+		t.Errorf("expected tags [europe geography], got %v", names)
+	}
+
+	if err := r.Question.AssociateTags(ctx, id+1000, []string{itoa(europe)}); err == nil {
+		t.Error("expected an error for a missing question")
+	}
+
+	check(t, r.Question.ClearTags(ctx, id))
+	if tags := must(r.Question.FindTagsByQuestionID(ctx, id)); len(tags) != 0 {
+		t.Errorf("expected no tags after clearing, got %v", tagNames(tags))
+	}
+}
+
+func testQuestionCreateWithLinks(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	quizID := must(r.Quiz.Create(ctx, "Capitals", nil))
+	geography := must(r.Tag.Create(ctx, "geography"))
+	first := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	check(t, r.Question.AddToQuiz(ctx, quizID, first, 1))
+
+	id := must(r.Question.CreateWithLinks(ctx, "TRUE_FALSE", "Rome is in Spain", "PLAIN", nil, models.Payload{}, "false", "EXACT", nil, "EASY", []string{itoa(geography)}, &quizID, 3))
+	if names := tagNames(must(r.Question.FindTagsByQuestionID(ctx, id))); !slices.Equal(names, []string{"geography"}) {
+		t.Errorf("expected tags [geography], got %v", names)
+	}
+	questions := must(r.Quiz.FindQuestionsByQuizID(ctx, quizID))
+	if ids := questionIDs(questions); !slices.Equal(ids, []int{first, id}) {
+		t.Fatalf("expected questions %v in order, got %v", []int{first, id}, ids)
+	}
+	if q := questions[1]; q.Position == nil || *q.Position != 1 || q.Points == nil || *q.Points != 3 {
+		t.Errorf("unexpected membership of question %d: position %v, points %v", q.ID, q.Position, q.Points)
+	}
+
+	// A missing tag or quiz leaves no question behind
+	missing := quizID + 1000
+	if _, err := r.Question.CreateWithLinks(ctx, "TRUE_FALSE", "Orphan", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY", []string{itoa(geography + 1000)}, nil, 1); err == nil {
+		t.Error("expected an error for a missing tag")
+	}
+	if _, err := r.Question.CreateWithLinks(ctx, "TRUE_FALSE", "Orphan", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY", []string{itoa(geography)}, &missing, 1); err == nil {
+		t.Error("expected an error for a missing quiz")
+	}
+	if ids := questionIDs(must(r.Question.FindAll(ctx, nil))); len(ids) != 2 || !slices.Contains(ids, first) || !slices.Contains(ids, id) {
+		t.Errorf("expected only questions %v, got %v", []int{first, id}, ids)
+	}
+}
+
+func testQuestionKeywords(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	geography := must(r.Tag.Create(ctx, "geography"))
+	capital := must(r.Question.Create(ctx, "SHORT_ANSWER", "What is the CAPITAL of France?", "PLAIN", nil, models.Payload{}, "Paris", "EXACT", nil, "EASY"))
+	river := must(r.Question.Create(ctx, "SHORT_ANSWER", "Which river flows through Paris?", "PLAIN", nil, models.Payload{}, "Seine", "EXACT", nil, "EASY"))
+	must(r.Question.Create(ctx, "SHORT_ANSWER", "What is the capital of Spain?", "PLAIN", nil, models.Payload{}, "Madrid", "EXACT", nil, "EASY"))
+	check(t, r.Question.AssociateTags(ctx, capital, []string{itoa(geography)}))
+	check(t, r.Question.AssociateTags(ctx, river, []string{itoa(geography)}))
+
+	// Execute
+	questions := must(r.Question.FindTaggedByKeywords(ctx, []string{"capital", "river"}, 10))
+
+	// Untagged questions are left out
+	if ids := questionIDs(questions); len(ids) != 2 || !slices.Contains(ids, capital) || !slices.Contains(ids, river) {
+		t.Errorf("expected questions %d and %d, got %v", capital, river, ids)
+	}
+	if questions := must(r.Question.FindTaggedByKeywords(ctx, []string{"capital", "river"}, 1)); len(questions) != 1 {
+		t.Errorf("expected the limit to keep 1 question, got %d", len(questions))
+	}
+	if questions := must(r.Question.FindTaggedByKeywords(ctx, nil, 10)); len(questions) != 0 {
+		t.Errorf("expected no questions without keywords, got %v", questionIDs(questions))
+	}
+
+	byQuestion := must(r.Question.FindTagsByQuestionIDs(ctx, []int{capital, river}))
+	for _, id := range []int{capital, river} {
+		if names := tagNames(byQuestion[id]); !slices.Equal(names, []string{"geography"}) {
+			t.Errorf("expected question %d tagged [geography], got %v", id, names)
+		}
+	}
+}
+
+func testQuizMembership(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	quizID := must(r.Quiz.Create(ctx, "Capitals", nil))
+	first := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	second := must(r.Question.Create(ctx, "TRUE_FALSE", "Rome is in Spain", "PLAIN", nil, models.Payload{}, "false", "EXACT", nil, "EASY"))
+
+	// Adding a member again only updates its points
+	check(t, r.Question.AddToQuiz(ctx, quizID, first, 1))
+	check(t, r.Question.AddToQuiz(ctx, quizID, second, 2))
+	check(t, r.Question.AddToQuiz(ctx, quizID, first, 5))
+
+	questions := must(r.Quiz.FindQuestionsByQuizID(ctx, quizID))
+	if ids := questionIDs(questions); !slices.Equal(ids, []int{first, second}) {
+		t.Fatalf("expected questions %v in order, got %v", []int{first, second}, ids)
+	}
+	for i, want := range []int{5, 2} {
+		q := questions[i]
+		if q.QuizID == nil || *q.QuizID != quizID || q.Position == nil || *q.Position != i || q.Points == nil || *q.Points != want {
+			t.Errorf("unexpected membership of question %d: quiz %v, position %v, points %v", q.ID, q.QuizID, q.Position, q.Points)
+		}
+	}
+
+	if ids := questionIDs(must(r.Question.FindAll(ctx, &quizID))); !slices.Equal(ids, []int{first, second}) {
+		t.Errorf("expected quiz questions %v, got %v", []int{first, second}, ids)
+	}
+	byQuiz := must(r.Quiz.FindQuestionsByQuizIDs(ctx, []int{quizID, quizID + 1000}))
+	if len(byQuiz) != 1 || !slices.Equal(questionIDs(byQuiz[quizID]), []int{first, second}) {
+		t.Errorf("unexpected questions by quiz: %v", byQuiz)
+	}
+	if count := must(r.Attempt.CountQuestionsByQuizID(ctx, quizID)); count != 2 {
+		t.Errorf("expected 2 questions, got %d", count)
+	}
+
+	if err := r.Question.AddToQuiz(ctx, quizID+1000, first, 1); err == nil {
+		t.Error("expected an error for a missing quiz")
+	}
+	if err := r.Question.AddToQuiz(ctx, quizID, second+1000, 1); err == nil {
+		t.Error("expected an error for a missing question")
+	}
+
+	// Removing keeps the question in the bank, and new members go after the last position
+	check(t, r.Question.RemoveFromQuiz(ctx, quizID, first))
+	if question := must(r.Question.FindByID(ctx, first)); question == nil {
+		t.Error("expected a removed question to stay in the bank")
+	}
+	check(t, r.Question.AddToQuiz(ctx, quizID, first, 1))
+
+	questions = must(r.Quiz.FindQuestionsByQuizID(ctx, quizID))
+	if ids := questionIDs(questions); !slices.Equal(ids, []int{second, first}) {
+		t.Fatalf("expected questions %v in order, got %v", []int{second, first}, ids)
+	}
+	if *questions[1].Position != 2 {
+		t.Errorf("expected the re-added question at position 2, got %d", *questions[1].Position)
+	}
+}
+
+func testQuestionBulk(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	first := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	second := must(r.Question.Create(ctx, "TRUE_FALSE", "Rome is in Spain", "PLAIN", nil, models.Payload{}, "false", "EXACT", ptr("Italy"), "EASY"))
+	other := must(r.Question.Create(ctx, "TRUE_FALSE", "Berlin is in Germany", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+
+	// Nil fields are left unchanged
+	check(t, r.Question.BulkUpdate(ctx, []int{first, second}, nil, ptr("HARD")))
+	for _, q := range must(r.Question.FindByIDs(ctx, []int{first, second, other})) {
+		want := "HARD"
+		if q.ID == other {
+			want = "EASY"
+		}
+		if q.Difficulty != want {
+			t.Errorf("expected question %d to be %s, got %s", q.ID, want, q.Difficulty)
+		}
+	}
+	if q := must(r.Question.FindByID(ctx, second)); q.Explanation == nil || *q.Explanation != "Italy" {
+		t.Errorf("expected the explanation to be kept, got %v", q.Explanation)
+	}
+
+	check(t, r.Question.BulkUpdate(ctx, []int{first, second}, ptr("Capitals"), nil))
+	for _, q := range must(r.Question.FindByIDs(ctx, []int{first, second})) {
+		if q.Explanation == nil || *q.Explanation != "Capitals" || q.Difficulty != "HARD" {
+			t.Errorf("unexpected question %d: explanation %v, difficulty %s", q.ID, q.Explanation, q.Difficulty)
+		}
+	}
+
+	// Missing questions are not counted
+	if deleted := must(r.Question.BulkDelete(ctx, []int{first, second, other + 1000})); deleted != 2 {
+		t.Errorf("expected 2 deleted questions, got %d", deleted)
+	}
+	if ids := questionIDs(must(r.Question.FindAll(ctx, nil))); !slices.Equal(ids, []int{other}) {
+		t.Errorf("expected questions [%d] to remain, got %v", other, ids)
+	}
+}
+
+func testQuestionBulkTag(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	first := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	second := must(r.Question.Create(ctx, "TRUE_FALSE", "Rome is in Spain", "PLAIN", nil, models.Payload{}, "false", "EXACT", nil, "EASY"))
+	geography := must(r.Tag.Create(ctx, "geography"))
+	europe := must(r.Tag.Create(ctx, "europe"))
+	draft := must(r.Tag.Create(ctx, "draft"))
+	check(t, r.Question.AssociateTags(ctx, first, []string{itoa(geography), itoa(draft)}))
+
+	// Tags already carried are kept once
+	check(t, r.Question.BulkTag(ctx, []int{first, second}, []int{geography, europe}, []int{draft}))
+
+	byQuestion := must(r.Question.FindTagsByQuestionIDs(ctx, []int{first, second}))
+	for _, id := range []int{first, second} {
+		if names := tagNames(byQuestion[id]); !slices.Equal(names, []string{"europe", "geography"}) {
+			t.Errorf("expected question %d tagged [europe geography], got %v", id, names)
+		}
+	}
+
+	// A missing tag fails the whole operation
+	if err := r.Question.BulkTag(ctx, []int{first}, []int{draft + 1000}, []int{europe}); err == nil {
+		t.Error("expected an error for a missing tag")
+	}
+	if names := tagNames(must(r.Question.FindTagsByQuestionID(ctx, first))); !slices.Equal(names, []string{"europe", "geography"}) {
+		t.Errorf("expected the failed operation to keep [europe geography], got %v", names)
+	}
+}
+
+func testQuestionMove(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	source := must(r.Quiz.Create(ctx, "Capitals", nil))
+	target := must(r.Quiz.Create(ctx, "Europe", nil))
+	other := must(r.Quiz.Create(ctx, "Geography", nil))
+	first := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	second := must(r.Question.Create(ctx, "TRUE_FALSE", "Rome is in Spain", "PLAIN", nil, models.Payload{}, "false", "EXACT", nil, "EASY"))
+	kept := must(r.Question.Create(ctx, "TRUE_FALSE", "Berlin is in Germany", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	bank := must(r.Question.Create(ctx, "TRUE_FALSE", "Madrid is in Spain", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	check(t, r.Question.AddToQuiz(ctx, source, first, 3))
+	check(t, r.Question.AddToQuiz(ctx, source, kept, 1))
+	check(t, r.Question.AddToQuiz(ctx, target, second, 2))
+	check(t, r.Question.AddToQuiz(ctx, other, first, 5))
+
+	// Members of the target move to the end too, and questions not in the source get the default points
+	check(t, r.Question.MoveToQuiz(ctx, []int{bank, second, first}, &source, target, 1))
+
+	if ids := questionIDs(must(r.Quiz.FindQuestionsByQuizID(ctx, source))); !slices.Equal(ids, []int{kept}) {
+		t.Errorf("expected source questions [%d], got %v", kept, ids)
+	}
+	if ids := questionIDs(must(r.Quiz.FindQuestionsByQuizID(ctx, other))); !slices.Equal(ids, []int{first}) {
+		t.Errorf("expected the other quiz to keep question %d, got %v", first, ids)
+	}
+	questions := must(r.Quiz.FindQuestionsByQuizID(ctx, target))
+	if ids := questionIDs(questions); !slices.Equal(ids, []int{bank, second, first}) {
+		t.Fatalf("expected target questions %v in order, got %v", []int{bank, second, first}, ids)
+	}
+	for i, want := range []int{1, 2, 3} {
+		if q := questions[i]; *q.Points != want {
+			t.Errorf("expected question %d to be worth %d points, got %d", q.ID, want, *q.Points)
+		}
+	}
+
+	// Without a source, questions are only added
+	check(t, r.Question.MoveToQuiz(ctx, []int{kept}, nil, target, 1))
+	if ids := questionIDs(must(r.Quiz.FindQuestionsByQuizID(ctx, source))); !slices.Equal(ids, []int{kept}) {
+		t.Errorf("expected source questions [%d], got %v", kept, ids)
+	}
+
+	if err := r.Question.MoveToQuiz(ctx, []int{first}, &target, target+1000, 1); err == nil {
+		t.Error("expected an error for a missing quiz")
+	}
+	if ids := questionIDs(must(r.Quiz.FindQuestionsByQuizID(ctx, target))); !slices.Equal(ids, []int{bank, second, first, kept}) {
+		t.Errorf("expected the failed move to keep %v, got %v", []int{bank, second, first, kept}, ids)
+	}
+}
+
+func testQuestionReorder(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	quizID := must(r.Quiz.Create(ctx, "Capitals", nil))
+	otherID := must(r.Quiz.Create(ctx, "Europe", nil))
+	var ids []int
+	for _, content := range []string{"Paris is in France", "Rome is in Italy", "Berlin is in Germany"} {
+		id := must(r.Question.Create(ctx, "TRUE_FALSE", content, "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+		check(t, r.Question.AddToQuiz(ctx, quizID, id, 1))
+		check(t, r.Question.AddToQuiz(ctx, otherID, id, 1))
+		ids = append(ids, id)
+	}
+
+	// Execute
+	check(t, r.Question.Reorder(ctx, quizID, []int{ids[2], ids[0], ids[1]}))
+
+	// Assert
+	questions := must(r.Quiz.FindQuestionsByQuizID(ctx, quizID))
+	if got := questionIDs(questions); !slices.Equal(got, []int{ids[2], ids[0], ids[1]}) {
+		t.Fatalf("expected questions %v in order, got %v", []int{ids[2], ids[0], ids[1]}, got)
+	}
+	for i, q := range questions {
+		if *q.Position != i {
+			t.Errorf("expected question %d at position %d, got %d", q.ID, i, *q.Position)
+		}
+	}
+	if got := questionIDs(must(r.Quiz.FindQuestionsByQuizID(ctx, otherID))); !slices.Equal(got, ids) {
+		t.Errorf("expected the other quiz to keep %v, got %v", ids, got)
+	}
+}
+
+func testDeleteCascades(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	quizID := must(r.Quiz.Create(ctx, "Capitals", nil))
+	questionID := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	tagID := must(r.Tag.Create(ctx, "geography"))
+	check(t, r.Question.AddToQuiz(ctx, quizID, questionID, 1))
+	check(t, r.Quiz.AssociateTags(ctx, quizID, []string{itoa(tagID)}))
+
+	now := time.Now()
+	attemptID := must(r.Attempt.Create(ctx, quizID, now, now, 1, 1))
+	check(t, r.Attempt.CreateAnswer(ctx, attemptID, questionID, "false", false, 0, nil))
+
+	// Deleting a question removes its answers and memberships
+	otherID := must(r.Question.Create(ctx, "TRUE_FALSE", "Rome is in Italy", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	check(t, r.Question.AddToQuiz(ctx, quizID, otherID, 1))
+	check(t, r.Attempt.CreateAnswer(ctx, attemptID, otherID, "true", true, 1, nil))
+	check(t, r.Question.Delete(ctx, otherID))
+
+	if answers := must(r.Attempt.FindAnswersByAttemptID(ctx, attemptID)); len(answers) != 1 {
+		t.Errorf("expected the answer to the deleted question to be removed, got %d answers", len(answers))
+	}
+	if count := must(r.Attempt.CountQuestionsByQuizID(ctx, quizID)); count != 1 {
+		t.Errorf("expected the deleted question to leave the quiz, got %d questions", count)
+	}
+
+	// Deleting a quiz removes its attempts but keeps its questions in the bank
+	check(t, r.Quiz.Delete(ctx, quizID))
+
+	if attempt := must(r.Attempt.FindByID(ctx, attemptID)); attempt != nil {
+		t.Errorf("expected the attempt to be deleted with its quiz, got %+v", attempt)
+	}
+	if answers := must(r.Attempt.FindAnswersByAttemptID(ctx, attemptID)); len(answers) != 0 {
+		t.Errorf("expected the answers to be deleted with their attempt, got %d", len(answers))
+	}
+	if question := must(r.Question.FindByID(ctx, questionID)); question == nil {
+		t.Error("expected the question to stay in the bank")
+	}
+	if tags := must(r.Tag.FindAll(ctx)); len(tags) != 1 {
+		t.Errorf("expected the tag to be kept, got %v", tagNames(tags))
+	}
+}
+
+func testAttachments(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	questionID := must(r.Question.Create(ctx, "SHORT_ANSWER", "Name this molecule", "PLAIN", nil, models.Payload{}, "water", "EXACT", nil, "EASY"))
+	otherID := must(r.Question.Create(ctx, "SHORT_ANSWER", "Name this shape", "PLAIN", nil, models.Payload{}, "cube", "EXACT", nil, "EASY"))
+
+	diagramID := must(r.Attachment.Create(ctx, questionID, "CONTENT", "h2o.png", "image/png", 2048, "key-diagram"))
+	must(r.Attachment.Create(ctx, questionID, "EXPLANATION", "notes.pdf", "application/pdf", 4096, "key-notes"))
+	must(r.Attachment.Create(ctx, otherID, "CONTENT", "cube.gif", "image/gif", 512, "key-cube"))
+
+	diagram := must(r.Attachment.FindByID(ctx, diagramID))
+	if diagram == nil {
+		t.Fatal("expected the attachment to be found")
+	}
+	if diagram.QuestionID == nil || *diagram.QuestionID != questionID || diagram.Placement != "CONTENT" ||
+		diagram.Filename != "h2o.png" || diagram.ContentType != "image/png" || diagram.Size != 2048 || diagram.StorageKey != "key-diagram" {
+		t.Errorf("unexpected attachment %+v", diagram)
+	}
+	if diagram.CreatedAt.IsZero() {
+		t.Error("expected the creation time to be set")
+	}
+
+	byQuestion := must(r.Attachment.FindByQuestionIDs(ctx, []int{questionID, otherID, otherID + 100}))
+	if len(byQuestion[questionID]) != 2 || byQuestion[questionID][0].ID != diagramID || byQuestion[questionID][1].Placement != "EXPLANATION" {
+		t.Errorf("expected both attachments of the question in creation order, got %+v", byQuestion[questionID])
+	}
+	if len(byQuestion[otherID]) != 1 {
+		t.Errorf("expected one attachment on the other question, got %d", len(byQuestion[otherID]))
+	}
+	if _, ok := byQuestion[otherID+100]; ok {
+		t.Error("expected no entry for a missing question")
+	}
+
+	if _, err := r.Attachment.Create(ctx, otherID+100, "CONTENT", "x.png", "image/png", 1, "key-missing"); err == nil {
+		t.Error("expected an error attaching to a missing question")
+	}
+
+	if shared := must(r.Attachment.FindSharedStorageKeys(ctx, []int{diagramID})); len(shared) != 0 {
+		t.Errorf("expected no shared blobs, got %v", shared)
+	}
+
+	// Deleting a question keeps its attachments as orphans until their blobs are removed
+	if orphans := must(r.Attachment.FindOrphans(ctx, 10)); len(orphans) != 0 {
+		t.Errorf("expected no orphans yet, got %d", len(orphans))
+	}
+	check(t, r.Question.Delete(ctx, questionID))
+
+	orphans := must(r.Attachment.FindOrphans(ctx, 10))
+	if len(orphans) != 2 || orphans[0].ID != diagramID || orphans[0].QuestionID != nil || orphans[0].StorageKey != "key-diagram" {
+		t.Errorf("expected the attachments of the deleted question to be orphaned, got %+v", orphans)
+	}
+	if limited := must(r.Attachment.FindOrphans(ctx, 1)); len(limited) != 1 {
+		t.Errorf("expected the limit to apply, got %d orphans", len(limited))
+	}
+
+	check(t, r.Attachment.Delete(ctx, []int{orphans[0].ID, orphans[1].ID}))
+	if orphans := must(r.Attachment.FindOrphans(ctx, 10)); len(orphans) != 0 {
+		t.Errorf("expected the orphans to be deleted, got %d", len(orphans))
+	}
+	if attachment := must(r.Attachment.FindByID(ctx, diagramID)); attachment != nil {
+		t.Errorf("expected the attachment to be deleted, got %+v", attachment)
+	}
+	if left := must(r.Attachment.FindByQuestionIDs(ctx, []int{otherID})); len(left[otherID]) != 1 {
+		t.Error("expected the attachment of the other question to be kept")
+	}
+}
+
+func testDuplicate(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	sourceID := must(r.Quiz.Create(ctx, "Capitals", ptr("capitals of europe")))
+	tagID := must(r.Tag.Create(ctx, "geography"))
+	check(t, r.Quiz.AssociateTags(ctx, sourceID, []string{itoa(tagID)}))
+
+	first := must(r.Question.Create(ctx, "MULTIPLE_CHOICE", "capital of france?", "PLAIN", []string{"paris", "lyon"}, models.Payload{}, "paris", "EXACT", ptr("seat of government"), "EASY"))
+	second := must(r.Question.Create(ctx, "TRUE_FALSE", "rome is in *italy*", "MARKDOWN", nil, models.Payload{}, "true", "EXACT", nil, "HARD"))
+	third := must(r.Question.Create(ctx, "ORDERING", "order by size", "PLAIN", []string{"rome", "vatican"}, models.Payload{}, "0,1", "PARTIAL", nil, "EASY"))
+	check(t, r.Question.AssociateTags(ctx, first, []string{itoa(tagID)}))
+	check(t, r.Question.AddToQuiz(ctx, sourceID, first, 2))
+	check(t, r.Question.AddToQuiz(ctx, sourceID, second, 3))
+	check(t, r.Question.AddToQuiz(ctx, sourceID, third, 1))
+	diagramID := must(r.Attachment.Create(ctx, first, "CONTENT", "map.png", "image/png", 2048, "key-map"))
+
+	copyID := must(r.Quiz.Duplicate(ctx, sourceID, "Copy", true, strings.ToUpper))
+
+	quiz := must(r.Quiz.FindByID(ctx, copyID))
+	if quiz == nil || quiz.Title != "Copy" || quiz.Description == nil || *quiz.Description != "CAPITALS OF EUROPE" || quiz.Status != "DRAFT" {
+		t.Fatalf("unexpected copy: %+v", quiz)
+	}
+	if names := tagNames(must(r.Quiz.FindTagsByQuizID(ctx, copyID))); !slices.Equal(names, []string{"geography"}) {
+		t.Errorf("expected the quiz tags to be copied, got %v", names)
+	}
+
+	questions := must(r.Quiz.FindQuestionsByQuizID(ctx, copyID))
+	if len(questions) != 3 {
+		t.Fatalf("expected 3 copied questions, got %d", len(questions))
+	}
+	copied := questions[0]
+	if copied.ID == first || copied.Content != "CAPITAL OF FRANCE?" || copied.CorrectAnswer != "PARIS" || *copied.Points != 2 {
+		t.Errorf("unexpected copied question: %+v", copied)
+	}
+	if !slices.Equal(copied.Options, []string{"PARIS", "LYON"}) || copied.Explanation == nil || *copied.Explanation != "SEAT OF GOVERNMENT" {
+		t.Errorf("expected replaced options and explanation, got %v and %v", copied.Options, copied.Explanation)
+	}
+	if names := tagNames(must(r.Question.FindTagsByQuestionID(ctx, copied.ID))); !slices.Equal(names, []string{"geography"}) {
+		t.Errorf("expected the question tags to be copied, got %v", names)
+	}
+
+	// The copied attachments share the blobs of the originals
+	attachments := must(r.Attachment.FindByQuestionIDs(ctx, []int{copied.ID, second}))
+	if len(attachments[copied.ID]) != 1 || len(attachments[second]) != 0 {
+		t.Fatalf("expected only the attachment of the first question to be copied, got %+v", attachments)
+	}
+	attachment := attachments[copied.ID][0]
+	if attachment.ID == diagramID || attachment.Placement != "CONTENT" || attachment.Filename != "map.png" ||
+		attachment.ContentType != "image/png" || attachment.Size != 2048 || attachment.StorageKey != "key-map" {
+		t.Errorf("unexpected copied attachment %+v", attachment)
+	}
+	if shared := must(r.Attachment.FindSharedStorageKeys(ctx, []int{diagramID})); !shared["key-map"] {
+		t.Errorf("expected the blob to be shared with the copy, got %v", shared)
+	}
+	if shared := must(r.Attachment.FindSharedStorageKeys(ctx, []int{diagramID, attachment.ID})); len(shared) != 0 {
+		t.Errorf("expected no blob shared outside both copies, got %v", shared)
+	}
+
+	if questions[1].Difficulty != "HARD" || questions[1].ContentFormat != "MARKDOWN" || *questions[1].Points != 3 {
+		t.Errorf("unexpected second copied question: %+v", questions[1])
+	}
+	if !slices.Equal(questions[2].Options, []string{"ROME", "VATICAN"}) || questions[2].CorrectAnswer != "0,1" || questions[2].Grading != "PARTIAL" {
+		t.Errorf("unexpected third copied question: %+v", questions[2])
+	}
+
+	// The source is left untouched
+	if source := must(r.Question.FindByID(ctx, first)); source.Content != "capital of france?" {
+		t.Errorf("expected the source question to be unchanged, got %q", source.Content)
+	}
+
+	withoutTags := must(r.Quiz.Duplicate(ctx, sourceID, "Copy without tags", false, nil))
+	if tags := must(r.Quiz.FindTagsByQuizID(ctx, withoutTags)); len(tags) != 0 {
+		t.Errorf("expected no quiz tags, got %v", tagNames(tags))
+	}
+	if questions := must(r.Quiz.FindQuestionsByQuizID(ctx, withoutTags)); len(questions) != 3 || questions[0].Content != "capital of france?" {
+		t.Errorf("expected the questions copied unchanged without replace, got %v", questionIDs(questions))
+	}
+
+	if _, err := r.Quiz.Duplicate(ctx, sourceID+1000, "Missing", false, nil); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a missing quiz, got %v", err)
+	}
+}
+
+func testAttemptWithAnswers(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	quizID := must(r.Quiz.Create(ctx, "Capitals", nil))
+	right := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	wrong := must(r.Question.Create(ctx, "TRUE_FALSE", "Rome is in Spain", "PLAIN", nil, models.Payload{}, "false", "EXACT", nil, "EASY"))
+
+	startedAt := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	completedAt := startedAt.Add(5 * time.Minute)
+	attemptID := must(r.Attempt.CreateWithAnswers(ctx, quizID, startedAt, completedAt, 50, 2, []*models.Answer{
+		{QuestionID: ptr(right), UserAnswer: "true", IsCorrect: true, Credit: 1},
+		{QuestionID: ptr(wrong), UserAnswer: "true", Blanks: models.Blanks{false}},
+	}))
+
+	attempt := must(r.Attempt.FindByID(ctx, attemptID))
+	if attempt == nil || attempt.Score != 50 || attempt.TotalQuestions != 2 {
+		t.Fatalf("unexpected attempt: %+v", attempt)
+	}
+	answers := must(r.Attempt.FindAnswersByAttemptID(ctx, attemptID))
+	if len(answers) != 2 || *answers[0].QuestionID != right || !answers[0].IsCorrect || *answers[1].QuestionID != wrong || !slices.Equal(answers[1].Blanks, models.Blanks{false}) {
+		t.Errorf("unexpected answers: %+v", answers)
+	}
+
+	// An attempt without answers is stored as well
+	empty := must(r.Attempt.CreateWithAnswers(ctx, quizID, startedAt, completedAt, 0, 2, nil))
+	if answers := must(r.Attempt.FindAnswersByAttemptID(ctx, empty)); len(answers) != 0 {
+		t.Errorf("expected no answers, got %+v", answers)
+	}
+
+	// A missing question leaves no attempt behind
+	if _, err := r.Attempt.CreateWithAnswers(ctx, quizID, startedAt, completedAt, 100, 2, []*models.Answer{
+		{QuestionID: ptr(right), UserAnswer: "true", IsCorrect: true, Credit: 1},
+		{QuestionID: ptr(wrong + 1000), UserAnswer: "false", IsCorrect: true, Credit: 1},
+	}); err == nil {
+		t.Error("expected an error for a missing question")
+	}
+	if attempts := must(r.Attempt.FindAll(ctx, &quizID)); len(attempts) != 2 {
+		t.Errorf("expected 2 attempts, got %d", len(attempts))
+	}
+}
+
+func testAttempts(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	quizID := must(r.Quiz.Create(ctx, "Capitals", nil))
+	otherQuizID := must(r.Quiz.Create(ctx, "Rivers", nil))
+	right := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	wrong := must(r.Question.Create(ctx, "TRUE_FALSE", "Rome is in Spain", "PLAIN", nil, models.Payload{}, "false", "EXACT", nil, "EASY"))
+
+	if status := must(r.Attempt.FindQuizStatus(ctx, quizID)); status != "DRAFT" {
+		t.Errorf("expected status DRAFT, got %s", status)
+	}
+	if _, err := r.Attempt.FindQuizStatus(ctx, quizID+1000); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a missing quiz, got %v", err)
+	}
+	if answer := must(r.Attempt.GetCorrectAnswer(ctx, wrong)); answer != "false" {
+		t.Errorf("expected correct answer false, got %s", answer)
+	}
+	if _, err := r.Attempt.GetCorrectAnswer(ctx, wrong+1000); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows for a missing question, got %v", err)
+	}
+
+	startedAt := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	completedAt := startedAt.Add(5 * time.Minute)
+	attemptID := must(r.Attempt.Create(ctx, quizID, startedAt, completedAt, 0, 2))
+	laterID := must(r.Attempt.Create(ctx, quizID, startedAt.Add(time.Hour), completedAt.Add(time.Hour), 2, 2))
+	otherID := must(r.Attempt.Create(ctx, otherQuizID, startedAt, completedAt, 0, 0))
+
+	if _, err := r.Attempt.Create(ctx, quizID+1000, startedAt, completedAt, 0, 0); err == nil {
+		t.Error("expected an error for a missing quiz")
+	}
+
+	check(t, r.Attempt.CreateAnswer(ctx, attemptID, right, "true", true, 1, nil))
+	check(t, r.Attempt.CreateAnswer(ctx, attemptID, wrong, `["true", "false"]`, false, 0.5, models.Blanks{true, false}))
+	check(t, r.Attempt.UpdateScore(ctx, attemptID, 1))
+
+	if err := r.Attempt.CreateAnswer(ctx, attemptID+1000, right, "true", true, 1, nil); err == nil {
+		t.Error("expected an error for a missing attempt")
+	}
+
+	attempt := must(r.Attempt.FindByID(ctx, attemptID))
+	if attempt == nil {
+		t.Fatalf("expected attempt %d to exist", attemptID)
+	}
+	if attempt.QuizID == nil || *attempt.QuizID != quizID || attempt.Score != 1 || attempt.TotalQuestions != 2 {
+		t.Errorf("unexpected attempt: %+v", attempt)
+	}
+	if !attempt.StartedAt.Equal(startedAt) || attempt.CompletedAt == nil || !attempt.CompletedAt.Equal(completedAt) {
+		t.Errorf("expected the attempt to run from %v to %v, got %v to %v", startedAt, completedAt, attempt.StartedAt, attempt.CompletedAt)
+	}
+	if attempt := must(r.Attempt.FindByID(ctx, otherID+1000)); attempt != nil {
+		t.Errorf("expected a missing attempt to be nil, got %+v", attempt)
+	}
+
+	answers := must(r.Attempt.FindAnswersByAttemptID(ctx, attemptID))
+	if len(answers) != 2 || *answers[0].QuestionID != right || !answers[0].IsCorrect || *answers[1].QuestionID != wrong || answers[1].IsCorrect {
+		t.Errorf("unexpected answers: %+v", answers)
+	} else if answers[0].Credit != 1 || answers[1].Credit != 0.5 {
+		t.Errorf("expected credits 1 and 0.5, got %v and %v", answers[0].Credit, answers[1].Credit)
+	} else if answers[0].Blanks != nil || !slices.Equal(answers[1].Blanks, models.Blanks{true, false}) {
+		t.Errorf("expected blanks [true false] for the second answer only, got %v and %v", answers[0].Blanks, answers[1].Blanks)
+	}
+
+	// Attempts are ordered by start, latest first
+	var ids []int
+	for _, a := range must(r.Attempt.FindAll(ctx, &quizID)) {
+		ids = append(ids, a.ID)
+	}
+	if !slices.Equal(ids, []int{laterID, attemptID}) {
+		t.Errorf("expected attempts %v, got %v", []int{laterID, attemptID}, ids)
+	}
+	if all := must(r.Attempt.FindAll(ctx, nil)); len(all) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(all))
+	}
+
+	if ids := questionIDs(must(r.Question.FindWrongQuestions(ctx))); !slices.Equal(ids, []int{wrong}) {
+		t.Errorf("expected wrong questions %v, got %v", []int{wrong}, ids)
+	}
+
+	// Batched lookups
+	byAttempt := must(r.Attempt.FindAnswersByAttemptIDs(ctx, []int{attemptID, laterID}))
+	if answers := byAttempt[attemptID]; len(answers) != 2 || *answers[0].QuestionID != right || *answers[1].QuestionID != wrong || len(answers[1].Blanks) != 2 {
+		t.Errorf("unexpected answers of attempt %d: %+v", attemptID, answers)
+	}
+	if answers := byAttempt[laterID]; len(answers) != 0 {
+		t.Errorf("expected no answers for attempt %d, got %+v", laterID, answers)
+	}
+
+	byQuiz := must(r.Attempt.FindByQuizIDs(ctx, []int{quizID, otherQuizID, otherQuizID + 1000}))
+	ids = nil
+	for _, a := range byQuiz[quizID] {
+		ids = append(ids, a.ID)
+	}
+	if !slices.Equal(ids, []int{laterID, attemptID}) {
+		t.Errorf("expected attempts %v of quiz %d, got %v", []int{laterID, attemptID}, quizID, ids)
+	}
+	if attempts := byQuiz[otherQuizID]; len(attempts) != 1 || attempts[0].ID != otherID {
+		t.Errorf("expected attempt %d of quiz %d, got %+v", otherID, otherQuizID, attempts)
+	}
+	if len(byQuiz) != 2 {
+		t.Errorf("expected attempts for 2 quizzes, got %d", len(byQuiz))
+	}
+
+	if ids := questionIDs(must(r.Question.FindByIDs(ctx, []int{wrong, right, wrong + 1000}))); !slices.Equal(ids, []int{right, wrong}) {
+		t.Errorf("expected questions %v, got %v", []int{right, wrong}, ids)
+	}
+}
+
+func testStatistics(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	if count := must(r.Statistics.CountTotalAttempts(ctx)); count != 0 {
+		t.Errorf("expected no attempts, got %d", count)
+	}
+	if avg := must(r.Statistics.CalculateAverageScore(ctx)); avg != 0 {
+		t.Errorf("expected an average of 0 without attempts, got %v", avg)
+	}
+	if stats := must(r.Statistics.GetCategoryStats(ctx, false)); len(stats) != 0 {
+		t.Errorf("expected no category stats, got %d", len(stats))
+	}
+
+	quizID := must(r.Quiz.Create(ctx, "Capitals", nil))
+	first := must(r.Question.Create(ctx, "TRUE_FALSE", "Paris is in France", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	second := must(r.Question.Create(ctx, "TRUE_FALSE", "Rome is in Spain", "PLAIN", nil, models.Payload{}, "false", "EXACT", nil, "EASY"))
+	geography := must(r.Tag.Create(ctx, "geography"))
+	europe := must(r.Tag.Create(ctx, "europe"))
+	must(r.Tag.Create(ctx, "unused"))
+	check(t, r.Question.AssociateTags(ctx, first, []string{itoa(geography), itoa(europe)}))
+	check(t, r.Question.AssociateTags(ctx, second, []string{itoa(geography)}))
+
+	// Attempts without questions are left out of the average
+	now := time.Now()
+	half := must(r.Attempt.Create(ctx, quizID, now, now, 1, 2))
+	full := must(r.Attempt.Create(ctx, quizID, now, now, 2, 2))
+	must(r.Attempt.Create(ctx, quizID, now, now, 0, 0))
+
+	check(t, r.Attempt.CreateAnswer(ctx, half, first, "true", true, 1, nil))
+	check(t, r.Attempt.CreateAnswer(ctx, half, second, "true", false, 0, nil))
+	check(t, r.Attempt.CreateAnswer(ctx, full, first, "true", true, 1, nil))
+	check(t, r.Attempt.CreateAnswer(ctx, full, second, "false", true, 1, nil))
+
+	if count := must(r.Statistics.CountTotalAttempts(ctx)); count != 3 {
+		t.Errorf("expected 3 attempts, got %d", count)
+	}
+	if avg := must(r.Statistics.CalculateAverageScore(ctx)); math.Abs(avg-75) > 1e-9 {
+		t.Errorf("expected an average of 75, got %v", avg)
+	}
+
+	// One row per tag over the answers to its questions, ordered by name
+	stats := must(r.Statistics.GetCategoryStats(ctx, false))
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 category stats, got %d", len(stats))
+	}
+	want := []repository.CategoryStat{
+		{TagName: "europe", CorrectRate: 100, TotalQuestions: 2},
+		{TagName: "geography", CorrectRate: 75, TotalQuestions: 4},
+	}
+	for i, w := range want {
+		got := stats[i]
+		if got.TagName != w.TagName || math.Abs(got.CorrectRate-w.CorrectRate) > 1e-9 || got.TotalQuestions != w.TotalQuestions {
+			t.Errorf("expected %+v, got %+v", w, *got)
+		}
+	}
+}
+
+func itoa(id int) string {
+	return strconv.Itoa(id)
+}
+
+func testStatisticsRollUp(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	goID := must(r.Tag.Create(ctx, "Go"))
+	concurrency := must(r.Tag.Create(ctx, "Concurrency"))
+	channels := must(r.Tag.Create(ctx, "Channels"))
+	check(t, r.Tag.SetParent(ctx, concurrency, &goID))
+	check(t, r.Tag.SetParent(ctx, channels, &concurrency))
+
+	quizID := must(r.Quiz.Create(ctx, "Go", nil))
+	onChannels := must(r.Question.Create(ctx, "TRUE_FALSE", "Channels can be closed", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+	onGo := must(r.Question.Create(ctx, "TRUE_FALSE", "Go has generics", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY"))
+
+	// A question tagged with a tag and its ancestor counts once for the ancestor
+	check(t, r.Question.AssociateTags(ctx, onChannels, []string{itoa(channels), itoa(goID)}))
+	check(t, r.Question.AssociateTags(ctx, onGo, []string{itoa(goID)}))
+
+	now := time.Now()
+	attemptID := must(r.Attempt.Create(ctx, quizID, now, now, 1, 2))
+	check(t, r.Attempt.CreateAnswer(ctx, attemptID, onChannels, "true", true, 1, nil))
+	check(t, r.Attempt.CreateAnswer(ctx, attemptID, onGo, "false", false, 0, nil))
+
+	tests := []struct {
+		rollUp bool
+		want   []repository.CategoryStat
+	}{
+		{false, []repository.CategoryStat{
+			{TagName: "Channels", CorrectRate: 100, TotalQuestions: 1},
+			{TagName: "Go", CorrectRate: 50, TotalQuestions: 2},
+		}},
+		{true, []repository.CategoryStat{
+			{TagName: "Channels", CorrectRate: 100, TotalQuestions: 1},
+			{TagName: "Concurrency", CorrectRate: 100, TotalQuestions: 1},
+			{TagName: "Go", CorrectRate: 50, TotalQuestions: 2},
+		}},
+	}
+
+	for _, tt := range tests {
+		stats := must(r.Statistics.GetCategoryStats(ctx, tt.rollUp))
+		if len(stats) != len(tt.want) {
+			t.Errorf("rollUp %v: expected %d category stats, got %d", tt.rollUp, len(tt.want), len(stats))
+			continue
+		}
+		for i, w := range tt.want {
+			got := stats[i]
+			if got.TagName != w.TagName || math.Abs(got.CorrectRate-w.CorrectRate) > 1e-9 || got.TotalQuestions != w.TotalQuestions {
+				t.Errorf("rollUp %v: expected %+v, got %+v", tt.rollUp, w, *got)
+			}
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+
+	"quiz-log/db"
+	"quiz-log/migrate"
+	"quiz-log/models"
+)
+
+// setupSQLiteDB opens a migrated in-memory SQLite database, checking the SQL the mocks can't
+func setupSQLiteDB(t *testing.T) *bun.DB {
+	bunDB, err := db.Connect(db.Config{Driver: db.DriverSQLite, Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("failed to open SQLite: %v", err)
+	}
+	t.Cleanup(func() { bunDB.Close() })
+
+	migrations, err := migrate.Load(db.Migrations(db.DriverSQLite))
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	migrator := migrate.NewMigrator(bunDB.DB, migrations)
+	migrator.SkipLock = true
+	if _, err := migrator.Up(context.Background(), 0); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	return bunDB
+}
+
+func TestSQLite_QuizAndQuestions(t *testing.T) {
+	bunDB := setupSQLiteDB(t)
+	ctx := context.Background()
+
+	quizRepo := NewQuizRepository(bunDB)
+	questionRepo := NewQuestionRepository(bunDB)
+	tagRepo := NewTagRepository(bunDB)
+
+	quizID, err := quizRepo.Create(ctx, "Capitals", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	questionID, err := questionRepo.Create(ctx, "MULTIPLE_CHOICE", "Capital of France?", "PLAIN", []string{"Paris", "Lyon"}, models.Payload{}, "Paris", "EXACT", nil, "EASY")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Adding twice updates the points of the existing membership
+	if err := questionRepo.AddToQuiz(ctx, quizID, questionID, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := questionRepo.AddToQuiz(ctx, quizID, questionID, 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Creating an existing tag returns its ID
+	tagID, err := tagRepo.Create(ctx, "geography")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sameTagID, err := tagRepo.Create(ctx, "geography")
+	if err != nil || sameTagID != tagID {
+		t.Fatalf("expected tag ID %d, got %d (%v)", tagID, sameTagID, err)
+	}
+
+	if err := questionRepo.AssociateTags(ctx, questionID, []string{"1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := quizRepo.AssociateTags(ctx, quizID, []string{"1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content := "Capital city of France?"
+	if err := questionRepo.Update(ctx, questionID, nil, &content, nil, []string{"Paris", "Lyon", "Nice"}, nil, nil, nil, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	questions, err := quizRepo.FindQuestionsByQuizID(ctx, quizID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(questions) != 1 {
+		t.Fatalf("expected 1 question, got %d", len(questions))
+	}
+
+	q := questions[0]
+	if q.Content != content || len(q.Options) != 3 || q.Options[2] != "Nice" {
+		t.Errorf("expected updated question with 3 options, got '%s' %v", q.Content, q.Options)
+	}
+
+	if q.Points == nil || *q.Points != 3 {
+		t.Errorf("expected points 3, got %v", q.Points)
+	}
+
+	// Execute
+	copyID, err := quizRepo.Duplicate(ctx, quizID, "Capitals (copy)", true, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	copied, err := quizRepo.FindQuestionsByQuizID(ctx, copyID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(copied) != 1 || copied[0].ID == questionID || len(copied[0].Options) != 3 {
+		t.Fatalf("expected a copied question with 3 options, got %+v", copied)
+	}
+
+	tags, err := quizRepo.FindTagsByQuizIDs(ctx, []int{copyID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(tags[copyID]) != 1 || tags[copyID][0].Name != "geography" {
+		t.Errorf("expected copied tag 'geography', got %v", tags[copyID])
+	}
+}
+
+func TestSQLite_Attempts(t *testing.T) {
+	bunDB := setupSQLiteDB(t)
+	ctx := context.Background()
+
+	quizRepo := NewQuizRepository(bunDB)
+	questionRepo := NewQuestionRepository(bunDB)
+	attemptRepo := NewAttemptRepository(bunDB)
+	statsRepo := NewStatisticsRepository(bunDB)
+
+	quizID, err := quizRepo.Create(ctx, "Go", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	questionID, err := questionRepo.Create(ctx, "TRUE_FALSE", "Go has generics.", "PLAIN", nil, models.Payload{}, "true", "EXACT", nil, "EASY")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	startedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	attemptID, err := attemptRepo.Create(ctx, quizID, startedAt, startedAt.Add(time.Minute), 0, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := attemptRepo.CreateAnswer(ctx, attemptID, questionID, "false", false, 0, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Execute
+	attempt, err := attemptRepo.FindByID(ctx, attemptID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wrong, err := questionRepo.FindWrongQuestions(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	total, err := statsRepo.CountTotalAttempts(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	if !attempt.StartedAt.Equal(startedAt) {
+		t.Errorf("expected started at %s, got %s", startedAt, attempt.StartedAt)
+	}
+
+	if len(wrong) != 1 || wrong[0].ID != questionID || wrong[0].Options != nil {
+		t.Errorf("expected question %d without options to review, got %+v", questionID, wrong)
+	}
+
+	if total != 1 {
+		t.Errorf("expected 1 attempt, got %d", total)
+	}
+}
@@ -14,7 +14,7 @@ import (
 type StatisticsRepository interface {
 	CountTotalAttempts(ctx context.Context) (int, error)
 	CalculateAverageScore(ctx context.Context) (float64, error)
-	GetCategoryStats(ctx context.Context) ([]*CategoryStat, error)
+	GetCategoryStats(ctx context.Context, rollUp bool) ([]*CategoryStat, error)
 }
 
 type statisticsRepository struct {
@@ -66,12 +66,17 @@ type CategoryStat struct {
 	TotalQuestions int
 }
 
-// GetCategoryStats retrieves statistics by category (tag)
-func (r *statisticsRepository) GetCategoryStats(ctx context.Context) ([]*CategoryStat, error) {
+// GetCategoryStats retrieves statistics by category (tag). With rollUp, the answers to the
+// questions of a tag also count for all of its ancestors, each answer once per tag.
+func (r *statisticsRepository) GetCategoryStats(ctx context.Context, rollUp bool) ([]*CategoryStat, error) {
+	if rollUp {
+		return FindAll[CategoryStat](ctx, r.DB, selectRolledUpCategoryStats())
+	}
+
 	queryBuilder := psql.Select(
-		"t.name",
-		"AVG(CASE WHEN a.is_correct THEN 1.0 ELSE 0.0 END) * 100 as correct_rate",
-		"COUNT(*) as total",
+		"t.name AS tag_name",
+		"AVG(CASE WHEN a.is_correct THEN 1.0 ELSE 0.0 END) * 100 AS correct_rate",
+		"COUNT(*) AS total_questions",
 	).
 		From("tags t").
 		Join("question_tags qt ON t.id = qt.tag_id").
@@ -81,3 +86,27 @@ func (r *statisticsRepository) GetCategoryStats(ctx context.Context) ([]*Categor
 
 	return FindAll[CategoryStat](ctx, r.DB, queryBuilder)
 }
+
+// selectRolledUpCategoryStats pairs every tag with itself and its ancestors, then counts the
+// distinct answers reaching each ancestor so a question tagged twice in a branch counts once
+func selectRolledUpCategoryStats() sq.SelectBuilder {
+	answers := sq.Select("DISTINCT an.ancestor_id", "a.id", "a.is_correct").
+		From("ancestors an").
+		Join("question_tags qt ON qt.tag_id = an.tag_id").
+		Join("answers a ON a.question_id = qt.question_id")
+
+	return psql.Select(
+		"t.name AS tag_name",
+		"AVG(CASE WHEN x.is_correct THEN 1.0 ELSE 0.0 END) * 100 AS correct_rate",
+		"COUNT(*) AS total_questions",
+	).
+		Prefix(`WITH RECURSIVE ancestors (tag_id, ancestor_id) AS (
+			SELECT id, id FROM tags
+			UNION ALL
+			SELECT an.tag_id, t.parent_id FROM ancestors an JOIN tags t ON t.id = an.ancestor_id WHERE t.parent_id IS NOT NULL
+		)`).
+		FromSelect(answers, "x").
+		Join("tags t ON t.id = x.ancestor_id").
+		GroupBy("t.name").
+		OrderBy("t.name")
+}
@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"quiz-log/apperrors"
 	"quiz-log/models"
+	"strings"
+	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/uptrace/bun"
 )
 
@@ -12,7 +16,26 @@ import (
 // TagRepository defines the interface for tag repository operations
 type TagRepository interface {
 	Create(ctx context.Context, name string) (int, error)
+	CreateWithParent(ctx context.Context, name string, parentID int) (int, error)
 	FindAll(ctx context.Context) ([]*models.Tag, error)
+	FindByID(ctx context.Context, id int) (*models.Tag, error)
+	FindByIDs(ctx context.Context, ids []int) ([]*models.Tag, error)
+	FindByParentIDs(ctx context.Context, parentIDs []int) (map[int][]*models.Tag, error)
+	Rename(ctx context.Context, id int, name string) error
+	SetParent(ctx context.Context, id int, parentID *int) error
+	Delete(ctx context.Context, id int) error
+	Merge(ctx context.Context, sourceIDs []int, targetID int) error
+	FindUsageByTagIDs(ctx context.Context, tagIDs []int) (map[int]*TagUsage, error)
+	FindByPrefix(ctx context.Context, prefix string, limit int) ([]*models.Tag, error)
+}
+
+// TagUsage represents how much a tag is used. LastUsedAt is the latest update of a quiz or
+// question carrying the tag, nil when the tag is unused.
+type TagUsage struct {
+	TagID         int        `bun:"tag_id"`
+	QuizCount     int        `bun:"quiz_count"`
+	QuestionCount int        `bun:"question_count"`
+	LastUsedAt    *time.Time `bun:"last_used_at"`
 }
 
 type tagRepository struct {
@@ -40,11 +63,246 @@ func (r *tagRepository) Create(ctx context.Context, name string) (int, error) {
 	return tagID, nil
 }
 
+// CreateWithParent creates a new tag under a parent. Unlike Create, a name in use fails with a
+// unique violation, so an existing tag is never moved.
+func (r *tagRepository) CreateWithParent(ctx context.Context, name string, parentID int) (int, error) {
+	var tagID int
+
+	query := psql.Insert("tags").
+		Columns("name", "parent_id").
+		Values(name, parentID).
+		Suffix("RETURNING id")
+
+	err := ExecQueryWithReturning[int](ctx, r.DB, query, &tagID)
+	if err != nil {
+		return 0, err
+	}
+
+	return tagID, nil
+}
+
 // FindAll retrieves all tags
 func (r *tagRepository) FindAll(ctx context.Context) ([]*models.Tag, error) {
-	query := psql.Select("id", "name").
+	query := psql.Select("id", "name", "parent_id").
 		From("tags").
 		OrderBy("name ASC")
 
 	return FindAll[models.Tag](ctx, r.DB, query)
 }
+
+// FindByID retrieves a tag by its ID
+func (r *tagRepository) FindByID(ctx context.Context, id int) (*models.Tag, error) {
+	query := psql.Select("id", "name", "parent_id").
+		From("tags").
+		Where("id = ?", id)
+
+	return FindOne[models.Tag](ctx, r.DB, query)
+}
+
+// FindByIDs retrieves the tags with the given IDs in ID order, leaving out missing ones
+func (r *tagRepository) FindByIDs(ctx context.Context, ids []int) ([]*models.Tag, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	query := psql.Select("id", "name", "parent_id").
+		From("tags").
+		Where(sq.Eq{"id": ids}).
+		OrderBy("id ASC")
+
+	return FindAll[models.Tag](ctx, r.DB, query)
+}
+
+// FindByParentIDs retrieves the direct children of multiple tags, keyed by parent ID
+func (r *tagRepository) FindByParentIDs(ctx context.Context, parentIDs []int) (map[int][]*models.Tag, error) {
+	if len(parentIDs) == 0 {
+		return make(map[int][]*models.Tag), nil
+	}
+
+	query := psql.Select("id", "name", "parent_id").
+		From("tags").
+		Where(sq.Eq{"parent_id": parentIDs}).
+		OrderBy("name ASC")
+
+	tags, err := FindAll[models.Tag](ctx, r.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int][]*models.Tag)
+	for _, tag := range tags {
+		result[*tag.ParentID] = append(result[*tag.ParentID], tag)
+	}
+	return result, nil
+}
+
+// Rename changes the name of a tag
+func (r *tagRepository) Rename(ctx context.Context, id int, name string) error {
+	query := psql.Update("tags").
+		Set("name", name).
+		Where("id = ?", id)
+
+	_, err := ExecQuery(ctx, r.DB, query)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetParent moves a tag under another one, or to the top level when parentID is nil.
+// The parent is checked not to be the tag itself or one of its descendants in the same transaction.
+func (r *tagRepository) SetParent(ctx context.Context, id int, parentID *int) error {
+	return r.DB.RunInTx(ctx, nil, func(ctx context.Context, bunTx bun.Tx) error {
+		tx := bunTx.Tx
+
+		if parentID != nil {
+			// Walking up from the parent reaches the tag when the move would make a loop
+			loop := psql.Select("COUNT(*)").
+				Prefix(`WITH RECURSIVE ancestors (id, parent_id) AS (
+					SELECT id, parent_id FROM tags WHERE id = ?
+					UNION
+					SELECT t.id, t.parent_id FROM ancestors an JOIN tags t ON t.id = an.parent_id
+				)`, *parentID).
+				From("ancestors").
+				Where("id = ?", id)
+
+			var count int
+			err := ExecTxQueryWithReturning(ctx, tx, loop, &count)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return apperrors.InvalidArgument("tag %d cannot be moved under itself or one of its descendants", id)
+			}
+		}
+
+		query := psql.Update("tags").
+			Set("parent_id", parentID).
+			Where("id = ?", id)
+
+		_, err := ExecTxQuery(ctx, tx, query)
+		return err
+	})
+}
+
+// Delete deletes a tag, moving its children up to its parent
+func (r *tagRepository) Delete(ctx context.Context, id int) error {
+	return r.DB.RunInTx(ctx, nil, func(ctx context.Context, bunTx bun.Tx) error {
+		tx := bunTx.Tx
+
+		reparent := psql.Update("tags").
+			Set("parent_id", sq.Expr("(SELECT parent_id FROM tags WHERE id = ?)", id)).
+			Where("parent_id = ?", id)
+
+		_, err := ExecTxQuery(ctx, tx, reparent)
+		if err != nil {
+			return err
+		}
+
+		_, err = ExecTxQuery(ctx, tx, psql.Delete("tags").Where("id = ?", id))
+		return err
+	})
+}
+
+// Merge moves the quizzes, questions and children of the source tags to the target tag, then deletes the sources.
+// Items already carrying the target keep a single association.
+func (r *tagRepository) Merge(ctx context.Context, sourceIDs []int, targetID int) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+
+	return r.DB.RunInTx(ctx, nil, func(ctx context.Context, bunTx bun.Tx) error {
+		tx := bunTx.Tx
+
+		for _, table := range []struct{ name, column string }{
+			{"quiz_tags", "quiz_id"},
+			{"question_tags", "question_id"},
+		} {
+			relink := psql.Insert(table.name).
+				Columns(table.column, "tag_id").
+				Select(sq.Select().
+					Distinct().
+					Column(table.column).
+					Column("CAST(? AS INTEGER)", targetID).
+					From(table.name).
+					Where(sq.Eq{"tag_id": sourceIDs})).
+				Suffix("ON CONFLICT DO NOTHING")
+
+			_, err := ExecTxQuery(ctx, tx, relink)
+			if err != nil {
+				return err
+			}
+		}
+
+		reparent := psql.Update("tags").
+			Set("parent_id", targetID).
+			Where(sq.Eq{"parent_id": sourceIDs})
+
+		_, err := ExecTxQuery(ctx, tx, reparent)
+		if err != nil {
+			return err
+		}
+
+		// Deleting the sources removes their remaining associations
+		_, err = ExecTxQuery(ctx, tx, psql.Delete("tags").Where(sq.Eq{"id": sourceIDs}))
+		return err
+	})
+}
+
+// FindUsageByTagIDs retrieves the usage of multiple tags, keyed by tag ID. Unknown tags are left out.
+func (r *tagRepository) FindUsageByTagIDs(ctx context.Context, tagIDs []int) (map[int]*TagUsage, error) {
+	if len(tagIDs) == 0 {
+		return make(map[int]*TagUsage), nil
+	}
+
+	counts := psql.Select(
+		"t.id AS tag_id",
+		"(SELECT COUNT(*) FROM quiz_tags qt WHERE qt.tag_id = t.id) AS quiz_count",
+		"(SELECT COUNT(*) FROM question_tags qnt WHERE qnt.tag_id = t.id) AS question_count",
+		"(SELECT MAX(q.updated_at) FROM quizzes q JOIN quiz_tags qt ON q.id = qt.quiz_id WHERE qt.tag_id = t.id) AS quiz_used_at",
+		"(SELECT MAX(qn.updated_at) FROM questions qn JOIN question_tags qnt ON qn.id = qnt.question_id WHERE qnt.tag_id = t.id) AS question_used_at",
+	).
+		From("tags t").
+		Where(sq.Eq{"t.id": tagIDs})
+
+	// A NULL comparison falls through to question_used_at, which is NULL only when both are
+	query := psql.Select(
+		"u.tag_id",
+		"u.quiz_count",
+		"u.question_count",
+		"CASE WHEN u.question_used_at IS NULL OR u.quiz_used_at > u.question_used_at THEN u.quiz_used_at ELSE u.question_used_at END AS last_used_at",
+	).
+		FromSelect(counts, "u")
+
+	usages, err := FindAll[TagUsage](ctx, r.DB, query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int]*TagUsage, len(usages))
+	for _, usage := range usages {
+		result[usage.TagID] = usage
+	}
+	return result, nil
+}
+
+// FindByPrefix retrieves the tags whose name starts with prefix, ignoring case, most used first
+func (r *tagRepository) FindByPrefix(ctx context.Context, prefix string, limit int) ([]*models.Tag, error) {
+	query := psql.Select("t.id", "t.name", "t.parent_id").
+		From("tags t").
+		Where("LOWER(t.name) LIKE ? ESCAPE '\\'", escapeLike(strings.ToLower(prefix))+"%").
+		OrderBy(
+			"(SELECT COUNT(*) FROM quiz_tags qt WHERE qt.tag_id = t.id) + (SELECT COUNT(*) FROM question_tags qnt WHERE qnt.tag_id = t.id) DESC",
+			"t.name ASC",
+		).
+		Limit(uint64(limit))
+
+	return FindAll[models.Tag](ctx, r.DB, query)
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, with a backslash as the escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
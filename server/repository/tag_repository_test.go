@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/uptrace/bun"
+
+	"quiz-log/apperrors"
+)
+
+func TestTagRepository_Delete(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewTagRepository(bunDB)
+
+		// Expect the children to move up before the tag is deleted
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE tags SET parent_id = \(SELECT parent_id FROM tags WHERE id = \$1\) WHERE parent_id = \$2`).
+			WithArgs(3, 3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM tags WHERE id = \$1`).
+			WithArgs(3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		// Execute
+		err := repo.Delete(context.Background(), 3)
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestTagRepository_Merge(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewTagRepository(bunDB)
+
+		// Expect the associations to be copied without duplicates, then the sources to go
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO quiz_tags \(quiz_id,tag_id\) SELECT DISTINCT quiz_id, CAST\(\$1 AS INTEGER\) FROM quiz_tags WHERE tag_id IN \(\$2,\$3\) ON CONFLICT DO NOTHING`).
+			WithArgs(1, 2, 3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`INSERT INTO question_tags \(question_id,tag_id\) SELECT DISTINCT question_id, CAST\(\$1 AS INTEGER\) FROM question_tags WHERE tag_id IN \(\$2,\$3\) ON CONFLICT DO NOTHING`).
+			WithArgs(1, 2, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE tags SET parent_id = \$1 WHERE parent_id IN \(\$2,\$3\)`).
+			WithArgs(1, 2, 3).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(`DELETE FROM tags WHERE id IN \(\$1,\$2\)`).
+			WithArgs(2, 3).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		// Execute
+		err := repo.Merge(context.Background(), []int{2, 3}, 1)
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestTagRepository_Merge_RollbackOnError(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewTagRepository(bunDB)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO quiz_tags`).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		// Execute
+		err := repo.Merge(context.Background(), []int{2}, 1)
+
+		// Assert
+		if err != sql.ErrConnDone {
+			t.Errorf("expected sql.ErrConnDone, got %v", err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestTagRepository_SetParent_RejectsLoop(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewTagRepository(bunDB)
+		parentID := 3
+
+		// Expect the ancestors of the parent to be checked in the transaction of the move, and no update
+		mock.ExpectBegin()
+		mock.ExpectQuery(`WITH RECURSIVE ancestors \(id, parent_id\) AS \(.*\) SELECT COUNT\(\*\) FROM ancestors WHERE id = \$2`).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectRollback()
+
+		// Execute
+		err := repo.SetParent(context.Background(), 1, &parentID)
+
+		// Assert
+		if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+			t.Errorf("expected code '%s', got %v", apperrors.CodeInvalidArgument, err)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestTagRepository_FindByPrefix(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewTagRepository(bunDB)
+
+		// Expect the wildcards of the prefix to be escaped
+		rows := sqlmock.NewRows([]string{"id", "name", "parent_id"}).
+			AddRow(1, "100%_done", nil)
+		mock.ExpectQuery(`SELECT t.id, t.name, t.parent_id FROM tags t WHERE LOWER\(t.name\) LIKE \$1 ESCAPE '\\' ORDER BY .+ DESC, t.name ASC LIMIT 5`).
+			WithArgs(`100\%\_%`).
+			WillReturnRows(rows)
+
+		// Execute
+		tags, err := repo.FindByPrefix(context.Background(), "100%_", 5)
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(tags) != 1 || tags[0].Name != "100%_done" {
+			t.Errorf("unexpected tags: %v", tags)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestTagRepository_FindByParentIDs(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewTagRepository(bunDB)
+
+		// Expect the children of every parent in one query
+		rows := sqlmock.NewRows([]string{"id", "name", "parent_id"}).
+			AddRow(3, "Channels", 1).
+			AddRow(4, "Generics", 2).
+			AddRow(5, "Goroutines", 1)
+		mock.ExpectQuery(`SELECT id, name, parent_id FROM tags WHERE parent_id IN \(\$1,\$2\) ORDER BY name ASC`).
+			WithArgs(1, 2).
+			WillReturnRows(rows)
+
+		// Execute
+		children, err := repo.FindByParentIDs(context.Background(), []int{1, 2})
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(children[1]) != 2 || children[1][1].Name != "Goroutines" {
+			t.Errorf("expected 2 children of tag 1, got %v", children[1])
+		}
+		if len(children[2]) != 1 || children[2][0].Name != "Generics" {
+			t.Errorf("expected 1 child of tag 2, got %v", children[2])
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
+
+func TestTagRepository_FindUsageByTagIDs_EmptyInput(t *testing.T) {
+	forEachDialect(t, func(t *testing.T, bunDB *bun.DB, mock sqlmock.Sqlmock) {
+		repo := NewTagRepository(bunDB)
+
+		// Execute
+		usages, err := repo.FindUsageByTagIDs(context.Background(), nil)
+
+		// Assert
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(usages) != 0 {
+			t.Errorf("expected no usage, got %v", usages)
+		}
+
+		// No query should run
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unfulfilled expectations: %v", err)
+		}
+	})
+}
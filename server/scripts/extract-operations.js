@@ -0,0 +1,30 @@
+const fs = require('fs');
+const path = require('path');
+const vm = require('vm');
+
+// Read the operations emitted by the Relay compiler
+const generatedDir = path.join(__dirname, '../../web/src/components/__generated__');
+
+const operations = {};
+for (const file of fs.readdirSync(generatedDir).sort()) {
+  if (!file.endsWith('.graphql.ts')) {
+    continue;
+  }
+
+  const source = fs.readFileSync(path.join(generatedDir, file), 'utf8');
+
+  // Fragments have no params; requests carry their name and document text
+  const match = source.match(/params: \{[\s\S]*?name: '(\w+)',[\s\S]*?text: ('(?:[^'\\]|\\.)*'),/);
+  if (!match) {
+    continue;
+  }
+
+  operations[match[1]] = vm.runInNewContext(match[2]);
+}
+
+// Write next to the Go package embedding it
+const outputPath = path.join(__dirname, '../graph/allowlist/operations.json');
+
+fs.writeFileSync(outputPath, JSON.stringify(operations, null, 2) + '\n');
+
+console.log(`✅ ${Object.keys(operations).length} operations extracted to ${outputPath}`);
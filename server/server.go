@@ -1,76 +1,274 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"quiz-log/attachments"
+	"quiz-log/blob"
+	"quiz-log/config"
 	"quiz-log/dataloader"
 	"quiz-log/db"
 	"quiz-log/graph"
+	"quiz-log/graph/allowlist"
+	"quiz-log/graph/depthlimit"
 	"quiz-log/graph/resolvers"
+	"quiz-log/health"
+	"quiz-log/logging"
+	"quiz-log/markup"
+	"quiz-log/pubsub"
 	"quiz-log/repository"
+	"quiz-log/requestid"
 	"quiz-log/services"
+	"quiz-log/telemetry"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/uptrace/bun"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
-const defaultPort = "8080"
-
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = defaultPort
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("invalid configuration", err)
 	}
 
-	// Database connection
-	dbConn, err := db.Connect(db.Config{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     5432,
-		User:     getEnv("DB_USER", "postgres"),
-		Password: getEnv("DB_PASSWORD", "postgres"),
-		DBName:   getEnv("DB_NAME", "quizlog"),
-	})
+	if err := logging.Setup(cfg.Log.Format, cfg.Log.Level); err != nil {
+		fatal("failed to set up logging", err)
+	}
+	repository.SetSlowQueryThreshold(cfg.Log.SlowQueryThreshold)
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), cfg, os.Args[2:]); err != nil {
+			fatal("migration failed", err)
+		}
+		return
+	}
+
+	demo := flag.Bool("demo", false, "serve seeded in-memory data instead of a database")
+	playgroundEnabled := flag.Bool("playground", cfg.PlaygroundEnabled, "serve the GraphQL playground, overriding PLAYGROUND_ENABLED")
+	flag.Parse()
+	cfg.PlaygroundEnabled = *playgroundEnabled
+
+	shutdownTracing, err := telemetry.SetupTracing(context.Background(), cfg.TracesExporter)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("failed to set up tracing", err)
+	}
+
+	// Initialize the pub/sub bus feeding subscriptions
+	bus := pubsub.NewMemoryBus()
+
+	var resolver *resolvers.Resolver
+	var pinger health.Pinger
+	if *demo {
+		// Attached files are lost on exit like the rest of the demo data
+		dir, err := os.MkdirTemp("", "quizlog-attachments-")
+		if err != nil {
+			fatal("failed to create attachment directory", err)
+		}
+		defer os.RemoveAll(dir)
+
+		blobs, err := blob.NewFSStore(dir)
+		if err != nil {
+			fatal("failed to open attachment storage", err)
+		}
+
+		resolver, err = newDemoResolver(context.Background(), bus, blobs, cfg.Attachments.MaxSize)
+		if err != nil {
+			fatal("failed to seed demo data", err)
+		}
+		pinger = alwaysReady{}
+		slog.Warn("running in demo mode, data is kept in memory and lost on exit")
+	} else {
+		// Database connection
+		dbConn, err := db.Connect(cfg.DB)
+		if err != nil {
+			fatal("failed to connect to database", err)
+		}
+		defer dbConn.Close()
+		telemetry.SetDBDriver(cfg.DB.Driver)
+
+		if cfg.AutoMigrate {
+			if err := autoMigrate(context.Background(), dbConn.DB, cfg.DB.Driver); err != nil {
+				fatal("failed to apply migrations", err)
+			}
+		}
+
+		if err := telemetry.RegisterDBStats(dbConn.DB, cfg.DB.DBName); err != nil {
+			fatal("failed to register database metrics", err)
+		}
+
+		blobs, err := blob.Open(cfg.Attachments.Blob)
+		if err != nil {
+			fatal("failed to open attachment storage", err)
+		}
+
+		resolver = newResolver(dbConn, bus, blobs, cfg.Attachments.MaxSize)
+		pinger = dbConn
+	}
+
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{
+		Complexity: graph.NewComplexityRoot(),
+		Resolvers:  resolver,
+	}))
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			// The API authenticates no one by cookie, so cross-origin subscriptions expose nothing extra
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	})
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		// Leave room for the operations and map fields sent along with the file
+		MaxUploadSize: cfg.Attachments.MaxSize + 1<<20,
+	})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](cfg.GraphQL.APQCacheSize),
+	})
+
+	// Only accept the operations of the web client, checked once persisted queries are resolved
+	if cfg.GraphQL.Allowlist {
+		relayAllowlist, err := allowlist.Relay()
+		if err != nil {
+			fatal("failed to load operation allowlist", err)
+		}
+		srv.Use(relayAllowlist)
 	}
-	defer dbConn.Close()
 
-	// Initialize repositories
-	quizRepo := repository.NewQuizRepository(dbConn)
+	srv.Use(extension.FixedComplexityLimit(cfg.GraphQL.MaxComplexity))
+	srv.Use(depthlimit.DepthLimit{Max: cfg.GraphQL.MaxDepth})
+	srv.Use(telemetry.GraphQL{KnownOperations: knownOperations()})
+	srv.Use(logging.GraphQL{})
+	srv.SetErrorPresenter(graph.ErrorPresenter)
 
-	// Initialize services
+	checker := health.NewChecker(pinger)
+
+	mux := http.NewServeMux()
+	mux.Handle("/query", requestid.Middleware(dataloader.Middleware(newLoaders(resolver))(srv)))
+	mux.Handle("GET /attachments/{id}", requestid.Middleware(attachments.NewHandler(resolver.AttachmentService)))
+	mux.HandleFunc("/healthz", checker.Healthz)
+	mux.HandleFunc("/readyz", checker.Readyz)
+	mux.Handle("/metrics", promhttp.Handler())
+	if cfg.PlaygroundEnabled {
+		mux.Handle("/", playground.Handler("GraphQL playground", "/query"))
+	}
+
+	httpServer := &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           mux,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go resolver.AttachmentService.RunCleanup(ctx, cfg.Attachments.CleanupInterval)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if cfg.PlaygroundEnabled {
+			slog.Info("connect to the GraphQL playground", "url", "http://localhost:"+cfg.Port+"/")
+		} else {
+			slog.Info("serving GraphQL", "url", "http://localhost:"+cfg.Port+"/query")
+		}
+		serverErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		fatal("server failed", err)
+	case <-ctx.Done():
+	}
+
+	// Stop receiving new traffic, then let in-flight requests finish
+	slog.Info("shutting down, draining requests", "timeout", cfg.HTTP.ShutdownTimeout)
+	checker.Drain()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("graceful shutdown failed", "error", err)
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+}
+
+// newResolver wires the services to the SQL repositories of the database, keeping attached files in blobs
+func newResolver(dbConn *bun.DB, bus pubsub.Bus, blobs blob.Store, maxAttachmentSize int64) *resolvers.Resolver {
 	quizService := services.NewQuizService(dbConn)
 	questionService := services.NewQuestionService(dbConn)
 	tagService := services.NewTagService(dbConn)
-	attemptService := services.NewAttemptService(dbConn, questionService)
-	statisticsService := services.NewStatisticsService(dbConn, attemptService)
-
-	// Initialize dataloaders
-	loaders := dataloader.NewLoaders(quizRepo)
-
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{
-		Resolvers: &resolvers.Resolver{
-			DB:                dbConn,
-			QuizService:       quizService,
-			QuestionService:   questionService,
-			TagService:        tagService,
-			AttemptService:    attemptService,
-			StatisticsService: statisticsService,
-		},
-	}))
+	attemptService := services.NewAttemptService(dbConn, bus)
+	statisticsService := services.NewStatisticsService(dbConn, attemptService, bus)
+	roomService := services.NewRoomService(quizService.Repo, attemptService, bus)
 
-	http.Handle("/", playground.Handler("GraphQL playground", "/query"))
-	http.Handle("/query", dataloader.Middleware(loaders)(srv))
+	return &resolvers.Resolver{
+		DB:                dbConn,
+		QuizService:       quizService,
+		QuestionService:   questionService,
+		TagService:        tagService,
+		AttemptService:    attemptService,
+		StatisticsService: statisticsService,
+		RoomService:       roomService,
+		AttachmentService: services.NewAttachmentService(dbConn, blobs, maxAttachmentSize),
+		Renderer:          markup.NewRenderer(markup.DefaultCacheSize),
+	}
+}
 
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+// newLoaders returns the constructor of the dataloaders of every request, reading the repositories of the resolver
+func newLoaders(resolver *resolvers.Resolver) func() *dataloader.Loaders {
+	return func() *dataloader.Loaders {
+		return dataloader.NewLoaders(
+			resolver.QuizService.Repo,
+			resolver.QuestionService.Repo,
+			resolver.TagService.Repo,
+			resolver.AttemptService.Repo,
+			resolver.AttachmentService.Repo,
+		)
+	}
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// knownOperations returns the names of the web client's operations, which metrics label operations with
+func knownOperations() map[string]bool {
+	operations, err := allowlist.RelayOperations()
+	if err != nil {
+		fatal("failed to load operation allowlist", err)
+	}
+
+	names := make(map[string]bool, len(operations))
+	for name := range operations {
+		names[name] = true
 	}
-	return defaultValue
+	return names
+}
+
+// fatal logs an error that prevents the server from running and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"quiz-log/db"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/uptrace/bun"
+
+	"quiz-log/apperrors"
+	"quiz-log/blob"
+	"quiz-log/graph/model"
+	"quiz-log/models"
+	"quiz-log/repository"
+)
+
+// maxFilenameLength matches the VARCHAR(255) of attachments.filename
+const maxFilenameLength = 255
+
+// orphanBatchSize bounds the orphaned attachments loaded at once while cleaning up
+const orphanBatchSize = 100
+
+// sniffLength is the number of bytes http.DetectContentType looks at
+const sniffLength = 512
+
+// allowedAttachmentTypes are the sniffed content types files can be attached as.
+// Text is left out, as SVG and HTML sniff as text and a browser would run the scripts they carry.
+var allowedAttachmentTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+}
+
+type AttachmentService struct {
+	DB           *bun.DB
+	Repo         repository.AttachmentRepository
+	QuestionRepo repository.QuestionRepository
+	Blobs        blob.Store
+	MaxSize      int64
+}
+
+func NewAttachmentService(database *bun.DB, blobs blob.Store, maxSize int64) *AttachmentService {
+	return &AttachmentService{
+		DB:           database,
+		Repo:         repository.NewAttachmentRepository(database),
+		QuestionRepo: repository.NewQuestionRepository(database),
+		Blobs:        blobs,
+		MaxSize:      maxSize,
+	}
+}
+
+// UploadAttachment stores a file and attaches it to the content or explanation of a question.
+// Its content type is sniffed from its first bytes, ignoring the type claimed by the client.
+func (s *AttachmentService) UploadAttachment(ctx context.Context, questionID string, file graphql.Upload, placement *model.AttachmentPlacement) (*model.Attachment, error) {
+	qID, err := parseID("question ID", questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentPlacement := model.AttachmentPlacementContent
+	if placement != nil {
+		if !placement.IsValid() {
+			return nil, apperrors.InvalidArgument("invalid placement %q", *placement)
+		}
+		attachmentPlacement = *placement
+	}
+
+	filename := attachmentFilename(file.Filename)
+	if filename == "" {
+		return nil, apperrors.InvalidArgument("file name is required")
+	}
+	if len(filename) > maxFilenameLength {
+		return nil, apperrors.InvalidArgument("file name must be at most %d bytes", maxFilenameLength)
+	}
+
+	if file.Size <= 0 {
+		return nil, apperrors.InvalidArgument("file is empty")
+	}
+	if file.Size > s.MaxSize {
+		return nil, apperrors.InvalidArgument("file must be at most %d bytes", s.MaxSize)
+	}
+
+	question, err := s.QuestionRepo.FindByID(ctx, qID)
+	if err != nil {
+		return nil, err
+	}
+	if question == nil {
+		return nil, apperrors.NotFound("question %d not found", qID)
+	}
+
+	content := bufio.NewReaderSize(file.File, sniffLength)
+	head, err := content.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head)
+	if !slices.Contains(allowedAttachmentTypes, contentType) {
+		return nil, apperrors.InvalidArgument("files of type %s cannot be attached", contentType)
+	}
+
+	key, err := newStorageKey()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Blobs.Put(ctx, key, io.LimitReader(content, file.Size), file.Size, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	attachmentID, err := s.Repo.Create(ctx, qID, string(attachmentPlacement), filename, contentType, file.Size, key)
+	if err != nil {
+		// Without a record the blob could never be cleaned up
+		if deleteErr := s.Blobs.Delete(ctx, key); deleteErr != nil {
+			slog.ErrorContext(ctx, "failed to delete blob of unsaved attachment", "key", key, "error", deleteErr)
+		}
+		return nil, err
+	}
+
+	attachment, err := s.Repo.FindByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	return db.AttachmentToGraphQL(attachment), nil
+}
+
+// DeleteAttachment deletes an attachment with its file. The file goes first,
+// so a failure leaves a record that can be deleted again rather than a file nothing refers to.
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, id string) (bool, error) {
+	attachmentID, err := parseID("attachment ID", id)
+	if err != nil {
+		return false, err
+	}
+
+	attachment, err := s.Repo.FindByID(ctx, attachmentID)
+	if err != nil {
+		return false, err
+	}
+	if attachment == nil {
+		return false, apperrors.NotFound("attachment %d not found", attachmentID)
+	}
+
+	// The copies of a duplicated question still need the file
+	shared, err := s.Repo.FindSharedStorageKeys(ctx, []int{attachmentID})
+	if err != nil {
+		return false, err
+	}
+	if !shared[attachment.StorageKey] {
+		if err := s.Blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			return false, err
+		}
+	}
+
+	err = s.Repo.Delete(ctx, []int{attachmentID})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// OpenAttachment returns an attachment with its file, to be closed by the caller.
+// The attachments of deleted questions are reported missing even before they are cleaned up.
+func (s *AttachmentService) OpenAttachment(ctx context.Context, id string) (*models.Attachment, io.ReadCloser, error) {
+	attachmentID, err := parseID("attachment ID", id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.Repo.FindByID(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if attachment == nil || attachment.QuestionID == nil {
+		return nil, nil, apperrors.NotFound("attachment %d not found", attachmentID)
+	}
+
+	content, err := s.Blobs.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, nil, apperrors.NotFound("attachment %d not found", attachmentID)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// CleanupOrphans deletes the attachments left by deleted questions along with the files no other
+// attachment shares, returning how many were deleted
+func (s *AttachmentService) CleanupOrphans(ctx context.Context) (int, error) {
+	deleted := 0
+	for {
+		orphans, err := s.Repo.FindOrphans(ctx, orphanBatchSize)
+		if err != nil {
+			return deleted, err
+		}
+
+		ids := make([]int, len(orphans))
+		for i, orphan := range orphans {
+			ids[i] = orphan.ID
+		}
+
+		shared, err := s.Repo.FindSharedStorageKeys(ctx, ids)
+		if err != nil {
+			return deleted, err
+		}
+		for _, orphan := range orphans {
+			if shared[orphan.StorageKey] {
+				continue
+			}
+			if err := s.Blobs.Delete(ctx, orphan.StorageKey); err != nil {
+				return deleted, err
+			}
+		}
+
+		err = s.Repo.Delete(ctx, ids)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += len(orphans)
+
+		if len(orphans) < orphanBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// RunCleanup cleans up orphaned attachments every interval until ctx is done
+func (s *AttachmentService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := s.CleanupOrphans(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to clean up orphaned attachments", "deleted", deleted, "error", err)
+		} else if deleted > 0 {
+			slog.InfoContext(ctx, "cleaned up orphaned attachments", "deleted", deleted)
+		}
+	}
+}
+
+// attachmentFilename strips the directories some browsers send with the name of a file, with either separator
+func attachmentFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+	return strings.TrimSpace(name)
+}
+
+// newStorageKey returns a random key for a new blob, so names chosen by clients never reach the store
+func newStorageKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
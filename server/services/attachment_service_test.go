@@ -0,0 +1,305 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.uber.org/mock/gomock"
+
+	"quiz-log/apperrors"
+	"quiz-log/blob"
+	"quiz-log/graph/model"
+	"quiz-log/models"
+	mocks "quiz-log/repository/mocks"
+)
+
+// pngData starts with the signature of a PNG image
+const pngData = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+func newBlobStore(t *testing.T) *blob.FSStore {
+	t.Helper()
+
+	store, err := blob.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open blob store: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func upload(filename, data string) graphql.Upload {
+	return graphql.Upload{
+		File:        strings.NewReader(data),
+		Filename:    filename,
+		Size:        int64(len(data)),
+		ContentType: "application/octet-stream",
+	}
+}
+
+func readBlob(t *testing.T, store blob.Store, key string) string {
+	t.Helper()
+
+	rc, err := store.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("failed to read blob %q: %v", key, err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("failed to read blob %q: %v", key, err)
+	}
+	return string(data)
+}
+
+func TestAttachmentService_UploadAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+	mockQuestionRepo := mocks.NewMockQuestionRepository(ctrl)
+	blobs := newBlobStore(t)
+	service := &AttachmentService{
+		Repo:         mockRepo,
+		QuestionRepo: mockQuestionRepo,
+		Blobs:        blobs,
+		MaxSize:      1024,
+	}
+
+	ctx := context.Background()
+	placement := model.AttachmentPlacementExplanation
+
+	// Expect the type sniffed from the content and the name without its directories
+	var key string
+	mockQuestionRepo.EXPECT().
+		FindByID(ctx, 3).
+		Return(&models.Question{ID: 3}, nil)
+	mockRepo.EXPECT().
+		Create(ctx, 3, "EXPLANATION", "diagram.png", "image/png", int64(len(pngData)), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, questionID int, placement, filename, contentType string, size int64, storageKey string) (int, error) {
+			key = storageKey
+			return 9, nil
+		})
+	mockRepo.EXPECT().
+		FindByID(ctx, 9).
+		DoAndReturn(func(ctx context.Context, id int) (*models.Attachment, error) {
+			return &models.Attachment{ID: 9, QuestionID: intPtr(3), Placement: "EXPLANATION", Filename: "diagram.png", ContentType: "image/png", Size: int64(len(pngData)), StorageKey: key}, nil
+		})
+
+	// Execute
+	attachment, err := service.UploadAttachment(ctx, "3", upload(`C:\Users\me\diagram.png`, pngData), &placement)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if attachment.ID != "9" || attachment.URL != "/attachments/9" || attachment.ContentType != "image/png" {
+		t.Errorf("unexpected attachment %+v", attachment)
+	}
+	if got := readBlob(t, blobs, key); got != pngData {
+		t.Errorf("expected the file to be stored, got %q", got)
+	}
+}
+
+func TestAttachmentService_UploadAttachment_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     graphql.Upload
+		question *models.Question
+		code     apperrors.Code
+	}{
+		{
+			name:     "too large",
+			file:     upload("big.png", pngData+strings.Repeat("x", 256)),
+			question: &models.Question{ID: 3},
+			code:     apperrors.CodeInvalidArgument,
+		},
+		{
+			name:     "empty",
+			file:     upload("empty.png", ""),
+			question: &models.Question{ID: 3},
+			code:     apperrors.CodeInvalidArgument,
+		},
+		{
+			name:     "no name",
+			file:     upload("/", pngData),
+			question: &models.Question{ID: 3},
+			code:     apperrors.CodeInvalidArgument,
+		},
+		{
+			name:     "scriptable type",
+			file:     upload("image.png", `<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`),
+			question: &models.Question{ID: 3},
+			code:     apperrors.CodeInvalidArgument,
+		},
+		{
+			name: "missing question",
+			file: upload("diagram.png", pngData),
+			code: apperrors.CodeNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockQuestionRepo := mocks.NewMockQuestionRepository(ctrl)
+			service := &AttachmentService{
+				Repo:         mocks.NewMockAttachmentRepository(ctrl),
+				QuestionRepo: mockQuestionRepo,
+				Blobs:        newBlobStore(t),
+				MaxSize:      256,
+			}
+
+			ctx := context.Background()
+
+			mockQuestionRepo.EXPECT().
+				FindByID(ctx, 3).
+				Return(tt.question, nil).
+				AnyTimes()
+
+			// Execute
+			_, err := service.UploadAttachment(ctx, "3", tt.file, nil)
+
+			// Assert
+			if code := apperrors.As(err).Code; code != tt.code {
+				t.Errorf("expected %s, got %v", tt.code, err)
+			}
+		})
+	}
+}
+
+func TestAttachmentService_UploadAttachment_DeletesBlobOnError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+	mockQuestionRepo := mocks.NewMockQuestionRepository(ctrl)
+	blobs := newBlobStore(t)
+	service := &AttachmentService{
+		Repo:         mockRepo,
+		QuestionRepo: mockQuestionRepo,
+		Blobs:        blobs,
+		MaxSize:      1024,
+	}
+
+	ctx := context.Background()
+	expectedErr := errors.New("database error")
+
+	var key string
+	mockQuestionRepo.EXPECT().
+		FindByID(ctx, 3).
+		Return(&models.Question{ID: 3}, nil)
+	mockRepo.EXPECT().
+		Create(ctx, 3, "CONTENT", "diagram.png", "image/png", int64(len(pngData)), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, questionID int, placement, filename, contentType string, size int64, storageKey string) (int, error) {
+			key = storageKey
+			return 0, expectedErr
+		})
+
+	// Execute
+	_, err := service.UploadAttachment(ctx, "3", upload("diagram.png", pngData), nil)
+
+	// Assert
+	if !errors.Is(err, expectedErr) {
+		t.Fatalf("expected %v, got %v", expectedErr, err)
+	}
+
+	if _, err := blobs.Get(ctx, key); !errors.Is(err, blob.ErrNotFound) {
+		t.Errorf("expected the stored file to be deleted, got %v", err)
+	}
+}
+
+func TestAttachmentService_OpenAttachment_Orphaned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+	blobs := newBlobStore(t)
+	service := &AttachmentService{
+		Repo:  mockRepo,
+		Blobs: blobs,
+	}
+
+	ctx := context.Background()
+	if err := blobs.Put(ctx, "orphan", strings.NewReader(pngData), int64(len(pngData)), "image/png"); err != nil {
+		t.Fatalf("failed to store blob: %v", err)
+	}
+
+	// The question of the attachment was deleted, but its file is not cleaned up yet
+	mockRepo.EXPECT().
+		FindByID(ctx, 4).
+		Return(&models.Attachment{ID: 4, StorageKey: "orphan"}, nil)
+
+	// Execute
+	_, _, err := service.OpenAttachment(ctx, "4")
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeNotFound {
+		t.Errorf("expected %s, got %v", apperrors.CodeNotFound, err)
+	}
+}
+
+func TestAttachmentService_CleanupOrphans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAttachmentRepository(ctrl)
+	blobs := newBlobStore(t)
+	service := &AttachmentService{
+		Repo:  mockRepo,
+		Blobs: blobs,
+	}
+
+	ctx := context.Background()
+	for _, key := range []string{"first", "second", "shared"} {
+		if err := blobs.Put(ctx, key, strings.NewReader("x"), 1, "application/pdf"); err != nil {
+			t.Fatalf("failed to store blob: %v", err)
+		}
+	}
+
+	// Expect the files to go before their records, one of them already missing
+	// and one kept for the copy of a duplicated question
+	mockRepo.EXPECT().
+		FindOrphans(ctx, orphanBatchSize).
+		Return([]*models.Attachment{
+			{ID: 1, StorageKey: "first"},
+			{ID: 2, StorageKey: "second"},
+			{ID: 3, StorageKey: "gone"},
+			{ID: 4, StorageKey: "shared"},
+		}, nil)
+	mockRepo.EXPECT().
+		FindSharedStorageKeys(ctx, []int{1, 2, 3, 4}).
+		Return(map[string]bool{"shared": true}, nil)
+	mockRepo.EXPECT().
+		Delete(ctx, []int{1, 2, 3, 4}).
+		Return(nil)
+
+	// Execute
+	deleted, err := service.CleanupOrphans(ctx)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if deleted != 4 {
+		t.Errorf("expected 4 attachments deleted, got %d", deleted)
+	}
+	for _, key := range []string{"first", "second"} {
+		if _, err := blobs.Get(ctx, key); !errors.Is(err, blob.ErrNotFound) {
+			t.Errorf("expected blob %q to be deleted, got %v", key, err)
+		}
+	}
+	content, err := blobs.Get(ctx, "shared")
+	if err != nil {
+		t.Fatalf("expected the shared blob to be kept, got %v", err)
+	}
+	content.Close()
+}
@@ -2,40 +2,87 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"quiz-log/db"
-	"strconv"
 	"time"
 
 	"github.com/uptrace/bun"
 
+	"quiz-log/apperrors"
+	"quiz-log/grading"
 	"quiz-log/graph/model"
+	"quiz-log/models"
+	"quiz-log/pubsub"
 	"quiz-log/repository"
 )
 
 type AttemptService struct {
-	DB              *bun.DB
-	Repo            repository.AttemptRepository
-	QuestionService *QuestionService
+	DB       *bun.DB
+	Repo     repository.AttemptRepository
+	QuizRepo repository.QuizRepository
+	Bus      pubsub.Bus
 }
 
-func NewAttemptService(database *bun.DB, questionService *QuestionService) *AttemptService {
+func NewAttemptService(database *bun.DB, bus pubsub.Bus) *AttemptService {
 	return &AttemptService{
-		DB:              database,
-		Repo:            repository.NewAttemptRepository(database),
-		QuestionService: questionService,
+		DB:       database,
+		Repo:     repository.NewAttemptRepository(database),
+		QuizRepo: repository.NewQuizRepository(database),
+		Bus:      bus,
 	}
 }
 
 // SubmitAttempt creates a new attempt and processes answers
 func (s *AttemptService) SubmitAttempt(ctx context.Context, input model.SubmitAttemptInput) (*model.AttemptResult, error) {
-	quizID, _ := strconv.Atoi(input.QuizID)
+	quizID, err := parseID("quiz ID", input.QuizID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate answer IDs before anything is stored, each question is answered at most once
+	questionIDs := make([]int, len(input.Answers))
+	answered := make(map[int]bool, len(input.Answers))
+	for i, answer := range input.Answers {
+		questionIDs[i], err = parseID("question ID", answer.QuestionID)
+		if err != nil {
+			return nil, err
+		}
+		if answered[questionIDs[i]] {
+			return nil, apperrors.InvalidArgument("question %d is answered more than once", questionIDs[i])
+		}
+		answered[questionIDs[i]] = true
+	}
+
+	err = s.ensureQuizPublished(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Get all questions for the quiz to calculate total
-	totalQuestions, err := s.Repo.CountQuestionsByQuizID(ctx, quizID)
+	// Load the questions of the quiz in one query, only they can be answered
+	questions, err := s.QuizRepo.FindQuestionsByQuizID(ctx, quizID)
 	if err != nil {
 		return nil, err
 	}
 
+	questionsByID := make(map[int]*models.Question, len(questions))
+	for _, q := range questions {
+		questionsByID[q.ID] = q
+	}
+	for _, questionID := range questionIDs {
+		if _, ok := questionsByID[questionID]; !ok {
+			return nil, apperrors.InvalidArgument("question %d is not part of quiz %d", questionID, quizID)
+		}
+	}
+
+	totalQuestions := len(questions)
+	totalPoints := 0
+	for _, q := range questions {
+		totalPoints += questionPoints(q)
+	}
+
 	// Create attempt record
 	attemptID, err := s.Repo.Create(ctx, quizID, time.Now(), time.Now(), 0, totalQuestions)
 	if err != nil {
@@ -44,37 +91,31 @@ func (s *AttemptService) SubmitAttempt(ctx context.Context, input model.SubmitAt
 
 	// Process answers and calculate score
 	correctCount := 0
-	var wrongQuestionIDs []int
-
-	for _, answer := range input.Answers {
-		questionID, _ := strconv.Atoi(answer.QuestionID)
+	earnedPoints := 0.0
+	var wrongQuestions []*model.Question
 
-		// Get correct answer
-		correctAnswer, err := s.Repo.GetCorrectAnswer(ctx, questionID)
-		if err != nil {
-			return nil, err
-		}
+	for i, answer := range input.Answers {
+		question := questionsByID[questionIDs[i]]
 
-		// Check if answer is correct
-		isCorrect := answer.UserAnswer == correctAnswer
+		// Grade the answer, only full credit counts as correct
+		credit := grading.Grade(question, answer.UserAnswer)
+		blanks := grading.GradeBlanks(question, answer.UserAnswer)
+		isCorrect := credit == 1
+		earnedPoints += credit * float64(questionPoints(question))
 		if isCorrect {
 			correctCount++
 		} else {
-			wrongQuestionIDs = append(wrongQuestionIDs, questionID)
+			wrongQuestions = append(wrongQuestions, db.QuestionToGraphQL(question))
 		}
 
 		// Save answer
-		err = s.Repo.CreateAnswer(ctx, attemptID, questionID, answer.UserAnswer, isCorrect)
+		err = s.Repo.CreateAnswer(ctx, attemptID, question.ID, answer.UserAnswer, isCorrect, credit, blanks)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	// Calculate score percentage
-	score := 0
-	if totalQuestions > 0 {
-		score = (correctCount * 100) / totalQuestions
-	}
+	score := attemptScore(earnedPoints, totalPoints)
 
 	// Update attempt with final score
 	err = s.Repo.UpdateScore(ctx, attemptID, score)
@@ -88,19 +129,7 @@ func (s *AttemptService) SubmitAttempt(ctx context.Context, input model.SubmitAt
 		return nil, err
 	}
 
-	// Get wrong questions
-	var wrongQuestions []*model.Question
-	if len(wrongQuestionIDs) > 0 {
-		for _, qid := range wrongQuestionIDs {
-			q, err := s.QuestionService.GetQuestionByID(ctx, strconv.Itoa(qid))
-			if err != nil {
-				return nil, err
-			}
-			if q != nil {
-				wrongQuestions = append(wrongQuestions, q)
-			}
-		}
-	}
+	s.publishAttemptSubmitted(ctx, attemptID, quizID)
 
 	return &model.AttemptResult{
 		Attempt:        db.AttemptToGraphQL(dbAttempt),
@@ -111,11 +140,47 @@ func (s *AttemptService) SubmitAttempt(ctx context.Context, input model.SubmitAt
 	}, nil
 }
 
+// attemptScore is the percentage of the points of a quiz earned, partial credit included
+func attemptScore(earnedPoints float64, totalPoints int) int {
+	if totalPoints <= 0 {
+		return 0
+	}
+	return int(earnedPoints * 100 / float64(totalPoints))
+}
+
+// questionPoints is what a question loaded with its quiz is worth
+func questionPoints(q *models.Question) int {
+	if q.Points == nil {
+		return defaultQuestionPoints
+	}
+	return *q.Points
+}
+
+// ensureQuizPublished checks that a quiz exists and can be attempted
+func (s *AttemptService) ensureQuizPublished(ctx context.Context, quizID int) error {
+	status, err := s.Repo.FindQuizStatus(ctx, quizID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound("quiz %d not found", quizID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if status != string(model.QuizStatusPublished) {
+		return apperrors.Conflict("quiz %d is not published", quizID)
+	}
+
+	return nil
+}
+
 // GetAttempts retrieves attempts, optionally filtered by quiz ID
 func (s *AttemptService) GetAttempts(ctx context.Context, quizID *string) ([]*model.Attempt, error) {
 	var qid *int
 	if quizID != nil {
-		id, _ := strconv.Atoi(*quizID)
+		id, err := parseID("quiz ID", *quizID)
+		if err != nil {
+			return nil, err
+		}
 		qid = &id
 	}
 
@@ -134,7 +199,7 @@ func (s *AttemptService) GetAttempts(ctx context.Context, quizID *string) ([]*mo
 
 // GetAnswersByAttemptID retrieves all answers for an attempt
 func (s *AttemptService) GetAnswersByAttemptID(ctx context.Context, attemptID string) ([]*model.Answer, error) {
-	id, err := strconv.Atoi(attemptID)
+	id, err := parseID("attempt ID", attemptID)
 	if err != nil {
 		return nil, err
 	}
@@ -151,3 +216,68 @@ func (s *AttemptService) GetAnswersByAttemptID(ctx context.Context, attemptID st
 
 	return answers, nil
 }
+
+// publishAttemptSubmitted notifies subscribers of a new attempt and of the statistics it changed.
+// The attempt is already stored, so a failure to publish is logged rather than returned.
+func (s *AttemptService) publishAttemptSubmitted(ctx context.Context, attemptID, quizID int) {
+	payload, err := json.Marshal(attemptSubmittedEvent{AttemptID: attemptID, QuizID: quizID})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to encode attempt event", "attempt_id", attemptID, "error", err)
+		return
+	}
+
+	if err := s.Bus.Publish(ctx, TopicAttemptSubmitted, payload); err != nil {
+		slog.ErrorContext(ctx, "failed to publish attempt", "attempt_id", attemptID, "error", err)
+	}
+
+	if err := s.Bus.Publish(ctx, TopicStatisticsUpdated, nil); err != nil {
+		slog.ErrorContext(ctx, "failed to publish statistics update", "error", err)
+	}
+}
+
+// SubscribeAttemptSubmitted streams the attempts submitted for a quiz until ctx is done
+func (s *AttemptService) SubscribeAttemptSubmitted(ctx context.Context, quizID string) (<-chan *model.Attempt, error) {
+	id, err := parseID("quiz ID", quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.Bus.Subscribe(ctx, TopicAttemptSubmitted)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make(chan *model.Attempt)
+	go func() {
+		defer close(attempts)
+
+		for payload := range events {
+			var event attemptSubmittedEvent
+			if err := json.Unmarshal(payload, &event); err != nil {
+				slog.ErrorContext(ctx, "failed to decode attempt event", "error", err)
+				continue
+			}
+
+			if event.QuizID != id {
+				continue
+			}
+
+			dbAttempt, err := s.Repo.FindByID(ctx, event.AttemptID)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to load attempt", "attempt_id", event.AttemptID, "error", err)
+				continue
+			}
+			if dbAttempt == nil {
+				continue
+			}
+
+			select {
+			case attempts <- db.AttemptToGraphQL(dbAttempt):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return attempts, nil
+}
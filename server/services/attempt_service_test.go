@@ -2,13 +2,19 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"quiz-log/models"
+	"slices"
+	"strconv"
 	"testing"
 	"time"
 
 	"go.uber.org/mock/gomock"
 
+	"quiz-log/apperrors"
 	"quiz-log/graph/model"
+	"quiz-log/pubsub"
+	"quiz-log/repository/memory"
 	mocks "quiz-log/repository/mocks"
 )
 
@@ -22,15 +28,13 @@ func TestAttemptService_SubmitAttempt(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockAttemptRepo := mocks.NewMockAttemptRepository(ctrl)
-	mockQuestionRepo := mocks.NewMockQuestionRepository(ctrl)
-
-	mockQuestionService := &QuestionService{
-		Repo: mockQuestionRepo,
-	}
+	mockQuizRepo := mocks.NewMockQuizRepository(ctrl)
 
+	bus := pubsub.NewMemoryBus()
 	service := &AttemptService{
-		Repo:            mockAttemptRepo,
-		QuestionService: mockQuestionService,
+		Repo:     mockAttemptRepo,
+		QuizRepo: mockQuizRepo,
+		Bus:      bus,
 	}
 
 	ctx := context.Background()
@@ -42,38 +46,61 @@ func TestAttemptService_SubmitAttempt(t *testing.T) {
 		},
 	}
 
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events, err := bus.Subscribe(subCtx, TopicAttemptSubmitted)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
 	totalQuestions := 2
 	attemptID := 1
 	startTime := time.Now()
 
-	// Expect CountQuestionsByQuizID to be called
+	// Expect FindQuizStatus to be called
 	mockAttemptRepo.EXPECT().
-		CountQuestionsByQuizID(ctx, 1).
-		Return(totalQuestions, nil)
+		FindQuizStatus(ctx, 1).
+		Return("PUBLISHED", nil)
+
+	// Expect the questions of the quiz to be fetched at once
+	mockQuizRepo.EXPECT().
+		FindQuestionsByQuizID(ctx, 1).
+		Return([]*models.Question{
+			{
+				ID:            1,
+				Type:          "SHORT_ANSWER",
+				Content:       "What is the capital of France?",
+				CorrectAnswer: "Paris",
+				Difficulty:    "EASY",
+				CreatedAt:     startTime,
+				UpdatedAt:     startTime,
+			},
+			{
+				ID:            2,
+				Type:          "MULTIPLE_CHOICE",
+				Content:       "What is the capital of Japan?",
+				Options:       []string{"Tokyo", "London", "Paris"},
+				CorrectAnswer: "Tokyo",
+				Difficulty:    "EASY",
+				CreatedAt:     startTime,
+				UpdatedAt:     startTime,
+			},
+		}, nil)
 
 	// Expect Create to be called
 	mockAttemptRepo.EXPECT().
 		Create(ctx, 1, gomock.Any(), gomock.Any(), 0, totalQuestions).
 		Return(attemptID, nil)
 
-	// Expect GetCorrectAnswer for question 1
-	mockAttemptRepo.EXPECT().
-		GetCorrectAnswer(ctx, 1).
-		Return("Paris", nil)
-
 	// Expect CreateAnswer for question 1 (correct)
 	mockAttemptRepo.EXPECT().
-		CreateAnswer(ctx, attemptID, 1, "Paris", true).
+		CreateAnswer(ctx, attemptID, 1, "Paris", true, 1.0, nil).
 		Return(nil)
 
-	// Expect GetCorrectAnswer for question 2
-	mockAttemptRepo.EXPECT().
-		GetCorrectAnswer(ctx, 2).
-		Return("Tokyo", nil)
-
 	// Expect CreateAnswer for question 2 (incorrect)
 	mockAttemptRepo.EXPECT().
-		CreateAnswer(ctx, attemptID, 2, "London", false).
+		CreateAnswer(ctx, attemptID, 2, "London", false, 0.0, nil).
 		Return(nil)
 
 	// Expect UpdateScore to be called with 50% score (1 out of 2 correct)
@@ -93,21 +120,6 @@ func TestAttemptService_SubmitAttempt(t *testing.T) {
 			TotalQuestions: totalQuestions,
 		}, nil)
 
-	// Expect GetQuestionByID for wrong question
-	mockQuestionRepo.EXPECT().
-		FindByID(ctx, 2).
-		Return(&models.Question{
-			ID:            2,
-			QuizID:        intPtr(1),
-			Type:          "MULTIPLE_CHOICE",
-			Content:       "What is the capital of Japan?",
-			Options:       []string{"Tokyo", "London", "Paris"},
-			CorrectAnswer: "Tokyo",
-			Difficulty:    "EASY",
-			CreatedAt:     startTime,
-			UpdatedAt:     startTime,
-		}, nil)
-
 	// Execute
 	result, err := service.SubmitAttempt(ctx, input)
 
@@ -132,8 +144,411 @@ func TestAttemptService_SubmitAttempt(t *testing.T) {
 		t.Errorf("expected correct_count 1, got %d", result.CorrectCount)
 	}
 
-	if len(result.WrongQuestions) != 1 {
-		t.Errorf("expected 1 wrong question, got %d", len(result.WrongQuestions))
+	if len(result.WrongQuestions) != 1 || result.WrongQuestions[0].ID != "2" {
+		t.Errorf("expected wrong question 2, got %v", result.WrongQuestions)
+	}
+
+	// The attempt is announced to subscribers
+	select {
+	case payload := <-events:
+		if string(payload) != `{"attemptId":1,"quizId":1}` {
+			t.Errorf("unexpected event '%s'", payload)
+		}
+	default:
+		t.Error("expected attempt event, got none")
+	}
+}
+
+// newMemoryAttemptService creates an AttemptService on an in-memory store holding quiz 1,
+// published with the given questions in order and worth their points
+func newMemoryAttemptService(t *testing.T, questions ...*models.Question) *AttemptService {
+	t.Helper()
+
+	store := memory.NewStore()
+	quizRepo := memory.NewQuizRepository(store)
+	questionRepo := memory.NewQuestionRepository(store)
+	ctx := context.Background()
+
+	quizID, err := quizRepo.Create(ctx, "Quiz", nil)
+	if err != nil {
+		t.Fatalf("failed to create quiz: %v", err)
+	}
+
+	for _, q := range questions {
+		questionID, err := questionRepo.Create(ctx, q.Type, q.Content, "PLAIN", q.Options, q.Payload, q.CorrectAnswer, q.Grading, nil, "EASY")
+		if err != nil {
+			t.Fatalf("failed to create question: %v", err)
+		}
+		if err := questionRepo.AddToQuiz(ctx, quizID, questionID, questionPoints(q)); err != nil {
+			t.Fatalf("failed to add question: %v", err)
+		}
+	}
+
+	if err := quizRepo.UpdateStatus(ctx, quizID, "PUBLISHED"); err != nil {
+		t.Fatalf("failed to publish quiz: %v", err)
+	}
+
+	return &AttemptService{
+		Repo:     memory.NewAttemptRepository(store),
+		QuizRepo: quizRepo,
+		Bus:      pubsub.NewMemoryBus(),
+	}
+}
+
+// storedAnswers returns the answers recorded for the attempt of a submission
+func storedAnswers(t *testing.T, service *AttemptService, result *model.AttemptResult) []*models.Answer {
+	t.Helper()
+
+	attemptID, err := strconv.Atoi(result.Attempt.ID)
+	if err != nil {
+		t.Fatalf("invalid attempt ID: %v", err)
+	}
+
+	answers, err := service.Repo.FindAnswersByAttemptID(context.Background(), attemptID)
+	if err != nil {
+		t.Fatalf("failed to load answers: %v", err)
+	}
+	return answers
+}
+
+func TestAttemptService_SubmitAttempt_PartialCredit(t *testing.T) {
+	// The same order with one pair swapped, once graded partially and once exactly,
+	// then capitals matched from the sorted answers Berlin, Paris and Rome with one right
+	handshake := []string{"ACK", "SYN", "SYN-ACK", "DATA"}
+	capitals := models.Payload{Pairs: []models.MatchingPair{
+		{Prompt: "France", Answer: "Paris"},
+		{Prompt: "Italy", Answer: "Rome"},
+		{Prompt: "Germany", Answer: "Berlin"},
+	}}
+	service := newMemoryAttemptService(t,
+		&models.Question{Type: "ORDERING", Content: "Open a connection", Options: handshake, CorrectAnswer: "1,2,0,3", Grading: "PARTIAL"},
+		&models.Question{Type: "ORDERING", Content: "Open a connection", Options: handshake, CorrectAnswer: "1,2,0,3", Grading: "EXACT"},
+		&models.Question{Type: "MATCHING", Content: "Match the capitals", Payload: capitals, Grading: "PARTIAL"},
+	)
+
+	input := model.SubmitAttemptInput{
+		QuizID: "1",
+		Answers: []*model.AnswerInput{
+			{QuestionID: "1", UserAnswer: "2,1,0,3"},
+			{QuestionID: "2", UserAnswer: "2,1,0,3"},
+			{QuestionID: "3", UserAnswer: "1,0,2"},
+		},
+	}
+
+	// Execute
+	result, err := service.SubmitAttempt(context.Background(), input)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Score != 38 || result.CorrectCount != 0 {
+		t.Errorf("expected score 38 without a correct answer, got %d and %d", result.Score, result.CorrectCount)
+	}
+	if len(result.WrongQuestions) != 3 {
+		t.Errorf("expected partly right answers among the wrong questions, got %v", result.WrongQuestions)
+	}
+
+	// 5 of the 6 pairs in order earn 5/6 of the first question, the exact one earns nothing,
+	// and one of the three pairs matched earns a third of the last question
+	answers := storedAnswers(t, service, result)
+	var credits []float64
+	for _, a := range answers {
+		credits = append(credits, a.Credit)
+	}
+	if !slices.Equal(credits, []float64{1 - 1.0/6, 0, 1.0 / 3}) {
+		t.Errorf("expected credits [5/6 0 1/3], got %v", credits)
+	}
+}
+
+func TestAttemptService_SubmitAttempt_WeightedByPoints(t *testing.T) {
+	service := newMemoryAttemptService(t,
+		&models.Question{Type: "TRUE_FALSE", Content: "Paris is in France", CorrectAnswer: "true", Points: intPtr(3)},
+		&models.Question{Type: "TRUE_FALSE", Content: "Rome is in Spain", CorrectAnswer: "false", Points: intPtr(1)},
+	)
+
+	input := model.SubmitAttemptInput{
+		QuizID: "1",
+		Answers: []*model.AnswerInput{
+			{QuestionID: "1", UserAnswer: "true"},
+			{QuestionID: "2", UserAnswer: "true"},
+		},
+	}
+
+	// Execute
+	result, err := service.SubmitAttempt(context.Background(), input)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Half of the questions are right, but they are worth 3 of the 4 points
+	if result.Score != 75 || result.CorrectCount != 1 {
+		t.Errorf("expected score 75 with one correct answer, got %d and %d", result.Score, result.CorrectCount)
+	}
+}
+
+func TestAttemptService_SubmitAttempt_Cloze(t *testing.T) {
+	// {{1}} lies on the {{2}} and has {{3}} inhabitants
+	blanks := []models.ClozeBlank{
+		{Accepted: []string{"Paris"}, Strategy: "EXACT"},
+		{Accepted: []string{"Seine"}, Strategy: "IGNORE_CASE"},
+		{Accepted: []string{"2100000"}, Strategy: "NUMERIC"},
+	}
+	service := newMemoryAttemptService(t,
+		&models.Question{Type: "CLOZE", Content: "{{1}} lies on the {{2}} and has {{3}} inhabitants", Payload: models.Payload{Blanks: blanks}, Grading: "PARTIAL"},
+	)
+
+	input := model.SubmitAttemptInput{
+		QuizID: "1",
+		Answers: []*model.AnswerInput{
+			{QuestionID: "1", UserAnswer: `["Paris", "Thames", "2.1 million"]`},
+		},
+	}
+
+	// Execute
+	result, err := service.SubmitAttempt(context.Background(), input)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Score != 33 || result.CorrectCount != 0 {
+		t.Errorf("expected score 33 without a correct answer, got %d and %d", result.Score, result.CorrectCount)
+	}
+
+	// The blank filled in right earns a third, stored with the breakdown
+	answers := storedAnswers(t, service, result)
+	if len(answers) != 1 || answers[0].Credit != 1.0/3 || !slices.Equal(answers[0].Blanks, models.Blanks{true, false, false}) {
+		t.Errorf("expected a third of the credit with blanks [true false false], got %+v", answers)
+	}
+}
+
+func TestAttemptService_SubscribeAttemptSubmitted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttemptRepo := mocks.NewMockAttemptRepository(ctrl)
+	bus := pubsub.NewMemoryBus()
+	service := &AttemptService{
+		Repo: mockAttemptRepo,
+		Bus:  bus,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Expect FindByID to be called for the attempt of the subscribed quiz only
+	mockAttemptRepo.EXPECT().
+		FindByID(gomock.Any(), 2).
+		Return(&models.Attempt{ID: 2, QuizID: intPtr(1), Score: 100, TotalQuestions: 1}, nil)
+
+	// Execute
+	attempts, err := service.SubscribeAttemptSubmitted(ctx, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service.publishAttemptSubmitted(ctx, 1, 3)
+	service.publishAttemptSubmitted(ctx, 2, 1)
+
+	// Assert
+	select {
+	case attempt := <-attempts:
+		if attempt.ID != "2" {
+			t.Errorf("expected attempt '2', got '%s'", attempt.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected attempt, got none")
+	}
+}
+
+func TestAttemptService_SubscribeAttemptSubmitted_Deleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttemptRepo := mocks.NewMockAttemptRepository(ctrl)
+	bus := pubsub.NewMemoryBus()
+	service := &AttemptService{
+		Repo: mockAttemptRepo,
+		Bus:  bus,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Expect the deleted attempt to be skipped and the next one delivered
+	gomock.InOrder(
+		mockAttemptRepo.EXPECT().
+			FindByID(gomock.Any(), 2).
+			Return(nil, nil),
+		mockAttemptRepo.EXPECT().
+			FindByID(gomock.Any(), 3).
+			Return(&models.Attempt{ID: 3, QuizID: intPtr(1), Score: 100, TotalQuestions: 1}, nil),
+	)
+
+	// Execute
+	attempts, err := service.SubscribeAttemptSubmitted(ctx, "1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	service.publishAttemptSubmitted(ctx, 2, 1)
+	service.publishAttemptSubmitted(ctx, 3, 1)
+
+	// Assert
+	select {
+	case attempt := <-attempts:
+		if attempt.ID != "3" {
+			t.Errorf("expected attempt '3', got '%s'", attempt.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected attempt, got none")
+	}
+}
+
+func TestAttemptService_SubmitAttempt_NotPublished(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttemptRepo := mocks.NewMockAttemptRepository(ctrl)
+	service := &AttemptService{
+		Repo: mockAttemptRepo,
+	}
+
+	ctx := context.Background()
+	input := model.SubmitAttemptInput{
+		QuizID: "1",
+		Answers: []*model.AnswerInput{
+			{QuestionID: "1", UserAnswer: "Paris"},
+		},
+	}
+
+	for _, status := range []string{"DRAFT", "ARCHIVED"} {
+		// Expect FindQuizStatus to be called and nothing to be recorded
+		mockAttemptRepo.EXPECT().
+			FindQuizStatus(ctx, 1).
+			Return(status, nil)
+
+		// Execute
+		result, err := service.SubmitAttempt(ctx, input)
+
+		// Assert
+		if err == nil {
+			t.Errorf("expected error for %s quiz, got nil", status)
+		}
+
+		if result != nil {
+			t.Errorf("expected nil result for %s quiz, got %v", status, result)
+		}
+	}
+}
+
+func TestAttemptService_SubmitAttempt_QuizNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAttemptRepo := mocks.NewMockAttemptRepository(ctrl)
+	service := &AttemptService{
+		Repo: mockAttemptRepo,
+	}
+
+	ctx := context.Background()
+	input := model.SubmitAttemptInput{
+		QuizID: "1",
+	}
+
+	// Expect FindQuizStatus to find no quiz
+	mockAttemptRepo.EXPECT().
+		FindQuizStatus(ctx, 1).
+		Return("", sql.ErrNoRows)
+
+	// Execute
+	_, err := service.SubmitAttempt(ctx, input)
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeNotFound {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeNotFound, code)
+	}
+}
+
+func TestAttemptService_SubmitAttempt_InvalidQuestionID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Nothing is looked up or recorded for a malformed answer
+	mockAttemptRepo := mocks.NewMockAttemptRepository(ctrl)
+	service := &AttemptService{
+		Repo: mockAttemptRepo,
+	}
+
+	input := model.SubmitAttemptInput{
+		QuizID: "1",
+		Answers: []*model.AnswerInput{
+			{QuestionID: "one", UserAnswer: "Paris"},
+		},
+	}
+
+	// Execute
+	_, err := service.SubmitAttempt(context.Background(), input)
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeInvalidArgument, code)
+	}
+}
+
+func TestAttemptService_SubmitAttempt_InvalidAnswers(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []*model.AnswerInput
+	}{
+		{
+			name:    "missing question",
+			answers: []*model.AnswerInput{{QuestionID: "1", UserAnswer: "Paris"}, {QuestionID: "9", UserAnswer: "Rome"}},
+		},
+		{
+			name:    "question of another quiz",
+			answers: []*model.AnswerInput{{QuestionID: "3", UserAnswer: "Madrid"}},
+		},
+		{
+			name:    "question answered twice",
+			answers: []*model.AnswerInput{{QuestionID: "1", UserAnswer: "Paris"}, {QuestionID: "1", UserAnswer: "Paris"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newMemoryAttemptService(t,
+				&models.Question{Type: "SHORT_ANSWER", Content: "Capital of France?", CorrectAnswer: "Paris", Grading: "EXACT"},
+				&models.Question{Type: "SHORT_ANSWER", Content: "Capital of Italy?", CorrectAnswer: "Rome", Grading: "EXACT"},
+			)
+			ctx := context.Background()
+
+			// The draft copy of the quiz holds questions 3 and 4
+			if _, err := service.QuizRepo.Duplicate(ctx, 1, "Draft", false, nil); err != nil {
+				t.Fatalf("failed to duplicate quiz: %v", err)
+			}
+
+			// Execute
+			_, err := service.SubmitAttempt(ctx, model.SubmitAttemptInput{QuizID: "1", Answers: tt.answers})
+
+			// Assert
+			if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+				t.Errorf("expected code '%s', got '%s' (%v)", apperrors.CodeInvalidArgument, code, err)
+			}
+
+			attempts, err := service.Repo.FindAll(ctx, nil)
+			if err != nil {
+				t.Fatalf("failed to load attempts: %v", err)
+			}
+			if len(attempts) != 0 {
+				t.Errorf("expected no attempt to be recorded, got %d", len(attempts))
+			}
+		})
 	}
 }
 
@@ -154,7 +569,7 @@ func TestAttemptService_GetAttempts(t *testing.T) {
 	expectedAttempts := []*models.Attempt{
 		{
 			ID:             1,
-			QuizID:         quizIDInt,
+			QuizID:         &quizIDInt,
 			StartedAt:      startTime,
 			CompletedAt:    &startTime,
 			Score:          80,
@@ -162,7 +577,7 @@ func TestAttemptService_GetAttempts(t *testing.T) {
 		},
 		{
 			ID:             2,
-			QuizID:         quizIDInt,
+			QuizID:         &quizIDInt,
 			StartedAt:      startTime,
 			CompletedAt:    &startTime,
 			Score:          90,
@@ -211,7 +626,7 @@ func TestAttemptService_GetAttempts_WithoutFilter(t *testing.T) {
 	expectedAttempts := []*models.Attempt{
 		{
 			ID:             1,
-			QuizID:         1,
+			QuizID:         intPtr(1),
 			StartedAt:      startTime,
 			CompletedAt:    &startTime,
 			Score:          80,
@@ -252,15 +667,15 @@ func TestAttemptService_GetAnswersByAttemptID(t *testing.T) {
 	expectedAnswers := []*models.Answer{
 		{
 			ID:         1,
-			AttemptID:  1,
-			QuestionID: 1,
+			AttemptID:  intPtr(1),
+			QuestionID: intPtr(1),
 			UserAnswer: "Paris",
 			IsCorrect:  true,
 		},
 		{
 			ID:         2,
-			AttemptID:  1,
-			QuestionID: 2,
+			AttemptID:  intPtr(1),
+			QuestionID: intPtr(2),
 			UserAnswer: "London",
 			IsCorrect:  false,
 		},
@@ -0,0 +1,21 @@
+package services
+
+// Topics published on the pub/sub bus
+const (
+	TopicAttemptSubmitted  = "attempt_submitted"
+	TopicStatisticsUpdated = "statistics_updated"
+	TopicRoomUpdated       = "room_updated"
+)
+
+// roomUpdatedTopic is the topic the changes of one room are published on, so a busy room
+// cannot crowd out the updates of another
+func roomUpdatedTopic(code string) string {
+	return TopicRoomUpdated + ":" + code
+}
+
+// attemptSubmittedEvent is published once an attempt has been scored.
+// It carries IDs only so it stays small enough for any bus backend.
+type attemptSubmittedEvent struct {
+	AttemptID int `json:"attemptId"`
+	QuizID    int `json:"quizId"`
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"strconv"
+
+	"quiz-log/apperrors"
+)
+
+// parseID converts a GraphQL ID into a database ID, rejecting anything that is not a number
+func parseID(name string, id string) (int, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, apperrors.InvalidArgument("invalid %s %q", name, id)
+	}
+	return n, nil
+}
+
+// parseIDs converts GraphQL IDs like parseID, keeping the first of repeated IDs
+func parseIDs(name string, ids []string) ([]int, error) {
+	result := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		n, err := parseID(name, id)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
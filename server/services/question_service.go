@@ -4,14 +4,24 @@ import (
 	"context"
 	"encoding/json"
 	"quiz-log/db"
+	"slices"
 	"strconv"
 
 	"github.com/uptrace/bun"
 
+	"quiz-log/apperrors"
 	"quiz-log/graph/model"
+	"quiz-log/models"
 	"quiz-log/repository"
+	"quiz-log/validation"
 )
 
+// defaultQuestionPoints is the number of points a question is worth in a quiz unless set otherwise
+const defaultQuestionPoints = 1
+
+// maxBulkQuestions bounds the questions of a bulk operation, keeping its statements within the parameter limits of the database
+const maxBulkQuestions = 500
+
 type QuestionService struct {
 	DB   *bun.DB
 	Repo repository.QuestionRepository
@@ -24,36 +34,88 @@ func NewQuestionService(database *bun.DB) *QuestionService {
 	}
 }
 
-// CreateQuestion creates a new question
+// CreateQuestion creates a new question in the question bank, adding it to a quiz when one is given
 func (s *QuestionService) CreateQuestion(ctx context.Context, input model.CreateQuestionInput) (*model.Question, error) {
-	questionID, err := s.Repo.Create(ctx, input.QuizID, string(input.Type), input.Content, input.Options, input.CorrectAnswer, input.Explanation, string(input.Difficulty))
-	if err != nil {
-		return nil, err
+	if errs := validateCreateQuestionInput(input); len(errs) > 0 {
+		return nil, errs.Prefix("input")
 	}
 
-	// Associate tags
-	if len(input.TagIDs) > 0 {
-		err = s.Repo.AssociateTags(ctx, questionID, input.TagIDs)
+	contentFormat := model.ContentFormatPlain
+	if input.ContentFormat != nil {
+		contentFormat = *input.ContentFormat
+	}
+
+	grading := defaultGrading(input.Type)
+	if input.Grading != nil {
+		grading = *input.Grading
+	}
+
+	payload := models.Payload{Pairs: matchingPairs(input.Pairs), Numeric: numericAnswer(input.Numeric), Blanks: clozeBlanks(input.Blanks)}
+
+	var quizID *int
+	if input.QuizID != nil {
+		qid, err := parseID("quiz ID", *input.QuizID)
 		if err != nil {
 			return nil, err
 		}
+		quizID = &qid
+	}
+
+	points := defaultQuestionPoints
+	if input.Points != nil {
+		points = *input.Points
+	}
+
+	questionID, err := s.Repo.CreateWithLinks(ctx, string(input.Type), input.Content, string(contentFormat), input.Options, payload, input.CorrectAnswer, string(grading), input.Explanation, string(input.Difficulty), input.TagIDs, quizID, points)
+	if err != nil {
+		return nil, err
+	}
+
+	question, err := s.GetQuestionByID(ctx, strconv.Itoa(questionID))
+	if err != nil {
+		return nil, err
 	}
 
-	return s.GetQuestionByID(ctx, strconv.Itoa(questionID))
+	if question != nil {
+		question.QuizID = input.QuizID
+	}
+
+	return question, nil
 }
 
 // UpdateQuestion updates an existing question
 func (s *QuestionService) UpdateQuestion(ctx context.Context, id string, input model.UpdateQuestionInput) (*model.Question, error) {
-	questionID, err := strconv.Atoi(id)
+	questionID, err := parseID("question ID", id)
 	if err != nil {
 		return nil, err
 	}
 
-	var qType, content, correctAnswer, difficulty *string
+	existing, err := s.Repo.FindByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		return nil, apperrors.NotFound("question %d not found", questionID)
+	}
+
+	if errs := validateUpdateQuestionInput(existing, input); len(errs) > 0 {
+		return nil, errs.Prefix("input")
+	}
+
+	var qType, content, contentFormat, correctAnswer, grading, difficulty *string
 	if input.Type != nil {
 		t := string(*input.Type)
 		qType = &t
 	}
+	if input.ContentFormat != nil {
+		f := string(*input.ContentFormat)
+		contentFormat = &f
+	}
+	if input.Grading != nil {
+		g := string(*input.Grading)
+		grading = &g
+	}
 	if input.Difficulty != nil {
 		d := string(*input.Difficulty)
 		difficulty = &d
@@ -61,7 +123,28 @@ func (s *QuestionService) UpdateQuestion(ctx context.Context, id string, input m
 	content = input.Content
 	correctAnswer = input.CorrectAnswer
 
-	err = s.Repo.Update(ctx, questionID, qType, content, input.Options, correctAnswer, input.Explanation, difficulty)
+	// Each part of the payload given replaces the stored one. A tolerance cannot be cleared
+	// on its own, so it goes when the question stops being NUMERIC.
+	dropNumeric := input.Type != nil && *input.Type != model.QuestionTypeNumeric && existing.Payload.Numeric != nil
+	var payload *models.Payload
+	if input.Pairs != nil || input.Numeric != nil || input.Blanks != nil || dropNumeric {
+		p := existing.Payload
+		if dropNumeric {
+			p.Numeric = nil
+		}
+		if input.Pairs != nil {
+			p.Pairs = matchingPairs(input.Pairs)
+		}
+		if input.Numeric != nil {
+			p.Numeric = numericAnswer(input.Numeric)
+		}
+		if input.Blanks != nil {
+			p.Blanks = clozeBlanks(input.Blanks)
+		}
+		payload = &p
+	}
+
+	err = s.Repo.Update(ctx, questionID, qType, content, contentFormat, input.Options, payload, correctAnswer, grading, input.Explanation, difficulty)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +169,7 @@ func (s *QuestionService) UpdateQuestion(ctx context.Context, id string, input m
 
 // DeleteQuestion deletes a question by ID
 func (s *QuestionService) DeleteQuestion(ctx context.Context, id string) (bool, error) {
-	questionID, err := strconv.Atoi(id)
+	questionID, err := parseID("question ID", id)
 	if err != nil {
 		return false, err
 	}
@@ -99,15 +182,243 @@ func (s *QuestionService) DeleteQuestion(ctx context.Context, id string) (bool,
 	return true, nil
 }
 
-// GetAllQuestions retrieves all questions, optionally filtered by quiz ID
-func (s *QuestionService) GetAllQuestions(ctx context.Context, quizID *string) ([]*model.Question, error) {
-	var qid *int
-	if quizID != nil {
-		id, _ := strconv.Atoi(*quizID)
-		qid = &id
+// AddQuestionToQuiz adds a question from the question bank to the end of a quiz
+func (s *QuestionService) AddQuestionToQuiz(ctx context.Context, quizID string, questionID string, points *int) (bool, error) {
+	qid, err := parseID("quiz ID", quizID)
+	if err != nil {
+		return false, err
+	}
+
+	id, err := parseID("question ID", questionID)
+	if err != nil {
+		return false, err
+	}
+
+	p := defaultQuestionPoints
+	if points != nil {
+		p = *points
 	}
 
-	dbQuestions, err := s.Repo.FindAll(ctx, qid)
+	err = s.Repo.AddToQuiz(ctx, qid, id, p)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RemoveQuestionFromQuiz removes a question from a quiz without deleting it from the question bank
+func (s *QuestionService) RemoveQuestionFromQuiz(ctx context.Context, quizID string, questionID string) (bool, error) {
+	qid, err := parseID("quiz ID", quizID)
+	if err != nil {
+		return false, err
+	}
+
+	id, err := parseID("question ID", questionID)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.Repo.RemoveFromQuiz(ctx, qid, id)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// BulkUpdateQuestions applies the same patch to several questions at once
+func (s *QuestionService) BulkUpdateQuestions(ctx context.Context, ids []string, patch model.BulkQuestionPatch) ([]*model.Question, error) {
+	questionIDs, err := parseBulkQuestionIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findQuestions(ctx, questionIDs); err != nil {
+		return nil, err
+	}
+
+	var difficulty *string
+	if patch.Difficulty != nil {
+		d := string(*patch.Difficulty)
+		difficulty = &d
+	}
+
+	err = s.Repo.BulkUpdate(ctx, questionIDs, patch.Explanation, difficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findQuestions(ctx, questionIDs)
+}
+
+// BulkDeleteQuestions deletes several questions at once and returns how many were deleted
+func (s *QuestionService) BulkDeleteQuestions(ctx context.Context, ids []string) (int, error) {
+	questionIDs, err := parseBulkQuestionIDs(ids)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.Repo.BulkDelete(ctx, questionIDs)
+}
+
+// BulkTagQuestions adds and removes tags on several questions at once
+func (s *QuestionService) BulkTagQuestions(ctx context.Context, ids []string, addTagIDs []string, removeTagIDs []string) ([]*model.Question, error) {
+	questionIDs, err := parseBulkQuestionIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	add, err := parseIDs("tag ID", addTagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	remove, err := parseIDs("tag ID", removeTagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, apperrors.InvalidArgument("at least one tag to add or remove is required")
+	}
+
+	removed := make(map[int]bool, len(remove))
+	for _, tagID := range remove {
+		removed[tagID] = true
+	}
+	for _, tagID := range add {
+		if removed[tagID] {
+			return nil, apperrors.InvalidArgument("tag %d cannot be both added and removed", tagID)
+		}
+	}
+
+	questions, err := s.findQuestions(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Repo.BulkTag(ctx, questionIDs, add, remove)
+	if err != nil {
+		return nil, err
+	}
+
+	return questions, nil
+}
+
+// MoveQuestions appends questions to the end of a quiz in the given order, taking them out of the source quiz
+// when one is given; without one, questions are added from the bank. It returns the questions of the quiz in quiz order.
+func (s *QuestionService) MoveQuestions(ctx context.Context, ids []string, sourceQuizID *string, targetQuizID string) ([]*model.Question, error) {
+	questionIDs, err := parseBulkQuestionIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var sourceID *int
+	if sourceQuizID != nil {
+		id, err := parseID("quiz ID", *sourceQuizID)
+		if err != nil {
+			return nil, err
+		}
+		sourceID = &id
+	}
+
+	quizID, err := parseID("quiz ID", targetQuizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findQuestions(ctx, questionIDs); err != nil {
+		return nil, err
+	}
+
+	err = s.Repo.MoveToQuiz(ctx, questionIDs, sourceID, quizID, defaultQuestionPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAllQuestions(ctx, &targetQuizID)
+}
+
+// ReorderQuestions sets the order of the questions of a quiz, which orderedIDs must list exactly once each.
+// It returns the questions of the quiz in their new order.
+func (s *QuestionService) ReorderQuestions(ctx context.Context, quizID string, orderedIDs []string) ([]*model.Question, error) {
+	qid, err := parseID("quiz ID", quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered, err := parseIDs("question ID", orderedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ordered) != len(orderedIDs) {
+		return nil, apperrors.InvalidArgument("each question must be listed once")
+	}
+
+	dbQuestions, err := s.Repo.FindAll(ctx, &qid)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*models.Question, len(dbQuestions))
+	for _, q := range dbQuestions {
+		byID[q.ID] = q
+	}
+
+	for _, id := range ordered {
+		if _, ok := byID[id]; !ok {
+			return nil, apperrors.InvalidArgument("question %d is not in quiz %d", id, qid)
+		}
+	}
+
+	if len(ordered) != len(dbQuestions) {
+		return nil, apperrors.InvalidArgument("all %d questions of quiz %d must be listed", len(dbQuestions), qid)
+	}
+
+	err = s.Repo.Reorder(ctx, qid, ordered)
+	if err != nil {
+		return nil, err
+	}
+
+	questions := make([]*model.Question, len(ordered))
+	for i, id := range ordered {
+		q := byID[id]
+		position := i
+		q.Position = &position
+		questions[i] = db.QuestionToGraphQL(q)
+	}
+
+	return questions, nil
+}
+
+// findQuestions retrieves the questions with the given IDs in ID order, failing if any is missing
+func (s *QuestionService) findQuestions(ctx context.Context, ids []int) ([]*model.Question, error) {
+	dbQuestions, err := s.Repo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]int, len(dbQuestions))
+	questions := make([]*model.Question, len(dbQuestions))
+	for i, dbQuestion := range dbQuestions {
+		found[i] = dbQuestion.ID
+		questions[i] = db.QuestionToGraphQL(dbQuestion)
+	}
+
+	for _, id := range ids {
+		if !slices.Contains(found, id) {
+			return nil, apperrors.NotFound("question %d not found", id)
+		}
+	}
+
+	return questions, nil
+}
+
+// GetAllQuestions retrieves all questions, optionally filtered by quiz ID
+func (s *QuestionService) GetAllQuestions(ctx context.Context, quizID *string) ([]*model.Question, error) {
+	dbQuestions, err := s.findAllQuestions(ctx, quizID)
 	if err != nil {
 		return nil, err
 	}
@@ -120,9 +431,23 @@ func (s *QuestionService) GetAllQuestions(ctx context.Context, quizID *string) (
 	return questions, nil
 }
 
+// findAllQuestions retrieves all questions, or those of a quiz when its ID is given
+func (s *QuestionService) findAllQuestions(ctx context.Context, quizID *string) ([]*models.Question, error) {
+	var qid *int
+	if quizID != nil {
+		id, err := parseID("quiz ID", *quizID)
+		if err != nil {
+			return nil, err
+		}
+		qid = &id
+	}
+
+	return s.Repo.FindAll(ctx, qid)
+}
+
 // GetQuestionByID retrieves a question by its ID
 func (s *QuestionService) GetQuestionByID(ctx context.Context, id string) (*model.Question, error) {
-	questionID, err := strconv.Atoi(id)
+	questionID, err := parseID("question ID", id)
 	if err != nil {
 		return nil, err
 	}
@@ -157,37 +482,55 @@ func (s *QuestionService) GetWrongQuestions(ctx context.Context) ([]*model.Quest
 // ImportQuestions imports questions from JSON data
 func (s *QuestionService) ImportQuestions(ctx context.Context, data string) ([]*model.Question, error) {
 	var questions []struct {
-		QuizID        string   `json:"quizId"`
-		Type          string   `json:"type"`
-		Content       string   `json:"content"`
-		Options       []string `json:"options"`
-		CorrectAnswer string   `json:"correctAnswer"`
-		Explanation   *string  `json:"explanation"`
-		Difficulty    string   `json:"difficulty"`
-		TagIDs        []string `json:"tagIds"`
+		QuizID        *string                    `json:"quizId"`
+		Type          string                     `json:"type"`
+		Content       string                     `json:"content"`
+		ContentFormat *string                    `json:"contentFormat"`
+		Options       []string                   `json:"options"`
+		Pairs         []*model.MatchingPairInput `json:"pairs"`
+		Numeric       *model.NumericInput        `json:"numeric"`
+		Blanks        []*model.ClozeBlankInput   `json:"blanks"`
+		CorrectAnswer string                     `json:"correctAnswer"`
+		Grading       *string                    `json:"grading"`
+		Explanation   *string                    `json:"explanation"`
+		Difficulty    string                     `json:"difficulty"`
+		TagIDs        []string                   `json:"tagIds"`
 	}
 
 	err := json.Unmarshal([]byte(data), &questions)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.InvalidArgument("invalid question data: %v", err)
 	}
 
-	var result []*model.Question
-	for _, q := range questions {
-		qType := model.QuestionType(q.Type)
-		qDifficulty := model.Difficulty(q.Difficulty)
-
-		input := model.CreateQuestionInput{
+	inputs := make([]model.CreateQuestionInput, len(questions))
+	var errs validation.Errors
+	for i, q := range questions {
+		inputs[i] = model.CreateQuestionInput{
 			QuizID:        q.QuizID,
-			Type:          qType,
+			Type:          model.QuestionType(q.Type),
 			Content:       q.Content,
+			ContentFormat: (*model.ContentFormat)(q.ContentFormat),
 			Options:       q.Options,
+			Pairs:         q.Pairs,
+			Numeric:       q.Numeric,
+			Blanks:        q.Blanks,
 			CorrectAnswer: q.CorrectAnswer,
+			Grading:       (*model.Grading)(q.Grading),
 			Explanation:   q.Explanation,
-			Difficulty:    qDifficulty,
+			Difficulty:    model.Difficulty(q.Difficulty),
 			TagIDs:        q.TagIDs,
 		}
 
+		errs = append(errs, validateCreateQuestionInput(inputs[i]).Prefix("data", i)...)
+	}
+
+	// Reject the whole import before anything is stored
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	var result []*model.Question
+	for _, input := range inputs {
 		question, err := s.CreateQuestion(ctx, input)
 		if err != nil {
 			return nil, err
@@ -198,13 +541,30 @@ func (s *QuestionService) ImportQuestions(ctx context.Context, data string) ([]*
 	return result, nil
 }
 
+// exportedQuestion is a question as exported, along with the pairs of a matching question
+// and the blanks of a cloze one that quiz takers are not shown, so that it can be imported back
+type exportedQuestion struct {
+	*model.Question
+	Pairs  []models.MatchingPair `json:"pairs,omitempty"`
+	Blanks []models.ClozeBlank   `json:"blanks,omitempty"`
+}
+
 // ExportQuestions exports questions to JSON
 func (s *QuestionService) ExportQuestions(ctx context.Context, quizID *string) (string, error) {
-	questions, err := s.GetAllQuestions(ctx, quizID)
+	dbQuestions, err := s.findAllQuestions(ctx, quizID)
 	if err != nil {
 		return "", err
 	}
 
+	questions := make([]exportedQuestion, len(dbQuestions))
+	for i, dbQuestion := range dbQuestions {
+		questions[i] = exportedQuestion{
+			Question: db.QuestionToGraphQL(dbQuestion),
+			Pairs:    dbQuestion.Payload.Pairs,
+			Blanks:   dbQuestion.Payload.Blanks,
+		}
+	}
+
 	data, err := json.Marshal(questions)
 	if err != nil {
 		return "", err
@@ -215,7 +575,7 @@ func (s *QuestionService) ExportQuestions(ctx context.Context, quizID *string) (
 
 // GetTagsByQuestionID retrieves all tags for a question
 func (s *QuestionService) GetTagsByQuestionID(ctx context.Context, questionID string) ([]*model.Tag, error) {
-	id, err := strconv.Atoi(questionID)
+	id, err := parseID("question ID", questionID)
 	if err != nil {
 		return nil, err
 	}
@@ -232,3 +592,150 @@ func (s *QuestionService) GetTagsByQuestionID(ctx context.Context, questionID st
 
 	return tags, nil
 }
+
+// defaultGrading is how new questions of a type are graded unless told otherwise:
+// matching and cloze questions earn credit per pair or blank, all others only when fully correct
+func defaultGrading(questionType model.QuestionType) model.Grading {
+	if questionType == model.QuestionTypeMatching || questionType == model.QuestionTypeCloze {
+		return model.GradingPartial
+	}
+	return model.GradingExact
+}
+
+// matchingPairs converts the pairs of a question input, keeping a missing list apart from an empty one
+func matchingPairs(pairs []*model.MatchingPairInput) []models.MatchingPair {
+	if pairs == nil {
+		return nil
+	}
+
+	result := make([]models.MatchingPair, len(pairs))
+	for i, pair := range pairs {
+		result[i] = models.MatchingPair{Prompt: pair.Prompt, Answer: pair.Answer}
+	}
+	return result
+}
+
+// numericAnswer converts the tolerance and units of a question input, absolute unless told otherwise
+func numericAnswer(input *model.NumericInput) *models.NumericAnswer {
+	if input == nil {
+		return nil
+	}
+
+	mode := model.ToleranceModeAbsolute
+	if input.ToleranceMode != nil {
+		mode = *input.ToleranceMode
+	}
+	return &models.NumericAnswer{Tolerance: input.Tolerance, ToleranceMode: string(mode), Units: input.Units}
+}
+
+// clozeBlanks converts the blanks of a question input, compared exactly unless told otherwise,
+// keeping a missing list apart from an empty one
+func clozeBlanks(blanks []*model.ClozeBlankInput) []models.ClozeBlank {
+	if blanks == nil {
+		return nil
+	}
+
+	result := make([]models.ClozeBlank, len(blanks))
+	for i, blank := range blanks {
+		strategy := model.BlankStrategyExact
+		if blank.Strategy != nil {
+			strategy = *blank.Strategy
+		}
+		result[i] = models.ClozeBlank{Accepted: blank.Accepted, Strategy: string(strategy)}
+	}
+	return result
+}
+
+// validateCreateQuestionInput checks a new question against the rules of its type
+func validateCreateQuestionInput(input model.CreateQuestionInput) validation.Errors {
+	q := validation.Question{
+		Type:          string(input.Type),
+		Content:       input.Content,
+		Options:       input.Options,
+		Pairs:         matchingPairs(input.Pairs),
+		Numeric:       numericAnswer(input.Numeric),
+		Blanks:        clozeBlanks(input.Blanks),
+		CorrectAnswer: input.CorrectAnswer,
+		Difficulty:    string(input.Difficulty),
+	}
+
+	if input.ContentFormat != nil {
+		q.ContentFormat = string(*input.ContentFormat)
+	}
+	if input.Grading != nil {
+		q.Grading = string(*input.Grading)
+	}
+
+	return validation.ValidateQuestion(q)
+}
+
+// validateUpdateQuestionInput checks the question that results from applying an update to an existing one
+func validateUpdateQuestionInput(existing *models.Question, input model.UpdateQuestionInput) validation.Errors {
+	q := validation.Question{
+		Type:          existing.Type,
+		Content:       existing.Content,
+		ContentFormat: existing.ContentFormat,
+		Options:       existing.Options,
+		Pairs:         existing.Payload.Pairs,
+		Numeric:       existing.Payload.Numeric,
+		Blanks:        existing.Payload.Blanks,
+		CorrectAnswer: existing.CorrectAnswer,
+		Grading:       existing.Grading,
+		Difficulty:    existing.Difficulty,
+	}
+
+	if input.Type != nil {
+		q.Type = string(*input.Type)
+		if *input.Type != model.QuestionTypeNumeric {
+			q.Numeric = nil
+		}
+	}
+	if input.Content != nil {
+		q.Content = *input.Content
+	}
+	if input.ContentFormat != nil {
+		q.ContentFormat = string(*input.ContentFormat)
+	}
+	if input.Options != nil {
+		q.Options = input.Options
+	}
+	if input.Pairs != nil {
+		q.Pairs = matchingPairs(input.Pairs)
+	}
+	if input.Numeric != nil {
+		q.Numeric = numericAnswer(input.Numeric)
+	}
+	if input.Blanks != nil {
+		q.Blanks = clozeBlanks(input.Blanks)
+	}
+	if input.CorrectAnswer != nil {
+		q.CorrectAnswer = *input.CorrectAnswer
+	}
+	if input.Grading != nil {
+		q.Grading = string(*input.Grading)
+	}
+	if input.Difficulty != nil {
+		q.Difficulty = string(*input.Difficulty)
+	}
+
+	return validation.ValidateQuestion(q)
+}
+
+// parseBulkQuestionIDs converts the question IDs of a bulk operation, which takes between one and maxBulkQuestions questions
+func parseBulkQuestionIDs(ids []string) ([]int, error) {
+	// Checked before parsing, so an oversized list is rejected without being read
+	if len(ids) > maxBulkQuestions {
+		return nil, apperrors.InvalidArgument("at most %d questions can be changed at once", maxBulkQuestions)
+	}
+
+	questionIDs, err := parseIDs("question ID", ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(questionIDs) == 0 {
+		return nil, apperrors.InvalidArgument("at least one question is required")
+	}
+
+	return questionIDs, nil
+}
@@ -0,0 +1,613 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"quiz-log/apperrors"
+	"quiz-log/graph/model"
+	"quiz-log/models"
+	"quiz-log/repository/memory"
+	mocks "quiz-log/repository/mocks"
+	"quiz-log/validation"
+)
+
+func TestQuestionService_CreateQuestion_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No repository calls are expected for invalid input
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	input := model.CreateQuestionInput{
+		Type:          model.QuestionTypeMultipleChoice,
+		Content:       "Question 1",
+		Options:       []string{"A", "B"},
+		CorrectAnswer: "C",
+		Difficulty:    model.DifficultyEasy,
+	}
+
+	// Execute
+	result, err := service.CreateQuestion(ctx, input)
+
+	// Assert
+	if result != nil {
+		t.Errorf("expected nil result, got %v", result)
+	}
+
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	if errs.Error() != "input.correctAnswer: correct answer must be one of the options" {
+		t.Errorf("unexpected error '%s'", errs.Error())
+	}
+}
+
+func TestQuestionService_CreateQuestion_InvalidLinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		quizID *string
+		tagIDs []string
+	}{
+		{name: "missing quiz", quizID: stringPtr("99"), tagIDs: []string{"1"}},
+		{name: "invalid quiz ID", quizID: stringPtr("abc")},
+		{name: "missing tag", quizID: stringPtr("1"), tagIDs: []string{"1", "99"}},
+		{name: "invalid tag ID", tagIDs: []string{"abc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newMemoryQuestionService(t, 0)
+			ctx := context.Background()
+
+			// Execute
+			_, err := service.CreateQuestion(ctx, model.CreateQuestionInput{
+				QuizID:        tt.quizID,
+				Type:          model.QuestionTypeShortAnswer,
+				Content:       "Capital of France?",
+				CorrectAnswer: "Paris",
+				Difficulty:    model.DifficultyEasy,
+				TagIDs:        tt.tagIDs,
+			})
+
+			// Assert
+			if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+				t.Errorf("expected code %s, got %s (%v)", apperrors.CodeInvalidArgument, code, err)
+			}
+
+			questions, err := service.Repo.FindAll(ctx, nil)
+			if err != nil {
+				t.Fatalf("failed to load questions: %v", err)
+			}
+			if len(questions) != 0 {
+				t.Errorf("expected no question to be created, got %d", len(questions))
+			}
+		})
+	}
+}
+
+func TestQuestionService_UpdateQuestion_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+
+	// Expect FindByID to be called
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(&models.Question{
+			ID:            1,
+			Type:          "MULTIPLE_CHOICE",
+			Content:       "Question 1",
+			Options:       []string{"A", "B"},
+			CorrectAnswer: "A",
+			Difficulty:    "EASY",
+		}, nil)
+
+	// Removing the option holding the correct answer makes the question invalid
+	input := model.UpdateQuestionInput{
+		Options: []string{"B", "C"},
+	}
+
+	// Execute
+	result, err := service.UpdateQuestion(ctx, "1", input)
+
+	// Assert
+	if result != nil {
+		t.Errorf("expected nil result, got %v", result)
+	}
+
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+}
+
+func TestQuestionService_UpdateQuestion_DropsTolerance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	numeric := &models.NumericAnswer{Tolerance: 0.5, ToleranceMode: "ABSOLUTE", Units: []string{"km"}}
+
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(&models.Question{ID: 1, Type: "NUMERIC", Content: "Q", Payload: models.Payload{Numeric: numeric}, CorrectAnswer: "10", Grading: "EXACT", Difficulty: "EASY"}, nil)
+
+	// Expect the tolerance to go along with the NUMERIC type
+	mockRepo.EXPECT().
+		Update(ctx, 1, stringPtr("SHORT_ANSWER"), nil, nil, nil, &models.Payload{}, stringPtr("ten"), nil, nil, nil).
+		Return(nil)
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(&models.Question{ID: 1, Type: "SHORT_ANSWER", Content: "Q", CorrectAnswer: "ten", Grading: "EXACT", Difficulty: "EASY"}, nil)
+
+	questionType := model.QuestionTypeShortAnswer
+	input := model.UpdateQuestionInput{
+		Type:          &questionType,
+		CorrectAnswer: stringPtr("ten"),
+	}
+
+	// Execute
+	result, err := service.UpdateQuestion(ctx, "1", input)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Numeric != nil {
+		t.Errorf("expected no tolerance, got %+v", result.Numeric)
+	}
+}
+
+func TestQuestionService_ImportQuestions_Numeric(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	data := `[{"type": "NUMERIC", "content": "Speed of light in km/s", "correctAnswer": "299,792", "numeric": {"tolerance": 0.01, "units": ["km/s"]}, "difficulty": "MEDIUM"}]`
+
+	// Expect the tolerance to be absolute unless told otherwise
+	numeric := &models.NumericAnswer{Tolerance: 0.01, ToleranceMode: "ABSOLUTE", Units: []string{"km/s"}}
+	mockRepo.EXPECT().
+		CreateWithLinks(ctx, "NUMERIC", "Speed of light in km/s", "PLAIN", nil, models.Payload{Numeric: numeric}, "299,792", "EXACT", nil, "MEDIUM", nil, nil, defaultQuestionPoints).
+		Return(7, nil)
+	mockRepo.EXPECT().
+		FindByID(ctx, 7).
+		Return(&models.Question{ID: 7, Type: "NUMERIC", Payload: models.Payload{Numeric: numeric}, CorrectAnswer: "299,792", Grading: "EXACT", Difficulty: "MEDIUM"}, nil)
+
+	// Execute
+	result, err := service.ImportQuestions(ctx, data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != 1 || result[0].Numeric == nil || result[0].Numeric.ToleranceMode != model.ToleranceModeAbsolute || !slices.Equal(result[0].Numeric.Units, []string{"km/s"}) {
+		t.Errorf("unexpected questions %v", result)
+	}
+}
+
+func TestQuestionService_ImportQuestions_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Nothing is created when any question is invalid
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	data := `[
+		{"type": "SHORT_ANSWER", "content": "Question 1", "correctAnswer": "A", "difficulty": "EASY"},
+		{"type": "TRUE_FALSE", "content": "Question 2", "correctAnswer": "maybe", "difficulty": "EASY"}
+	]`
+
+	// Execute
+	result, err := service.ImportQuestions(ctx, data)
+
+	// Assert
+	if result != nil {
+		t.Errorf("expected nil result, got %v", result)
+	}
+
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	if errs.Error() != "data[1].correctAnswer: correct answer must be true or false" {
+		t.Errorf("unexpected error '%s'", errs.Error())
+	}
+}
+
+func TestQuestionService_ImportQuestions_Ordering(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	data := `[{"type": "ORDERING", "content": "Order the steps of the TCP handshake", "options": ["ACK", "SYN", "SYN-ACK"], "correctAnswer": "1,2,0", "grading": "PARTIAL", "difficulty": "MEDIUM"}]`
+
+	// Expect the grading to be imported along with the order
+	mockRepo.EXPECT().
+		CreateWithLinks(ctx, "ORDERING", "Order the steps of the TCP handshake", "PLAIN", []string{"ACK", "SYN", "SYN-ACK"}, models.Payload{}, "1,2,0", "PARTIAL", nil, "MEDIUM", nil, nil, defaultQuestionPoints).
+		Return(4, nil)
+	mockRepo.EXPECT().
+		FindByID(ctx, 4).
+		Return(&models.Question{ID: 4, Type: "ORDERING", Options: []string{"ACK", "SYN", "SYN-ACK"}, CorrectAnswer: "1,2,0", Grading: "PARTIAL", Difficulty: "MEDIUM"}, nil)
+
+	// Execute
+	result, err := service.ImportQuestions(ctx, data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != 1 || result[0].Type != model.QuestionTypeOrdering || result[0].Grading != model.GradingPartial {
+		t.Errorf("unexpected questions %v", result)
+	}
+}
+
+func TestQuestionService_ImportQuestions_Matching(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	data := `[{"type": "MATCHING", "content": "Match the capitals", "pairs": [{"prompt": "France", "answer": "Paris"}, {"prompt": "Germany", "answer": "Berlin"}], "difficulty": "EASY"}]`
+	pairs := []models.MatchingPair{{Prompt: "France", Answer: "Paris"}, {Prompt: "Germany", Answer: "Berlin"}}
+
+	// Expect matching questions to earn credit per pair unless told otherwise
+	mockRepo.EXPECT().
+		CreateWithLinks(ctx, "MATCHING", "Match the capitals", "PLAIN", nil, models.Payload{Pairs: pairs}, "", "PARTIAL", nil, "EASY", nil, nil, defaultQuestionPoints).
+		Return(5, nil)
+	mockRepo.EXPECT().
+		FindByID(ctx, 5).
+		Return(&models.Question{ID: 5, Type: "MATCHING", Payload: models.Payload{Pairs: pairs}, Grading: "PARTIAL", Difficulty: "EASY"}, nil)
+
+	// Execute
+	result, err := service.ImportQuestions(ctx, data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != 1 || result[0].Matching == nil {
+		t.Fatalf("unexpected questions %v", result)
+	}
+	if !slices.Equal(result[0].Matching.Prompts, []string{"France", "Germany"}) || !slices.Equal(result[0].Matching.Answers, []string{"Berlin", "Paris"}) {
+		t.Errorf("expected the prompts in order and the answers sorted, got %+v", result[0].Matching)
+	}
+	if result[0].CorrectAnswer != "" {
+		t.Errorf("expected no correct answer, got %q", result[0].CorrectAnswer)
+	}
+}
+
+func TestQuestionService_ImportQuestions_Cloze(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	data := `[{"type": "CLOZE", "content": "{{1}} was finished in {{2}}", "blanks": [{"accepted": ["The Eiffel Tower"], "strategy": "IGNORE_CASE"}, {"accepted": ["1889"]}], "difficulty": "EASY"}]`
+	blanks := []models.ClozeBlank{{Accepted: []string{"The Eiffel Tower"}, Strategy: "IGNORE_CASE"}, {Accepted: []string{"1889"}, Strategy: "EXACT"}}
+
+	// Expect blanks to be compared exactly and to earn credit each unless told otherwise
+	mockRepo.EXPECT().
+		CreateWithLinks(ctx, "CLOZE", "{{1}} was finished in {{2}}", "PLAIN", nil, models.Payload{Blanks: blanks}, "", "PARTIAL", nil, "EASY", nil, nil, defaultQuestionPoints).
+		Return(8, nil)
+	mockRepo.EXPECT().
+		FindByID(ctx, 8).
+		Return(&models.Question{ID: 8, Type: "CLOZE", Content: "{{1}} was finished in {{2}}", Payload: models.Payload{Blanks: blanks}, Grading: "PARTIAL", Difficulty: "EASY"}, nil)
+
+	// Execute
+	result, err := service.ImportQuestions(ctx, data)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != 1 || result[0].Cloze == nil || result[0].Cloze.Blanks != 2 {
+		t.Errorf("expected a question with 2 blanks, got %v", result)
+	}
+}
+
+func TestQuestionService_ExportQuestions_Matching(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuestionRepository(ctrl)
+	service := &QuestionService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	pairs := []models.MatchingPair{{Prompt: "France", Answer: "Paris"}, {Prompt: "Germany", Answer: "Berlin"}}
+
+	mockRepo.EXPECT().
+		FindAll(ctx, nil).
+		Return([]*models.Question{{ID: 5, Type: "MATCHING", Content: "Match the capitals", ContentFormat: "PLAIN", Payload: models.Payload{Pairs: pairs}, Grading: "PARTIAL", Difficulty: "EASY"}}, nil)
+
+	// Execute
+	data, err := service.ExportQuestions(ctx, nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The pairs are exported so that the question imports back the same
+	mockRepo.EXPECT().
+		CreateWithLinks(ctx, "MATCHING", "Match the capitals", "PLAIN", nil, models.Payload{Pairs: pairs}, "", "PARTIAL", nil, "EASY", nil, nil, defaultQuestionPoints).
+		Return(6, nil)
+	mockRepo.EXPECT().
+		FindByID(ctx, 6).
+		Return(&models.Question{ID: 6}, nil)
+
+	if _, err := service.ImportQuestions(ctx, data); err != nil {
+		t.Errorf("expected the export to import back, got %v for %s", err, data)
+	}
+}
+
+// newMemoryQuestionService creates a QuestionService on an in-memory store holding quiz 1
+// with the given number of questions, and the tags europe and asia as tags 1 and 2
+func newMemoryQuestionService(t *testing.T, questions int) *QuestionService {
+	t.Helper()
+
+	store := memory.NewStore()
+	quizRepo := memory.NewQuizRepository(store)
+	tagRepo := memory.NewTagRepository(store)
+	service := &QuestionService{
+		Repo: memory.NewQuestionRepository(store),
+	}
+	ctx := context.Background()
+
+	quizID, err := quizRepo.Create(ctx, "Capitals", nil)
+	if err != nil {
+		t.Fatalf("failed to create quiz: %v", err)
+	}
+
+	for i := range questions {
+		questionID, err := service.Repo.Create(ctx, "SHORT_ANSWER", fmt.Sprintf("Capital %d?", i+1), "PLAIN", nil, models.Payload{}, "Paris", "EXACT", nil, "EASY")
+		if err != nil {
+			t.Fatalf("failed to create question: %v", err)
+		}
+		if err := service.Repo.AddToQuiz(ctx, quizID, questionID, defaultQuestionPoints); err != nil {
+			t.Fatalf("failed to add question: %v", err)
+		}
+	}
+
+	for _, name := range []string{"europe", "asia"} {
+		if _, err := tagRepo.Create(ctx, name); err != nil {
+			t.Fatalf("failed to create tag: %v", err)
+		}
+	}
+
+	return service
+}
+
+// questionTagNames returns the names of the tags of a stored question
+func questionTagNames(t *testing.T, service *QuestionService, questionID int) []string {
+	t.Helper()
+
+	tags, err := service.Repo.FindTagsByQuestionID(context.Background(), questionID)
+	if err != nil {
+		t.Fatalf("failed to load tags: %v", err)
+	}
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func TestQuestionService_BulkUpdateQuestions(t *testing.T) {
+	service := newMemoryQuestionService(t, 3)
+	hard := model.DifficultyHard
+
+	// Execute: repeated IDs are updated once
+	questions, err := service.BulkUpdateQuestions(context.Background(), []string{"2", "1", "2"}, model.BulkQuestionPatch{Difficulty: &hard})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(questions) != 2 || questions[0].Difficulty != hard || questions[1].Difficulty != hard {
+		t.Errorf("expected 2 hard questions, got %v", questions)
+	}
+
+	// The question left out keeps its difficulty
+	untouched, err := service.Repo.FindByID(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if untouched.Difficulty != "EASY" {
+		t.Errorf("expected question 3 to stay EASY, got %s", untouched.Difficulty)
+	}
+}
+
+func TestQuestionService_BulkUpdateQuestions_NotFound(t *testing.T) {
+	service := newMemoryQuestionService(t, 1)
+
+	// Execute
+	_, err := service.BulkUpdateQuestions(context.Background(), []string{"1", "9"}, model.BulkQuestionPatch{Explanation: stringPtr("Capitals")})
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeNotFound {
+		t.Errorf("expected code %s, got %s (%v)", apperrors.CodeNotFound, code, err)
+	}
+
+	// Nothing is updated when any question is missing
+	question, err := service.Repo.FindByID(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if question.Explanation != nil {
+		t.Errorf("expected no explanation, got %q", *question.Explanation)
+	}
+}
+
+func TestQuestionService_BulkTagQuestions(t *testing.T) {
+	service := newMemoryQuestionService(t, 2)
+	ctx := context.Background()
+
+	if _, err := service.BulkTagQuestions(ctx, []string{"1", "2"}, []string{"2"}, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Execute
+	_, err := service.BulkTagQuestions(ctx, []string{"1", "2"}, []string{"1"}, []string{"2"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, id := range []int{1, 2} {
+		if names := questionTagNames(t, service, id); !slices.Equal(names, []string{"europe"}) {
+			t.Errorf("expected question %d tagged [europe], got %v", id, names)
+		}
+	}
+}
+
+func TestQuestionService_BulkTagQuestions_Invalid(t *testing.T) {
+	tests := []struct {
+		name         string
+		ids          []string
+		addTagIDs    []string
+		removeTagIDs []string
+	}{
+		{name: "no questions", ids: nil, addTagIDs: []string{"1"}},
+		{name: "invalid question ID", ids: []string{"abc"}, addTagIDs: []string{"1"}},
+		{name: "no tags", ids: []string{"1"}},
+		{name: "added and removed", ids: []string{"1"}, addTagIDs: []string{"1", "2"}, removeTagIDs: []string{"2"}},
+		{name: "too many questions", ids: slices.Repeat([]string{"1"}, maxBulkQuestions+1), addTagIDs: []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newMemoryQuestionService(t, 1)
+
+			// Execute
+			_, err := service.BulkTagQuestions(context.Background(), tt.ids, tt.addTagIDs, tt.removeTagIDs)
+
+			// Assert
+			if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+				t.Errorf("expected code %s, got %s (%v)", apperrors.CodeInvalidArgument, code, err)
+			}
+
+			if names := questionTagNames(t, service, 1); len(names) != 0 {
+				t.Errorf("expected no tags, got %v", names)
+			}
+		})
+	}
+}
+
+func TestQuestionService_ReorderQuestions(t *testing.T) {
+	service := newMemoryQuestionService(t, 3)
+	ctx := context.Background()
+
+	// Execute
+	questions, err := service.ReorderQuestions(ctx, "1", []string{"3", "1", "2"})
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []string
+	for i, q := range questions {
+		ids = append(ids, q.ID)
+		if q.Position == nil || *q.Position != i {
+			t.Errorf("expected question %s at position %d, got %v", q.ID, i, q.Position)
+		}
+	}
+	if !slices.Equal(ids, []string{"3", "1", "2"}) {
+		t.Errorf("expected questions [3 1 2], got %v", ids)
+	}
+
+	// The new order is stored
+	stored, err := service.GetAllQuestions(ctx, stringPtr("1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids = nil
+	for _, q := range stored {
+		ids = append(ids, q.ID)
+	}
+	if !slices.Equal(ids, []string{"3", "1", "2"}) {
+		t.Errorf("expected stored order [3 1 2], got %v", ids)
+	}
+}
+
+func TestQuestionService_ReorderQuestions_Invalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		orderedIDs []string
+	}{
+		{name: "repeated question", orderedIDs: []string{"1", "2", "1"}},
+		{name: "question of another quiz", orderedIDs: []string{"1", "2", "9"}},
+		{name: "missing question", orderedIDs: []string{"2", "1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newMemoryQuestionService(t, 3)
+
+			// Execute
+			_, err := service.ReorderQuestions(context.Background(), "1", tt.orderedIDs)
+
+			// Assert
+			if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+				t.Errorf("expected code %s, got %s (%v)", apperrors.CodeInvalidArgument, code, err)
+			}
+		})
+	}
+}
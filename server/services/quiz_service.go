@@ -3,12 +3,16 @@ package services
 import (
 	"context"
 	"strconv"
+	"strings"
 
 	"github.com/uptrace/bun"
 
+	"quiz-log/apperrors"
 	"quiz-log/db"
 	"quiz-log/graph/model"
+	"quiz-log/models"
 	"quiz-log/repository"
+	"quiz-log/validation"
 )
 
 type QuizService struct {
@@ -30,6 +34,13 @@ func (s *QuizService) CreateQuiz(ctx context.Context, input model.CreateQuizInpu
 		return nil, err
 	}
 
+	if input.IsTemplate != nil && *input.IsTemplate {
+		err = s.Repo.SetTemplate(ctx, quizID, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Associate tags
 	if len(input.TagIDs) > 0 {
 		err = s.Repo.AssociateTags(ctx, quizID, input.TagIDs)
@@ -43,7 +54,7 @@ func (s *QuizService) CreateQuiz(ctx context.Context, input model.CreateQuizInpu
 
 // UpdateQuiz updates an existing quiz
 func (s *QuizService) UpdateQuiz(ctx context.Context, id string, input model.UpdateQuizInput) (*model.Quiz, error) {
-	quizID, err := strconv.Atoi(id)
+	quizID, err := parseID("quiz ID", id)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +64,13 @@ func (s *QuizService) UpdateQuiz(ctx context.Context, id string, input model.Upd
 		return nil, err
 	}
 
+	if input.IsTemplate != nil {
+		err = s.Repo.SetTemplate(ctx, quizID, *input.IsTemplate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Update tags
 	if input.TagIDs != nil {
 		// Delete existing tags
@@ -75,7 +93,7 @@ func (s *QuizService) UpdateQuiz(ctx context.Context, id string, input model.Upd
 
 // DeleteQuiz deletes a quiz by ID
 func (s *QuizService) DeleteQuiz(ctx context.Context, id string) (bool, error) {
-	quizID, err := strconv.Atoi(id)
+	quizID, err := parseID("quiz ID", id)
 	if err != nil {
 		return false, err
 	}
@@ -88,9 +106,14 @@ func (s *QuizService) DeleteQuiz(ctx context.Context, id string) (bool, error) {
 	return true, nil
 }
 
-// GetAllQuizzes retrieves all quizzes
-func (s *QuizService) GetAllQuizzes(ctx context.Context) ([]*model.Quiz, error) {
-	dbQuizzes, err := s.Repo.FindAll(ctx)
+// GetAllQuizzes retrieves all quizzes with the given status, published ones by default
+func (s *QuizService) GetAllQuizzes(ctx context.Context, status *model.QuizStatus) ([]*model.Quiz, error) {
+	quizStatus := model.QuizStatusPublished
+	if status != nil {
+		quizStatus = *status
+	}
+
+	dbQuizzes, err := s.Repo.FindAll(ctx, string(quizStatus))
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +128,7 @@ func (s *QuizService) GetAllQuizzes(ctx context.Context) ([]*model.Quiz, error)
 
 // GetQuizByID retrieves a quiz by its ID
 func (s *QuizService) GetQuizByID(ctx context.Context, id string) (*model.Quiz, error) {
-	quizID, err := strconv.Atoi(id)
+	quizID, err := parseID("quiz ID", id)
 	if err != nil {
 		return nil, err
 	}
@@ -116,15 +139,182 @@ func (s *QuizService) GetQuizByID(ctx context.Context, id string) (*model.Quiz,
 	}
 
 	if dbQuiz == nil {
-		return nil, nil
+		return nil, apperrors.NotFound("quiz %d not found", quizID)
 	}
 
 	return db.QuizToGraphQL(dbQuiz), nil
 }
 
+// PublishQuiz validates a quiz and makes it available for attempts
+func (s *QuizService) PublishQuiz(ctx context.Context, id string) (*model.Quiz, error) {
+	quizID, err := parseID("quiz ID", id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbQuiz, err := s.Repo.FindByID(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbQuiz == nil {
+		return nil, apperrors.NotFound("quiz %d not found", quizID)
+	}
+
+	dbQuestions, err := s.Repo.FindQuestionsByQuizID(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := validateQuizForPublish(dbQuestions); len(errs) > 0 {
+		return nil, errs
+	}
+
+	err = s.Repo.UpdateStatus(ctx, quizID, string(model.QuizStatusPublished))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetQuizByID(ctx, id)
+}
+
+// ArchiveQuiz retires a quiz so it can no longer be attempted, keeping its attempts
+func (s *QuizService) ArchiveQuiz(ctx context.Context, id string) (*model.Quiz, error) {
+	quizID, err := parseID("quiz ID", id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Repo.UpdateStatus(ctx, quizID, string(model.QuizStatusArchived))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetQuizByID(ctx, id)
+}
+
+// validateQuizForPublish lists everything that prevents a quiz from being published,
+// with the paths of invalid questions nested under questions[i]
+func validateQuizForPublish(questions []*models.Question) validation.Errors {
+	if len(questions) == 0 {
+		return validation.Errors{{Path: []any{"questions"}, Message: "quiz has no questions"}}
+	}
+
+	var errs validation.Errors
+	for i, q := range questions {
+		questionErrs := validation.ValidateQuestion(validation.Question{
+			Type:          q.Type,
+			Content:       q.Content,
+			Options:       q.Options,
+			Pairs:         q.Payload.Pairs,
+			Numeric:       q.Payload.Numeric,
+			Blanks:        q.Payload.Blanks,
+			CorrectAnswer: q.CorrectAnswer,
+			Grading:       q.Grading,
+			Difficulty:    q.Difficulty,
+		})
+
+		errs = append(errs, questionErrs.Prefix("questions", i)...)
+	}
+
+	return errs
+}
+
+// GetQuizTemplates retrieves all quizzes marked as templates
+func (s *QuizService) GetQuizTemplates(ctx context.Context) ([]*model.Quiz, error) {
+	dbQuizzes, err := s.Repo.FindTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var quizzes []*model.Quiz
+	for _, dbQuiz := range dbQuizzes {
+		quizzes = append(quizzes, db.QuizToGraphQL(dbQuiz))
+	}
+
+	return quizzes, nil
+}
+
+// DuplicateQuiz deep-copies a quiz with its questions, optionally keeping its tags
+func (s *QuizService) DuplicateQuiz(ctx context.Context, id string, title *string, includeTags *bool) (*model.Quiz, error) {
+	quizID, err := parseID("quiz ID", id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbQuiz, err := s.Repo.FindByID(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbQuiz == nil {
+		return nil, apperrors.NotFound("quiz %d not found", quizID)
+	}
+
+	newTitle := dbQuiz.Title + " (Copy)"
+	if title != nil {
+		newTitle = *title
+	}
+
+	withTags := includeTags == nil || *includeTags
+
+	newQuizID, err := s.Repo.Duplicate(ctx, quizID, newTitle, withTags, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetQuizByID(ctx, strconv.Itoa(newQuizID))
+}
+
+// InstantiateTemplate creates a quiz from a template, replacing {{key}} placeholders with the given values
+func (s *QuizService) InstantiateTemplate(ctx context.Context, id string, title *string, placeholders []*model.PlaceholderInput) (*model.Quiz, error) {
+	quizID, err := parseID("quiz ID", id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbQuiz, err := s.Repo.FindByID(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbQuiz == nil {
+		return nil, apperrors.NotFound("quiz %d not found", quizID)
+	}
+
+	if !dbQuiz.IsTemplate {
+		return nil, apperrors.InvalidArgument("quiz %d is not a template", quizID)
+	}
+
+	replace := placeholderReplacer(placeholders)
+
+	newTitle := replace(dbQuiz.Title)
+	if title != nil {
+		newTitle = *title
+	}
+
+	newQuizID, err := s.Repo.Duplicate(ctx, quizID, newTitle, true, replace)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetQuizByID(ctx, strconv.Itoa(newQuizID))
+}
+
+// placeholderReplacer builds a function replacing every {{key}} with its value
+func placeholderReplacer(placeholders []*model.PlaceholderInput) func(string) string {
+	var oldnew []string
+	for _, p := range placeholders {
+		oldnew = append(oldnew, "{{"+strings.TrimSpace(p.Key)+"}}", p.Value)
+	}
+
+	replacer := strings.NewReplacer(oldnew...)
+	return replacer.Replace
+}
+
 // GetQuestionsByQuizID retrieves all questions for a quiz
 func (s *QuizService) GetQuestionsByQuizID(ctx context.Context, quizID string) ([]*model.Question, error) {
-	id, err := strconv.Atoi(quizID)
+	id, err := parseID("quiz ID", quizID)
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +334,7 @@ func (s *QuizService) GetQuestionsByQuizID(ctx context.Context, quizID string) (
 
 // GetTagsByQuizID retrieves all tags for a quiz
 func (s *QuizService) GetTagsByQuizID(ctx context.Context, quizID string) ([]*model.Tag, error) {
-	id, err := strconv.Atoi(quizID)
+	id, err := parseID("quiz ID", quizID)
 	if err != nil {
 		return nil, err
 	}
@@ -2,14 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
 	"quiz-log/models"
+	"strings"
 	"testing"
 	"time"
 
 	"go.uber.org/mock/gomock"
 
+	"quiz-log/apperrors"
 	"quiz-log/graph/model"
 	mocks "quiz-log/repository/mocks"
+	"quiz-log/validation"
 )
 
 func TestQuizService_CreateQuiz(t *testing.T) {
@@ -199,11 +203,11 @@ func TestQuizService_GetAllQuizzes(t *testing.T) {
 
 	// Expect FindAll to be called
 	mockRepo.EXPECT().
-		FindAll(ctx).
+		FindAll(ctx, "PUBLISHED").
 		Return(expectedQuizzes, nil)
 
 	// Execute
-	result, err := service.GetAllQuizzes(ctx)
+	result, err := service.GetAllQuizzes(ctx, nil)
 
 	// Assert
 	if err != nil {
@@ -263,6 +267,54 @@ func TestQuizService_GetQuizByID(t *testing.T) {
 	}
 }
 
+func TestQuizService_GetQuizByID_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuizRepository(ctrl)
+	service := &QuizService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+
+	// Expect FindByID to be called
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(nil, nil)
+
+	// Execute
+	result, err := service.GetQuizByID(ctx, "1")
+
+	// Assert
+	if result != nil {
+		t.Errorf("expected nil result, got %v", result)
+	}
+
+	if code := apperrors.As(err).Code; code != apperrors.CodeNotFound {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeNotFound, code)
+	}
+}
+
+func TestQuizService_GetQuizByID_InvalidID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// No repository calls are expected for a malformed ID
+	mockRepo := mocks.NewMockQuizRepository(ctrl)
+	service := &QuizService{
+		Repo: mockRepo,
+	}
+
+	// Execute
+	_, err := service.GetQuizByID(context.Background(), "abc")
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeInvalidArgument, code)
+	}
+}
+
 func TestQuizService_GetQuestionsByQuizID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -280,7 +332,7 @@ func TestQuizService_GetQuestionsByQuizID(t *testing.T) {
 	expectedQuestions := []*models.Question{
 		{
 			ID:            1,
-			QuizID:        1,
+			QuizID:        intPtr(1),
 			Type:          "MULTIPLE_CHOICE",
 			Content:       "Question 1",
 			Options:       []string{"A", "B", "C"},
@@ -352,6 +404,249 @@ func TestQuizService_GetTagsByQuizID(t *testing.T) {
 	}
 }
 
+func TestQuizService_PublishQuiz(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuizRepository(ctrl)
+	service := &QuizService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(&models.Quiz{ID: 1, Title: "Test Quiz", Status: "DRAFT", CreatedAt: now, UpdatedAt: now}, nil)
+
+	mockRepo.EXPECT().
+		FindQuestionsByQuizID(ctx, 1).
+		Return([]*models.Question{
+			{ID: 1, Type: "MULTIPLE_CHOICE", Content: "Question 1", Options: []string{"A", "B"}, CorrectAnswer: "A", Difficulty: "EASY"},
+			{ID: 2, Type: "TRUE_FALSE", Content: "Question 2", CorrectAnswer: "false", Difficulty: "EASY"},
+		}, nil)
+
+	// Expect UpdateStatus to be called
+	mockRepo.EXPECT().
+		UpdateStatus(ctx, 1, "PUBLISHED").
+		Return(nil)
+
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(&models.Quiz{ID: 1, Title: "Test Quiz", Status: "PUBLISHED", CreatedAt: now, UpdatedAt: now}, nil)
+
+	// Execute
+	result, err := service.PublishQuiz(ctx, "1")
+
+	// Assert
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if result == nil {
+		t.Fatal("expected result, got nil")
+	}
+
+	if result.Status != model.QuizStatusPublished {
+		t.Errorf("expected status PUBLISHED, got %s", result.Status)
+	}
+}
+
+func TestQuizService_PublishQuiz_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		questions []*models.Question
+		wantField string
+	}{
+		{
+			name:      "no questions",
+			questions: []*models.Question{},
+			wantField: "questions",
+		},
+		{
+			name: "correct answer not among options",
+			questions: []*models.Question{
+				{ID: 1, Type: "MULTIPLE_CHOICE", Content: "Question 1", Options: []string{"A", "B"}, CorrectAnswer: "C", Difficulty: "EASY"},
+			},
+			wantField: "questions[0].correctAnswer",
+		},
+		{
+			name: "true/false answer",
+			questions: []*models.Question{
+				{ID: 1, Type: "TRUE_FALSE", Content: "Question 1", CorrectAnswer: "false", Difficulty: "EASY"},
+				{ID: 2, Type: "TRUE_FALSE", Content: "Question 2", CorrectAnswer: "maybe", Difficulty: "EASY"},
+			},
+			wantField: "questions[1].correctAnswer",
+		},
+		{
+			name: "empty content",
+			questions: []*models.Question{
+				{ID: 1, Type: "SHORT_ANSWER", Content: " ", CorrectAnswer: "A", Difficulty: "EASY"},
+			},
+			wantField: "questions[0].content",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mocks.NewMockQuizRepository(ctrl)
+			service := &QuizService{
+				Repo: mockRepo,
+			}
+
+			ctx := context.Background()
+
+			mockRepo.EXPECT().
+				FindByID(ctx, 1).
+				Return(&models.Quiz{ID: 1, Title: "Test Quiz", Status: "DRAFT"}, nil)
+
+			mockRepo.EXPECT().
+				FindQuestionsByQuizID(ctx, 1).
+				Return(tt.questions, nil)
+
+			// Execute
+			result, err := service.PublishQuiz(ctx, "1")
+
+			// Assert
+			var errs validation.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected validation errors, got %v", err)
+			}
+
+			if !strings.HasPrefix(errs.Error(), tt.wantField+":") {
+				t.Errorf("expected an error on %s, got '%v'", tt.wantField, errs)
+			}
+
+			if result != nil {
+				t.Errorf("expected nil result, got %v", result)
+			}
+		})
+	}
+}
+
+func TestQuizService_DuplicateQuiz(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuizRepository(ctrl)
+	service := &QuizService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(&models.Quiz{ID: 1, Title: "Go Basics", CreatedAt: now, UpdatedAt: now}, nil)
+
+	// Expect the default copy title and tags to be kept
+	mockRepo.EXPECT().
+		Duplicate(ctx, 1, "Go Basics (Copy)", true, gomock.Nil()).
+		Return(2, nil)
+
+	mockRepo.EXPECT().
+		FindByID(ctx, 2).
+		Return(&models.Quiz{ID: 2, Title: "Go Basics (Copy)", CreatedAt: now, UpdatedAt: now}, nil)
+
+	// Execute
+	result, err := service.DuplicateQuiz(ctx, "1", nil, nil)
+
+	// Assert
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if result == nil {
+		t.Fatal("expected result, got nil")
+	}
+
+	if result.ID != "2" {
+		t.Errorf("expected ID '2', got '%s'", result.ID)
+	}
+}
+
+func TestQuizService_InstantiateTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuizRepository(ctrl)
+	service := &QuizService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+	now := time.Now()
+	placeholders := []*model.PlaceholderInput{
+		{Key: "language", Value: "Go"},
+	}
+
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(&models.Quiz{ID: 1, Title: "{{language}} Basics", IsTemplate: true, CreatedAt: now, UpdatedAt: now}, nil)
+
+	mockRepo.EXPECT().
+		Duplicate(ctx, 1, "Go Basics", true, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ int, _ string, _ bool, replace func(string) string) (int, error) {
+			if got := replace("What is a {{language}} channel?"); got != "What is a Go channel?" {
+				t.Errorf("expected placeholder to be replaced, got '%s'", got)
+			}
+			return 2, nil
+		})
+
+	mockRepo.EXPECT().
+		FindByID(ctx, 2).
+		Return(&models.Quiz{ID: 2, Title: "Go Basics", CreatedAt: now, UpdatedAt: now}, nil)
+
+	// Execute
+	result, err := service.InstantiateTemplate(ctx, "1", nil, placeholders)
+
+	// Assert
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if result == nil {
+		t.Fatal("expected result, got nil")
+	}
+
+	if result.Title != "Go Basics" {
+		t.Errorf("expected title 'Go Basics', got '%s'", result.Title)
+	}
+}
+
+func TestQuizService_InstantiateTemplate_NotTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockQuizRepository(ctrl)
+	service := &QuizService{
+		Repo: mockRepo,
+	}
+
+	ctx := context.Background()
+
+	mockRepo.EXPECT().
+		FindByID(ctx, 1).
+		Return(&models.Quiz{ID: 1, Title: "Go Basics"}, nil)
+
+	// Execute
+	result, err := service.InstantiateTemplate(ctx, "1", nil, nil)
+
+	// Assert
+	if err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if result != nil {
+		t.Errorf("expected nil result, got %v", result)
+	}
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,661 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"quiz-log/apperrors"
+	"quiz-log/db"
+	"quiz-log/grading"
+	"quiz-log/graph/model"
+	"quiz-log/models"
+	"quiz-log/pubsub"
+	"quiz-log/repository"
+)
+
+const (
+	// roomCodeAlphabet leaves out characters that are easily confused when read aloud
+	roomCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	roomCodeLength   = 6
+
+	minQuestionSeconds = 5
+	maxQuestionSeconds = 300
+	maxNameLength      = 32
+
+	// maxRoomPoints is awarded for an instant correct answer, falling to half at the deadline
+	maxRoomPoints = 1000
+
+	// finishedRoomRetention is how long a finished room stays visible for its final leaderboard
+	finishedRoomRetention = time.Hour
+
+	// roomIdleTimeout is how long a room that is not finished lives without a join, answer or host action
+	roomIdleTimeout = 30 * time.Minute
+
+	// maxOpenRooms caps the rooms held in memory, finished ones included until they expire
+	maxOpenRooms = 1000
+)
+
+type roomAnswer struct {
+	answer  string
+	correct bool
+	credit  float64
+	blanks  models.Blanks
+}
+
+type roomParticipant struct {
+	id           string
+	name         string
+	token        string
+	score        int
+	correctCount int
+	answers      map[int]*roomAnswer
+	saved        bool
+}
+
+type room struct {
+	mu              sync.Mutex
+	code            string
+	hostToken       string
+	quizID          int
+	questions       []*models.Question
+	questionSeconds int
+	status          model.RoomStatus
+	questionIndex   int
+	deadline        time.Time
+	startedAt       time.Time
+	expiresAt       time.Time
+	participants    []*roomParticipant
+
+	// ending is set once the host ends a started room; it stops taking answers
+	// and becomes FINISHED once the attempts of all participants are stored
+	ending bool
+	saveMu sync.Mutex
+}
+
+// RoomService runs live quiz rooms where participants answer the same question at the same time.
+// Rooms live in memory until they expire; once a room finishes, each participant's answers are stored as an attempt.
+type RoomService struct {
+	QuizRepo       repository.QuizRepository
+	AttemptService *AttemptService
+	Bus            pubsub.Bus
+
+	now   func() time.Time
+	mu    sync.RWMutex
+	rooms map[string]*room
+}
+
+func NewRoomService(quizRepo repository.QuizRepository, attemptService *AttemptService, bus pubsub.Bus) *RoomService {
+	return &RoomService{
+		QuizRepo:       quizRepo,
+		AttemptService: attemptService,
+		Bus:            bus,
+		now:            time.Now,
+		rooms:          make(map[string]*room),
+	}
+}
+
+// CreateRoom opens a room for a published quiz and returns the token its host controls it with
+func (s *RoomService) CreateRoom(ctx context.Context, quizID string, questionSeconds *int) (*model.CreateRoomPayload, error) {
+	id, err := parseID("quiz ID", quizID)
+	if err != nil {
+		return nil, err
+	}
+
+	seconds := 20
+	if questionSeconds != nil {
+		seconds = *questionSeconds
+	}
+
+	if seconds < minQuestionSeconds || seconds > maxQuestionSeconds {
+		return nil, apperrors.InvalidArgument("questionSeconds must be between %d and %d", minQuestionSeconds, maxQuestionSeconds)
+	}
+
+	err = s.AttemptService.ensureQuizPublished(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.QuizRepo.FindQuestionsByQuizID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(questions) == 0 {
+		return nil, apperrors.Conflict("quiz %d has no questions", id)
+	}
+
+	now := s.now()
+	r := &room{
+		hostToken:       newToken(),
+		quizID:          id,
+		questions:       questions,
+		questionSeconds: seconds,
+		status:          model.RoomStatusLobby,
+		expiresAt:       now.Add(roomIdleTimeout),
+	}
+
+	s.mu.Lock()
+	s.removeExpired(now)
+	if len(s.rooms) >= maxOpenRooms {
+		s.mu.Unlock()
+		return nil, apperrors.Conflict("too many open rooms, try again later")
+	}
+	for r.code == "" || s.rooms[r.code] != nil {
+		r.code = newRoomCode()
+	}
+	s.rooms[r.code] = r
+	s.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &model.CreateRoomPayload{
+		Room:      r.snapshot(),
+		HostToken: r.hostToken,
+	}, nil
+}
+
+// JoinRoom adds a participant to a room that has not started yet
+func (s *RoomService) JoinRoom(ctx context.Context, code string, name string) (*model.JoinRoomPayload, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, apperrors.InvalidArgument("name must be between 1 and %d characters", maxNameLength)
+	}
+
+	r, err := s.findRoom(code)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := r.join(name, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishRoomUpdated(ctx, r.code)
+
+	return payload, nil
+}
+
+// StartRoom shows the first question to every participant
+func (s *RoomService) StartRoom(ctx context.Context, code string, hostToken string) (*model.Room, error) {
+	return s.updateAsHost(ctx, code, hostToken, func(r *room) error {
+		if r.status != model.RoomStatusLobby {
+			return apperrors.Conflict("room %s has already started", r.code)
+		}
+
+		if len(r.participants) == 0 {
+			return apperrors.Conflict("room %s has no participants", r.code)
+		}
+
+		if len(r.questions) == 0 {
+			return apperrors.Conflict("room %s has no questions", r.code)
+		}
+
+		r.status = model.RoomStatusInProgress
+		r.startedAt = s.now()
+		r.showQuestion(0, r.startedAt)
+		return nil
+	})
+}
+
+// NextRoomQuestion moves every participant to the next question, finishing the room after the last one
+func (s *RoomService) NextRoomQuestion(ctx context.Context, code string, hostToken string) (*model.Room, error) {
+	return s.updateAsHost(ctx, code, hostToken, func(r *room) error {
+		if r.status != model.RoomStatusInProgress {
+			return apperrors.Conflict("room %s is not in progress", r.code)
+		}
+
+		if r.questionIndex+1 < len(r.questions) && !r.ending {
+			r.showQuestion(r.questionIndex+1, s.now())
+			return nil
+		}
+
+		r.ending = true
+		return nil
+	})
+}
+
+// CloseRoom ends a room early, storing the answers given so far
+func (s *RoomService) CloseRoom(ctx context.Context, code string, hostToken string) (*model.Room, error) {
+	return s.updateAsHost(ctx, code, hostToken, func(r *room) error {
+		if r.status == model.RoomStatusFinished {
+			return apperrors.Conflict("room %s is already finished", r.code)
+		}
+
+		// A room closed in the lobby has no answers to store
+		if r.status == model.RoomStatusLobby {
+			r.markFinished(s.now())
+			return nil
+		}
+
+		r.ending = true
+		return nil
+	})
+}
+
+// SubmitRoomAnswer scores a participant's answer to the current question on correctness and speed
+func (s *RoomService) SubmitRoomAnswer(ctx context.Context, code string, participantToken string, answer string) (*model.RoomAnswerResult, error) {
+	r, err := s.findRoom(code)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.answer(participantToken, answer, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishRoomUpdated(ctx, r.code)
+
+	return result, nil
+}
+
+// GetRoom retrieves the current state of a room
+func (s *RoomService) GetRoom(ctx context.Context, code string) (*model.Room, error) {
+	r, err := s.findRoom(code)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.currentSnapshot(), nil
+}
+
+// SubscribeRoom streams the state of a room, then its new state on every change until ctx is done
+func (s *RoomService) SubscribeRoom(ctx context.Context, code string) (<-chan *model.Room, error) {
+	r, err := s.findRoom(code)
+	if err != nil {
+		return nil, err
+	}
+
+	updates, err := s.Bus.Subscribe(ctx, roomUpdatedTopic(r.code))
+	if err != nil {
+		return nil, err
+	}
+
+	rooms := make(chan *model.Room)
+	go func() {
+		defer close(rooms)
+
+		for {
+			select {
+			case rooms <- r.currentSnapshot():
+			case <-ctx.Done():
+				return
+			}
+
+			// Wait for the next change of this room
+			if _, ok := <-updates; !ok {
+				return
+			}
+		}
+	}()
+
+	return rooms, nil
+}
+
+// updateAsHost applies a host action to a room and announces its new state
+func (s *RoomService) updateAsHost(ctx context.Context, code string, hostToken string, update func(r *room) error) (*model.Room, error) {
+	r, err := s.findRoom(code)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, ending, err := r.apply(hostToken, s.now(), update)
+	if err != nil {
+		return nil, err
+	}
+
+	if ending {
+		snapshot, err = s.finish(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.publishRoomUpdated(ctx, r.code)
+
+	return snapshot, nil
+}
+
+// finish stores the answers of every participant as an attempt of the quiz, then marks the room FINISHED.
+// Attempts are stored without holding r.mu; if one fails, the room stays in progress so the host can end it
+// again, and the participants already stored are skipped.
+func (s *RoomService) finish(ctx context.Context, r *room) (*model.Room, error) {
+	r.saveMu.Lock()
+	defer r.saveMu.Unlock()
+
+	completedAt := s.now()
+	for _, p := range r.pendingParticipants() {
+		err := s.saveAttempt(ctx, r, &p, completedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		r.markSaved(p.id)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status != model.RoomStatusFinished {
+		r.markFinished(s.now())
+	}
+
+	return r.snapshot(), nil
+}
+
+// saveAttempt stores a participant's answers as a scored attempt in one transaction
+func (s *RoomService) saveAttempt(ctx context.Context, r *room, p *roomParticipant, completedAt time.Time) error {
+	totalQuestions := len(r.questions)
+	totalPoints := 0
+	earnedPoints := 0.0
+
+	var answers []*models.Answer
+	for i, q := range r.questions {
+		totalPoints += questionPoints(q)
+
+		a := p.answers[i]
+		if a == nil {
+			continue
+		}
+		earnedPoints += a.credit * float64(questionPoints(q))
+
+		questionID := q.ID
+		answers = append(answers, &models.Answer{
+			QuestionID: &questionID,
+			UserAnswer: a.answer,
+			IsCorrect:  a.correct,
+			Credit:     a.credit,
+			Blanks:     a.blanks,
+		})
+	}
+
+	attemptID, err := s.AttemptService.Repo.CreateWithAnswers(ctx, r.quizID, r.startedAt, completedAt, attemptScore(earnedPoints, totalPoints), totalQuestions, answers)
+	if err != nil {
+		return err
+	}
+
+	s.AttemptService.publishAttemptSubmitted(ctx, attemptID, r.quizID)
+
+	return nil
+}
+
+func (s *RoomService) findRoom(code string) (*room, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r := s.rooms[strings.ToUpper(strings.TrimSpace(code))]
+	if r == nil || r.expired(s.now()) {
+		return nil, apperrors.NotFound("room %s not found", code)
+	}
+
+	return r, nil
+}
+
+// removeExpired forgets the rooms that expired; the caller must hold s.mu for writing
+func (s *RoomService) removeExpired(now time.Time) {
+	for code, r := range s.rooms {
+		if r.expired(now) {
+			delete(s.rooms, code)
+		}
+	}
+}
+
+// publishRoomUpdated notifies the subscribers of a room that its state changed
+func (s *RoomService) publishRoomUpdated(ctx context.Context, code string) {
+	if err := s.Bus.Publish(ctx, roomUpdatedTopic(code), nil); err != nil {
+		slog.ErrorContext(ctx, "failed to publish room update", "room", code, "error", err)
+	}
+}
+
+// join adds a participant while the room is still in its lobby
+func (r *room) join(name string, now time.Time) (*model.JoinRoomPayload, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status != model.RoomStatusLobby {
+		return nil, apperrors.Conflict("room %s has already started", r.code)
+	}
+
+	for _, p := range r.participants {
+		if strings.EqualFold(p.name, name) {
+			return nil, apperrors.Conflict("name %q is already taken", name)
+		}
+	}
+
+	participant := &roomParticipant{
+		id:      strconv.Itoa(len(r.participants) + 1),
+		name:    name,
+		token:   newToken(),
+		answers: make(map[int]*roomAnswer),
+	}
+	r.participants = append(r.participants, participant)
+	r.touch(now)
+
+	return &model.JoinRoomPayload{
+		Room:             r.snapshot(),
+		Participant:      participant.toGraphQL(),
+		ParticipantToken: participant.token,
+	}, nil
+}
+
+// answer grades a participant's answer to the current question and adds its points
+func (r *room) answer(participantToken string, answer string, now time.Time) (*model.RoomAnswerResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	participant := r.participantByToken(participantToken)
+	if participant == nil {
+		return nil, apperrors.PermissionDenied("not a participant of room %s", r.code)
+	}
+
+	if r.status != model.RoomStatusInProgress || r.ending {
+		return nil, apperrors.Conflict("room %s is not in progress", r.code)
+	}
+
+	remaining := r.deadline.Sub(now)
+	if remaining < 0 {
+		return nil, apperrors.Conflict("time is up for this question")
+	}
+
+	if participant.answers[r.questionIndex] != nil {
+		return nil, apperrors.Conflict("question already answered")
+	}
+
+	credit := grading.Grade(r.questions[r.questionIndex], answer)
+	blanks := grading.GradeBlanks(r.questions[r.questionIndex], answer)
+	correct := credit == 1
+	points := 0
+	if credit > 0 {
+		points = int(credit * float64(roomPoints(remaining, time.Duration(r.questionSeconds)*time.Second)))
+		participant.score += points
+	}
+	if correct {
+		participant.correctCount++
+	}
+
+	participant.answers[r.questionIndex] = &roomAnswer{answer: answer, correct: correct, credit: credit, blanks: blanks}
+	r.touch(now)
+
+	return &model.RoomAnswerResult{
+		Correct: correct,
+		Points:  points,
+	}, nil
+}
+
+// apply runs a host action on the room and returns its new state, and whether the room is ending
+func (r *room) apply(hostToken string, now time.Time, update func(r *room) error) (*model.Room, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if hostToken != r.hostToken {
+		return nil, false, apperrors.PermissionDenied("not the host of room %s", r.code)
+	}
+
+	err := update(r)
+	if err != nil {
+		return nil, false, err
+	}
+	r.touch(now)
+
+	return r.snapshot(), r.ending && r.status != model.RoomStatusFinished, nil
+}
+
+// pendingParticipants copies the participants whose attempt is not stored yet.
+// Their answers no longer change once the room is ending.
+func (r *room) pendingParticipants() []roomParticipant {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending []roomParticipant
+	for _, p := range r.participants {
+		if !p.saved {
+			pending = append(pending, *p)
+		}
+	}
+	return pending
+}
+
+// markSaved records that a participant's attempt is stored
+func (r *room) markSaved(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, p := range r.participants {
+		if p.id == id {
+			p.saved = true
+		}
+	}
+}
+
+// currentSnapshot converts the room into its GraphQL model while holding r.mu
+func (r *room) currentSnapshot() *model.Room {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.snapshot()
+}
+
+// markFinished finishes a room, keeping its final leaderboard visible for finishedRoomRetention;
+// the caller must hold r.mu
+func (r *room) markFinished(now time.Time) {
+	r.status = model.RoomStatusFinished
+	r.deadline = time.Time{}
+	r.expiresAt = now.Add(finishedRoomRetention)
+}
+
+// touch extends the life of a room that is not finished yet; the caller must hold r.mu
+func (r *room) touch(now time.Time) {
+	if r.status != model.RoomStatusFinished {
+		r.expiresAt = now.Add(roomIdleTimeout)
+	}
+}
+
+// expired reports whether the room is past its expiry
+func (r *room) expired(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return now.After(r.expiresAt)
+}
+
+func (r *room) showQuestion(index int, now time.Time) {
+	r.questionIndex = index
+	r.deadline = now.Add(time.Duration(r.questionSeconds) * time.Second)
+}
+
+func (r *room) participantByToken(token string) *roomParticipant {
+	for _, p := range r.participants {
+		if p.token == token {
+			return p
+		}
+	}
+	return nil
+}
+
+// snapshot converts the room into its GraphQL model; the caller must hold r.mu
+func (r *room) snapshot() *model.Room {
+	result := &model.Room{
+		Code:            r.code,
+		QuizID:          strconv.Itoa(r.quizID),
+		Status:          r.status,
+		QuestionSeconds: r.questionSeconds,
+		QuestionIndex:   r.questionIndex,
+		QuestionCount:   len(r.questions),
+		Leaderboard:     []*model.RoomParticipant{},
+	}
+
+	if r.status == model.RoomStatusInProgress {
+		q := db.QuestionToGraphQL(r.questions[r.questionIndex])
+		result.CurrentQuestion = &model.RoomQuestion{
+			ID:       q.ID,
+			Type:     q.Type,
+			Content:  q.Content,
+			Options:  q.Options,
+			Matching: q.Matching,
+			Numeric:  q.Numeric,
+			Cloze:    q.Cloze,
+		}
+
+		deadline := r.deadline
+		result.Deadline = &deadline
+
+		for _, p := range r.participants {
+			if p.answers[r.questionIndex] != nil {
+				result.AnsweredCount++
+			}
+		}
+	}
+
+	for _, p := range r.participants {
+		result.Leaderboard = append(result.Leaderboard, p.toGraphQL())
+	}
+
+	slices.SortStableFunc(result.Leaderboard, func(a, b *model.RoomParticipant) int {
+		return b.Score - a.Score
+	})
+
+	return result
+}
+
+func (p *roomParticipant) toGraphQL() *model.RoomParticipant {
+	return &model.RoomParticipant{
+		ID:           p.id,
+		Name:         p.name,
+		Score:        p.score,
+		CorrectCount: p.correctCount,
+	}
+}
+
+// roomPoints scores a correct answer, or its share of one earned with partial credit, given with the remaining share of the question's time
+func roomPoints(remaining, total time.Duration) int {
+	if total <= 0 {
+		return maxRoomPoints
+	}
+	return maxRoomPoints/2 + int(int64(maxRoomPoints/2)*int64(remaining)/int64(total))
+}
+
+func newRoomCode() string {
+	b := make([]byte, roomCodeLength)
+	_, _ = rand.Read(b)
+
+	code := make([]byte, roomCodeLength)
+	for i := range b {
+		code[i] = roomCodeAlphabet[int(b[i])%len(roomCodeAlphabet)]
+	}
+	return string(code)
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,508 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"quiz-log/apperrors"
+	"quiz-log/graph/model"
+	"quiz-log/models"
+	"quiz-log/pubsub"
+	mocks "quiz-log/repository/mocks"
+)
+
+// newTestRoomService creates a RoomService with mocked repositories and a clock the test controls
+func newTestRoomService(ctrl *gomock.Controller, now *time.Time) (*RoomService, *mocks.MockQuizRepository, *mocks.MockAttemptRepository) {
+	mockQuizRepo := mocks.NewMockQuizRepository(ctrl)
+	mockAttemptRepo := mocks.NewMockAttemptRepository(ctrl)
+	bus := pubsub.NewMemoryBus()
+
+	service := &RoomService{
+		QuizRepo: mockQuizRepo,
+		AttemptService: &AttemptService{
+			Repo:     mockAttemptRepo,
+			QuizRepo: mockQuizRepo,
+			Bus:      bus,
+		},
+		Bus:   bus,
+		now:   func() time.Time { return *now },
+		rooms: make(map[string]*room),
+	}
+
+	return service, mockQuizRepo, mockAttemptRepo
+}
+
+func TestRoomService_Game(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockQuizRepo, mockAttemptRepo := newTestRoomService(ctrl, &now)
+	ctx := context.Background()
+
+	// Expect the quiz to be checked and its questions loaded
+	mockAttemptRepo.EXPECT().
+		FindQuizStatus(ctx, 1).
+		Return("PUBLISHED", nil)
+
+	mockQuizRepo.EXPECT().
+		FindQuestionsByQuizID(ctx, 1).
+		Return([]*models.Question{
+			{ID: 10, Type: "SHORT_ANSWER", Content: "Capital of France?", CorrectAnswer: "Paris", Difficulty: "EASY", Points: intPtr(3)},
+			{ID: 11, Type: "SHORT_ANSWER", Content: "Capital of Japan?", CorrectAnswer: "Tokyo", Difficulty: "EASY", Points: intPtr(1)},
+		}, nil)
+
+	// Execute
+	created, err := service.CreateRoom(ctx, "1", intPtr(10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	code := created.Room.Code
+	if len(code) != roomCodeLength {
+		t.Errorf("expected code of length %d, got '%s'", roomCodeLength, code)
+	}
+
+	alice, err := service.JoinRoom(ctx, code, "Alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bob, err := service.JoinRoom(ctx, code, "Bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = service.StartRoom(ctx, code, created.HostToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Alice answers instantly, Bob halfway through the countdown
+	aliceResult, err := service.SubmitRoomAnswer(ctx, code, alice.ParticipantToken, "Paris")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now = now.Add(5 * time.Second)
+	bobResult, err := service.SubmitRoomAnswer(ctx, code, bob.ParticipantToken, "Paris")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	if aliceResult.Points != 1000 {
+		t.Errorf("expected 1000 points, got %d", aliceResult.Points)
+	}
+
+	if bobResult.Points != 750 {
+		t.Errorf("expected 750 points, got %d", bobResult.Points)
+	}
+
+	room, err := service.NextRoomQuestion(ctx, code, created.HostToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if room.CurrentQuestion == nil || room.CurrentQuestion.ID != "11" {
+		t.Fatalf("expected question '11', got %v", room.CurrentQuestion)
+	}
+
+	_, err = service.SubmitRoomAnswer(ctx, code, bob.ParticipantToken, "Kyoto")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Expect each participant's answers to be stored as a scored attempt when the room finishes,
+	// the first question earning 3 of the 4 points
+	mockAttemptRepo.EXPECT().
+		CreateWithAnswers(ctx, 1, gomock.Any(), now, 75, 2, []*models.Answer{
+			{QuestionID: intPtr(10), UserAnswer: "Paris", IsCorrect: true, Credit: 1},
+		}).
+		Return(100, nil)
+
+	mockAttemptRepo.EXPECT().
+		CreateWithAnswers(ctx, 1, gomock.Any(), now, 75, 2, []*models.Answer{
+			{QuestionID: intPtr(10), UserAnswer: "Paris", IsCorrect: true, Credit: 1},
+			{QuestionID: intPtr(11), UserAnswer: "Kyoto"},
+		}).
+		Return(101, nil)
+
+	room, err = service.NextRoomQuestion(ctx, code, created.HostToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if room.Status != model.RoomStatusFinished {
+		t.Errorf("expected status FINISHED, got %s", room.Status)
+	}
+
+	if room.Leaderboard[0].Name != "Alice" || room.Leaderboard[0].Score != 1000 {
+		t.Errorf("expected Alice to lead with 1000 points, got %s with %d", room.Leaderboard[0].Name, room.Leaderboard[0].Score)
+	}
+}
+
+func TestRoomService_SubmitRoomAnswer_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, _, _ := newTestRoomService(ctrl, &now)
+	ctx := context.Background()
+
+	r := &room{
+		code:      "ABCDEF",
+		hostToken: "host",
+		quizID:    1,
+		questions: []*models.Question{
+			{ID: 10, Type: "SHORT_ANSWER", Content: "Capital of France?", CorrectAnswer: "Paris"},
+		},
+		questionSeconds: 10,
+		status:          model.RoomStatusInProgress,
+		deadline:        now.Add(10 * time.Second),
+		expiresAt:       now.Add(roomIdleTimeout),
+		participants: []*roomParticipant{
+			{id: "1", name: "Alice", token: "alice", answers: map[int]*roomAnswer{}},
+		},
+	}
+	service.rooms[r.code] = r
+
+	// A second answer to the same question is rejected
+	_, err := service.SubmitRoomAnswer(ctx, "abcdef", "alice", "Paris")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = service.SubmitRoomAnswer(ctx, "ABCDEF", "alice", "Paris")
+	if code := apperrors.As(err).Code; code != apperrors.CodeConflict {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeConflict, code)
+	}
+
+	// Unknown participants are rejected
+	_, err = service.SubmitRoomAnswer(ctx, "ABCDEF", "mallory", "Paris")
+	if code := apperrors.As(err).Code; code != apperrors.CodePermissionDenied {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodePermissionDenied, code)
+	}
+
+	// Answers after the deadline are rejected
+	delete(r.participants[0].answers, 0)
+	now = now.Add(11 * time.Second)
+
+	_, err = service.SubmitRoomAnswer(ctx, "ABCDEF", "alice", "Paris")
+	if code := apperrors.As(err).Code; code != apperrors.CodeConflict {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeConflict, code)
+	}
+
+	// Only the host advances questions
+	_, err = service.NextRoomQuestion(ctx, "ABCDEF", "alice")
+	if code := apperrors.As(err).Code; code != apperrors.CodePermissionDenied {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodePermissionDenied, code)
+	}
+}
+
+func TestRoomService_Matching(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, _, _ := newTestRoomService(ctrl, &now)
+	ctx := context.Background()
+
+	service.rooms["ABCDEF"] = &room{
+		code:      "ABCDEF",
+		hostToken: "host",
+		quizID:    1,
+		questions: []*models.Question{
+			{ID: 10, Type: "MATCHING", Content: "Match the capitals", Grading: "PARTIAL", Payload: models.Payload{Pairs: []models.MatchingPair{
+				{Prompt: "France", Answer: "Paris"},
+				{Prompt: "Italy", Answer: "Rome"},
+				{Prompt: "Germany", Answer: "Berlin"},
+			}}},
+		},
+		questionSeconds: 10,
+		status:          model.RoomStatusInProgress,
+		deadline:        now.Add(10 * time.Second),
+		expiresAt:       now.Add(roomIdleTimeout),
+		participants: []*roomParticipant{
+			{id: "1", name: "Alice", token: "alice", answers: map[int]*roomAnswer{}},
+		},
+	}
+
+	// Execute
+	room, err := service.GetRoom(ctx, "ABCDEF")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	matching := room.CurrentQuestion.Matching
+	if matching == nil {
+		t.Fatal("expected matching prompts and answers, got nil")
+	}
+
+	if !slices.Equal(matching.Prompts, []string{"France", "Italy", "Germany"}) {
+		t.Errorf("expected prompts in order, got %v", matching.Prompts)
+	}
+
+	if !slices.Equal(matching.Answers, []string{"Berlin", "Paris", "Rome"}) {
+		t.Errorf("expected sorted answers, got %v", matching.Answers)
+	}
+
+	// Matching only France earns a third of the points
+	result, err := service.SubmitRoomAnswer(ctx, "ABCDEF", "alice", "1,0,2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Correct || result.Points != 333 {
+		t.Errorf("expected 333 points without a correct answer, got %d and %t", result.Points, result.Correct)
+	}
+}
+
+func TestRoomService_CloseRoom_Retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, _, mockAttemptRepo := newTestRoomService(ctrl, &now)
+	ctx := context.Background()
+
+	service.rooms["ABCDEF"] = &room{
+		code:      "ABCDEF",
+		hostToken: "host",
+		quizID:    1,
+		questions: []*models.Question{
+			{ID: 10, Type: "SHORT_ANSWER", Content: "Capital of France?", CorrectAnswer: "Paris"},
+		},
+		questionSeconds: 10,
+		status:          model.RoomStatusInProgress,
+		deadline:        now.Add(10 * time.Second),
+		expiresAt:       now.Add(roomIdleTimeout),
+		participants: []*roomParticipant{
+			{id: "1", name: "Alice", token: "alice", answers: map[int]*roomAnswer{}},
+			{id: "2", name: "Bob", token: "bob", answers: map[int]*roomAnswer{}},
+		},
+	}
+
+	// Expect Alice's attempt to be stored and Bob's to fail
+	gomock.InOrder(
+		mockAttemptRepo.EXPECT().
+			CreateWithAnswers(ctx, 1, gomock.Any(), now, 0, 1, nil).
+			Return(100, nil),
+		mockAttemptRepo.EXPECT().
+			CreateWithAnswers(ctx, 1, gomock.Any(), now, 0, 1, nil).
+			Return(0, errors.New("connection lost")),
+	)
+
+	// Execute
+	_, err := service.CloseRoom(ctx, "ABCDEF", "host")
+
+	// Assert
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	room, err := service.GetRoom(ctx, "ABCDEF")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if room.Status != model.RoomStatusInProgress {
+		t.Errorf("expected status IN_PROGRESS, got %s", room.Status)
+	}
+
+	// The room no longer takes answers
+	_, err = service.SubmitRoomAnswer(ctx, "ABCDEF", "alice", "Paris")
+	if code := apperrors.As(err).Code; code != apperrors.CodeConflict {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeConflict, code)
+	}
+
+	// Expect only Bob's attempt to be stored on retry
+	mockAttemptRepo.EXPECT().
+		CreateWithAnswers(ctx, 1, gomock.Any(), now, 0, 1, nil).
+		Return(101, nil)
+
+	room, err = service.CloseRoom(ctx, "ABCDEF", "host")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if room.Status != model.RoomStatusFinished {
+		t.Errorf("expected status FINISHED, got %s", room.Status)
+	}
+}
+
+func TestRoomService_NoQuestions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockQuizRepo, mockAttemptRepo := newTestRoomService(ctrl, &now)
+	ctx := context.Background()
+
+	// Expect the quiz to be checked and no questions to be found
+	mockAttemptRepo.EXPECT().
+		FindQuizStatus(ctx, 1).
+		Return("PUBLISHED", nil)
+
+	mockQuizRepo.EXPECT().
+		FindQuestionsByQuizID(ctx, 1).
+		Return([]*models.Question{}, nil)
+
+	// Execute
+	_, err := service.CreateRoom(ctx, "1", nil)
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeConflict {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeConflict, code)
+	}
+
+	// A room without questions cannot be started either
+	service.rooms["ABCDEF"] = &room{
+		code:      "ABCDEF",
+		hostToken: "host",
+		status:    model.RoomStatusLobby,
+		expiresAt: now.Add(roomIdleTimeout),
+		participants: []*roomParticipant{
+			{id: "1", name: "Alice", token: "alice", answers: map[int]*roomAnswer{}},
+		},
+	}
+
+	_, err = service.StartRoom(ctx, "ABCDEF", "host")
+	if code := apperrors.As(err).Code; code != apperrors.CodeConflict {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeConflict, code)
+	}
+}
+
+func TestRoomService_Expiry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, mockQuizRepo, mockAttemptRepo := newTestRoomService(ctrl, &now)
+	ctx := context.Background()
+
+	mockAttemptRepo.EXPECT().
+		FindQuizStatus(ctx, 1).
+		Return("PUBLISHED", nil).
+		AnyTimes()
+
+	mockQuizRepo.EXPECT().
+		FindQuestionsByQuizID(ctx, 1).
+		Return([]*models.Question{{ID: 10, Type: "SHORT_ANSWER", Content: "Capital of France?", CorrectAnswer: "Paris"}}, nil).
+		AnyTimes()
+
+	created, err := service.CreateRoom(ctx, "1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	code := created.Room.Code
+
+	// A join keeps the room alive
+	now = now.Add(roomIdleTimeout - time.Minute)
+	if _, err := service.JoinRoom(ctx, code, "Alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now = now.Add(roomIdleTimeout - time.Minute)
+	if _, err := service.GetRoom(ctx, code); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Execute: the room is left idle
+	now = now.Add(2 * time.Minute)
+	_, err = service.GetRoom(ctx, code)
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeNotFound {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeNotFound, code)
+	}
+
+	// No room can be opened while the cap is reached, until rooms expire
+	for i := range maxOpenRooms {
+		code := fmt.Sprintf("R%05d", i)
+		service.rooms[code] = &room{code: code, status: model.RoomStatusLobby, expiresAt: now.Add(roomIdleTimeout)}
+	}
+
+	_, err = service.CreateRoom(ctx, "1", nil)
+	if code := apperrors.As(err).Code; code != apperrors.CodeConflict {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeConflict, code)
+	}
+
+	now = now.Add(roomIdleTimeout + time.Second)
+	if _, err := service.CreateRoom(ctx, "1", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(service.rooms) != 1 {
+		t.Errorf("expected the expired rooms to be removed, got %d rooms", len(service.rooms))
+	}
+}
+
+func TestRoomService_SubscribeRoom(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	service, _, _ := newTestRoomService(ctrl, &now)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, code := range []string{"ABCDEF", "GHJKLM"} {
+		service.rooms[code] = &room{
+			code:      code,
+			hostToken: "host",
+			status:    model.RoomStatusLobby,
+			expiresAt: now.Add(roomIdleTimeout),
+		}
+	}
+
+	events, err := service.Bus.Subscribe(ctx, roomUpdatedTopic("ABCDEF"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Execute
+	rooms, err := service.SubscribeRoom(ctx, "ABCDEF")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Assert
+	initial := <-rooms
+	if len(initial.Leaderboard) != 0 {
+		t.Errorf("expected empty leaderboard, got %d participants", len(initial.Leaderboard))
+	}
+
+	// Another room publishes on its own topic
+	_, err = service.JoinRoom(ctx, "GHJKLM", "Bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-events:
+		t.Error("expected no update of room ABCDEF for a change of room GHJKLM")
+	default:
+	}
+
+	_, err = service.JoinRoom(ctx, "ABCDEF", "Alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case updated := <-rooms:
+		if len(updated.Leaderboard) != 1 {
+			t.Errorf("expected 1 participant, got %d", len(updated.Leaderboard))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected room update, got none")
+	}
+}
@@ -2,10 +2,12 @@ package services
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/uptrace/bun"
 
 	"quiz-log/graph/model"
+	"quiz-log/pubsub"
 	"quiz-log/repository"
 )
 
@@ -13,13 +15,15 @@ type StatisticsService struct {
 	DB             *bun.DB
 	Repo           repository.StatisticsRepository
 	AttemptService *AttemptService
+	Bus            pubsub.Bus
 }
 
-func NewStatisticsService(database *bun.DB, attemptService *AttemptService) *StatisticsService {
+func NewStatisticsService(database *bun.DB, attemptService *AttemptService, bus pubsub.Bus) *StatisticsService {
 	return &StatisticsService{
 		DB:             database,
 		Repo:           repository.NewStatisticsRepository(database),
 		AttemptService: attemptService,
+		Bus:            bus,
 	}
 }
 
@@ -39,18 +43,6 @@ func (s *StatisticsService) GetStatistics(ctx context.Context) (*model.Statistic
 		stats.AverageScore = avgScore
 	}
 
-	// Category stats
-	categoryStats, err := s.Repo.GetCategoryStats(ctx)
-	if err == nil {
-		for _, stat := range categoryStats {
-			stats.CategoryStats = append(stats.CategoryStats, &model.CategoryStat{
-				TagName:        stat.TagName,
-				CorrectRate:    stat.CorrectRate,
-				TotalQuestions: stat.TotalQuestions,
-			})
-		}
-	}
-
 	// Recent attempts
 	stats.RecentAttempts, _ = s.AttemptService.GetAttempts(ctx, nil)
 	if len(stats.RecentAttempts) > 10 {
@@ -59,3 +51,54 @@ func (s *StatisticsService) GetStatistics(ctx context.Context) (*model.Statistic
 
 	return stats, nil
 }
+
+// GetCategoryStats retrieves statistics per tag, counting the answers of child tags for their ancestors with rollUp
+func (s *StatisticsService) GetCategoryStats(ctx context.Context, rollUp bool) ([]*model.CategoryStat, error) {
+	categoryStats, err := s.Repo.GetCategoryStats(ctx, rollUp)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := []*model.CategoryStat{}
+	for _, stat := range categoryStats {
+		stats = append(stats, &model.CategoryStat{
+			TagName:        stat.TagName,
+			CorrectRate:    stat.CorrectRate,
+			TotalQuestions: stat.TotalQuestions,
+		})
+	}
+
+	return stats, nil
+}
+
+// SubscribeStatistics streams the current statistics, then fresh ones on every update until ctx is done
+func (s *StatisticsService) SubscribeStatistics(ctx context.Context) (<-chan *model.Statistics, error) {
+	updates, err := s.Bus.Subscribe(ctx, TopicStatisticsUpdated)
+	if err != nil {
+		return nil, err
+	}
+
+	statistics := make(chan *model.Statistics)
+	go func() {
+		defer close(statistics)
+
+		for {
+			stats, err := s.GetStatistics(ctx)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to load statistics", "error", err)
+			} else {
+				select {
+				case statistics <- stats:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if _, ok := <-updates; !ok {
+				return
+			}
+		}
+	}()
+
+	return statistics, nil
+}
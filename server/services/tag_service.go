@@ -3,37 +3,76 @@ package services
 import (
 	"context"
 	"quiz-log/db"
-	"strconv"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/uptrace/bun"
 
+	"quiz-log/apperrors"
 	"quiz-log/graph/model"
+	"quiz-log/models"
 	"quiz-log/repository"
 )
 
+// maxTagNameLength matches the VARCHAR(100) of tags.name
+const maxTagNameLength = 100
+
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 50
+	// similarQuestionLimit caps the questions compared when suggesting tags for a question
+	similarQuestionLimit = 100
+	// maxKeywords caps the words of a question searched for similar questions
+	maxKeywords = 10
+)
+
 type TagService struct {
-	DB   *bun.DB
-	Repo repository.TagRepository
+	DB           *bun.DB
+	Repo         repository.TagRepository
+	QuestionRepo repository.QuestionRepository
 }
 
 func NewTagService(database *bun.DB) *TagService {
 	return &TagService{
-		DB:   database,
-		Repo: repository.NewTagRepository(database),
+		DB:           database,
+		Repo:         repository.NewTagRepository(database),
+		QuestionRepo: repository.NewQuestionRepository(database),
 	}
 }
 
-// CreateTag creates a new tag or returns existing one
-func (s *TagService) CreateTag(ctx context.Context, name string) (*model.Tag, error) {
-	tagID, err := s.Repo.Create(ctx, name)
+// CreateTag creates a new tag or returns existing one. Under a parentID the tag must be new,
+// so an existing tag is never moved.
+func (s *TagService) CreateTag(ctx context.Context, name string, parentID *string) (*model.Tag, error) {
+	name, err := normalizeTagName(name)
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.Tag{
-		ID:   strconv.Itoa(tagID),
-		Name: name,
-	}, nil
+	if parentID == nil {
+		tagID, err := s.Repo.Create(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		return s.getTag(ctx, tagID)
+	}
+
+	parent, err := parseID("parent tag ID", *parentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getTag(ctx, parent); err != nil {
+		return nil, err
+	}
+
+	// A name in use is a conflict rather than a silent move of the existing tag
+	tagID, err := s.Repo.CreateWithParent(ctx, name, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getTag(ctx, tagID)
 }
 
 // GetAllTags retrieves all tags
@@ -50,3 +89,342 @@ func (s *TagService) GetAllTags(ctx context.Context) ([]*model.Tag, error) {
 
 	return tags, nil
 }
+
+// GetTagByID retrieves a tag by its ID, returning nil when it does not exist
+func (s *TagService) GetTagByID(ctx context.Context, id string) (*model.Tag, error) {
+	tagID, err := parseID("tag ID", id)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTag, err := s.Repo.FindByID(ctx, tagID)
+	if err != nil || dbTag == nil {
+		return nil, err
+	}
+
+	return db.TagToGraphQL(dbTag), nil
+}
+
+// RenameTag changes the name of a tag, which must not be used by another one
+func (s *TagService) RenameTag(ctx context.Context, id string, name string) (*model.Tag, error) {
+	tagID, err := parseID("tag ID", id)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err = normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getTag(ctx, tagID); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.Rename(ctx, tagID, name); err != nil {
+		return nil, err
+	}
+
+	return s.getTag(ctx, tagID)
+}
+
+// MoveTag places a tag under parentID, or at the top level when parentID is nil.
+// A tag cannot be moved under itself or one of its descendants.
+func (s *TagService) MoveTag(ctx context.Context, id string, parentID *string) (*model.Tag, error) {
+	tagID, err := parseID("tag ID", id)
+	if err != nil {
+		return nil, err
+	}
+
+	var parent *int
+	if parentID != nil {
+		p, err := parseID("parent tag ID", *parentID)
+		if err != nil {
+			return nil, err
+		}
+		parent = &p
+	}
+
+	if _, err := s.getTag(ctx, tagID); err != nil {
+		return nil, err
+	}
+	if parent != nil {
+		if _, err := s.getTag(ctx, *parent); err != nil {
+			return nil, err
+		}
+	}
+
+	// The repository rejects loops in the same transaction as the move
+	if err := s.Repo.SetParent(ctx, tagID, parent); err != nil {
+		return nil, err
+	}
+
+	return s.getTag(ctx, tagID)
+}
+
+// DeleteTag deletes a tag, removing it from quizzes and questions. Its children move up to its parent.
+func (s *TagService) DeleteTag(ctx context.Context, id string) (bool, error) {
+	tagID, err := parseID("tag ID", id)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.Repo.Delete(ctx, tagID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// MergeTags folds the source tags into the target tag: their quizzes, questions and children
+// are moved to the target and the sources are deleted
+func (s *TagService) MergeTags(ctx context.Context, sourceIDs []string, targetID string) (*model.Tag, error) {
+	if len(sourceIDs) == 0 {
+		return nil, apperrors.InvalidArgument("at least one source tag is required")
+	}
+
+	target, err := parseID("target tag ID", targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]int, 0, len(sourceIDs))
+	for _, sourceID := range sourceIDs {
+		source, err := parseID("source tag ID", sourceID)
+		if err != nil {
+			return nil, err
+		}
+		if source == target {
+			return nil, apperrors.InvalidArgument("tag %d cannot be merged into itself", target)
+		}
+		if !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+
+	tree, err := s.loadTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tagID := range append([]int{target}, sources...) {
+		if _, ok := tree[tagID]; !ok {
+			return nil, apperrors.NotFound("tag %d not found", tagID)
+		}
+	}
+
+	// The children of a source move to the target, which would make a loop if the target is one of them
+	for _, source := range sources {
+		if tree.isAncestor(source, target) {
+			return nil, apperrors.InvalidArgument("tag %d cannot be merged into its descendant %d", source, target)
+		}
+	}
+
+	if err := s.Repo.Merge(ctx, sources, target); err != nil {
+		return nil, err
+	}
+
+	return s.getTag(ctx, target)
+}
+
+// SuggestTags retrieves the tags whose name starts with prefix, ignoring case, most used first
+func (s *TagService) SuggestTags(ctx context.Context, prefix string, limit *int) ([]*model.Tag, error) {
+	n, err := suggestionLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTags, err := s.Repo.FindByPrefix(ctx, strings.TrimSpace(prefix), n)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []*model.Tag{}
+	for _, dbTag := range dbTags {
+		tags = append(tags, db.TagToGraphQL(dbTag))
+	}
+
+	return tags, nil
+}
+
+// SuggestTagsForQuestion suggests tags for a question from the tags of existing questions with
+// similar content. Each similar question votes for its tags with its similarity, the share of
+// keywords both questions have.
+func (s *TagService) SuggestTagsForQuestion(ctx context.Context, content string, limit *int) ([]*model.Tag, error) {
+	n, err := suggestionLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	keywords := keywordsOf(content)
+	if len(keywords) == 0 {
+		return []*model.Tag{}, nil
+	}
+
+	searched := slices.Clone(keywords)
+	if len(searched) > maxKeywords {
+		// Longer words tell more about a question
+		slices.SortStableFunc(searched, func(a, b string) int {
+			return utf8.RuneCountInString(b) - utf8.RuneCountInString(a)
+		})
+		searched = searched[:maxKeywords]
+	}
+
+	questions, err := s.QuestionRepo.FindTaggedByKeywords(ctx, searched, similarQuestionLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	similarity := make(map[int]float64, len(questions))
+	questionIDs := make([]int, 0, len(questions))
+	for _, q := range questions {
+		if score := jaccard(keywords, keywordsOf(q.Content)); score > 0 {
+			similarity[q.ID] = score
+			questionIDs = append(questionIDs, q.ID)
+		}
+	}
+
+	tagsByQuestion, err := s.QuestionRepo.FindTagsByQuestionIDs(ctx, questionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int]float64)
+	tagsByID := make(map[int]*models.Tag)
+	for _, questionID := range questionIDs {
+		for _, dbTag := range tagsByQuestion[questionID] {
+			scores[dbTag.ID] += similarity[questionID]
+			tagsByID[dbTag.ID] = dbTag
+		}
+	}
+
+	ranked := make([]*models.Tag, 0, len(tagsByID))
+	for _, dbTag := range tagsByID {
+		ranked = append(ranked, dbTag)
+	}
+	slices.SortFunc(ranked, func(a, b *models.Tag) int {
+		if scores[a.ID] != scores[b.ID] {
+			if scores[a.ID] > scores[b.ID] {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	tags := []*model.Tag{}
+	for _, dbTag := range ranked[:min(n, len(ranked))] {
+		tags = append(tags, db.TagToGraphQL(dbTag))
+	}
+
+	return tags, nil
+}
+
+// suggestionLimit checks the number of suggestions asked for, defaulting to defaultSuggestionLimit
+func suggestionLimit(limit *int) (int, error) {
+	if limit == nil {
+		return defaultSuggestionLimit, nil
+	}
+	if *limit < 1 || *limit > maxSuggestionLimit {
+		return 0, apperrors.InvalidArgument("limit must be between 1 and %d", maxSuggestionLimit)
+	}
+	return *limit, nil
+}
+
+// stopWords are common words that say nothing about the subject of a question
+var stopWords = map[string]bool{
+	"the": true, "and": true, "are": true, "for": true, "was": true, "were": true, "what": true,
+	"which": true, "who": true, "whom": true, "whose": true, "how": true, "why": true, "when": true,
+	"where": true, "this": true, "that": true, "these": true, "those": true, "with": true, "from": true,
+	"does": true, "did": true, "has": true, "have": true, "not": true, "its": true, "into": true,
+	"than": true, "then": true, "there": true, "their": true, "true": true, "false": true,
+}
+
+// keywordsOf returns the distinct lowercase words of a text, leaving out stop words and words
+// shorter than 3 characters
+func keywordsOf(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var keywords []string
+	for _, word := range words {
+		if utf8.RuneCountInString(word) < 3 || stopWords[word] || slices.Contains(keywords, word) {
+			continue
+		}
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
+
+// jaccard returns the number of words two sets share over the number of words in either
+func jaccard(a, b []string) float64 {
+	shared := 0
+	for _, word := range a {
+		if slices.Contains(b, word) {
+			shared++
+		}
+	}
+	if shared == 0 {
+		return 0
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// getTag retrieves a tag that must exist
+func (s *TagService) getTag(ctx context.Context, tagID int) (*model.Tag, error) {
+	dbTag, err := s.Repo.FindByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+
+	if dbTag == nil {
+		return nil, apperrors.NotFound("tag %d not found", tagID)
+	}
+
+	return db.TagToGraphQL(dbTag), nil
+}
+
+// tagTree maps every tag ID to the tag
+type tagTree map[int]*models.Tag
+
+func (s *TagService) loadTree(ctx context.Context) (tagTree, error) {
+	dbTags, err := s.Repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make(tagTree, len(dbTags))
+	for _, dbTag := range dbTags {
+		tree[dbTag.ID] = dbTag
+	}
+	return tree, nil
+}
+
+// isAncestor reports whether ancestor is tagID itself or one of the tags above it
+func (t tagTree) isAncestor(ancestor, tagID int) bool {
+	for seen := 0; seen <= len(t); seen++ {
+		if tagID == ancestor {
+			return true
+		}
+		tag, ok := t[tagID]
+		if !ok || tag.ParentID == nil {
+			return false
+		}
+		tagID = *tag.ParentID
+	}
+	return false
+}
+
+// normalizeTagName trims a tag name and checks that it fits the tags table
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", apperrors.InvalidArgument("tag name must not be empty")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", apperrors.InvalidArgument("tag name must be at most %d characters", maxTagNameLength)
+	}
+	return name, nil
+}
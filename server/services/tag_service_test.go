@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"quiz-log/apperrors"
+	"quiz-log/models"
+	"quiz-log/repository/memory"
+)
+
+// goTags is the hierarchy Go > Concurrency > Channels, with Rust at the top level
+func goTags() []*models.Tag {
+	return []*models.Tag{
+		{ID: 1, Name: "Go"},
+		{ID: 2, Name: "Concurrency", ParentID: intPtr(1)},
+		{ID: 3, Name: "Channels", ParentID: intPtr(2)},
+		{ID: 4, Name: "Rust"},
+	}
+}
+
+// newMemoryTagService creates a TagService on an empty in-memory store
+func newMemoryTagService() *TagService {
+	store := memory.NewStore()
+	return &TagService{
+		Repo:         memory.NewTagRepository(store),
+		QuestionRepo: memory.NewQuestionRepository(store),
+	}
+}
+
+// newGoTagService creates a TagService on an in-memory store holding goTags
+func newGoTagService(t *testing.T) *TagService {
+	t.Helper()
+
+	service := newMemoryTagService()
+	ctx := context.Background()
+
+	for _, tag := range goTags() {
+		id, err := service.Repo.Create(ctx, tag.Name)
+		if err != nil || id != tag.ID {
+			t.Fatalf("failed to create tag %s: %v", tag.Name, err)
+		}
+		if err := service.Repo.SetParent(ctx, id, tag.ParentID); err != nil {
+			t.Fatalf("failed to place tag %s: %v", tag.Name, err)
+		}
+	}
+
+	return service
+}
+
+// tagParents maps the name of every stored tag to its parent ID, or 0 at the top level
+func tagParents(t *testing.T, service *TagService) map[string]int {
+	t.Helper()
+
+	tags, err := service.Repo.FindAll(context.Background())
+	if err != nil {
+		t.Fatalf("failed to load tags: %v", err)
+	}
+
+	parents := make(map[string]int, len(tags))
+	for _, tag := range tags {
+		parents[tag.Name] = 0
+		if tag.ParentID != nil {
+			parents[tag.Name] = *tag.ParentID
+		}
+	}
+	return parents
+}
+
+func TestTagService_CreateTag_WithParent(t *testing.T) {
+	service := newGoTagService(t)
+
+	// Execute
+	tag, err := service.CreateTag(context.Background(), "  Goroutines ", stringPtr("2"))
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tag.Name != "Goroutines" || tag.ParentID == nil || *tag.ParentID != "2" {
+		t.Errorf("expected 'Goroutines' under tag 2, got %+v", tag)
+	}
+
+	if parent := tagParents(t, service)["Goroutines"]; parent != 2 {
+		t.Errorf("expected stored parent 2, got %d", parent)
+	}
+}
+
+func TestTagService_CreateTag_ExistingWithParent(t *testing.T) {
+	service := newGoTagService(t)
+
+	// Execute
+	_, err := service.CreateTag(context.Background(), "Rust", stringPtr("2"))
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeConflict {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeConflict, code)
+	}
+
+	if parent := tagParents(t, service)["Rust"]; parent != 0 {
+		t.Errorf("expected the existing tag to stay at the top level, got parent %d", parent)
+	}
+}
+
+func TestTagService_RenameTag_EmptyName(t *testing.T) {
+	service := newGoTagService(t)
+
+	// Execute
+	_, err := service.RenameTag(context.Background(), "1", "   ")
+
+	// Assert
+	if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+		t.Errorf("expected code '%s', got '%s'", apperrors.CodeInvalidArgument, code)
+	}
+}
+
+func TestTagService_MoveTag_RejectsLoops(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		parentID string
+		code     apperrors.Code
+	}{
+		{name: "under itself", id: "2", parentID: "2", code: apperrors.CodeInvalidArgument},
+		{name: "under its grandchild", id: "1", parentID: "3", code: apperrors.CodeInvalidArgument},
+		{name: "under a missing tag", id: "1", parentID: "9", code: apperrors.CodeNotFound},
+		{name: "missing tag", id: "9", parentID: "1", code: apperrors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newGoTagService(t)
+
+			// Execute
+			_, err := service.MoveTag(context.Background(), tt.id, &tt.parentID)
+
+			// Assert
+			if code := apperrors.As(err).Code; code != tt.code {
+				t.Errorf("expected code '%s', got '%s'", tt.code, code)
+			}
+
+			// The hierarchy is left as it was
+			expected := map[string]int{"Go": 0, "Concurrency": 1, "Channels": 2, "Rust": 0}
+			if parents := tagParents(t, service); !maps.Equal(parents, expected) {
+				t.Errorf("expected parents %v, got %v", expected, parents)
+			}
+		})
+	}
+}
+
+func TestTagService_MoveTag_ToTopLevel(t *testing.T) {
+	service := newGoTagService(t)
+
+	// Execute
+	tag, err := service.MoveTag(context.Background(), "3", nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tag.ParentID != nil {
+		t.Errorf("expected a top-level tag, got parent %s", *tag.ParentID)
+	}
+
+	if parent := tagParents(t, service)["Channels"]; parent != 0 {
+		t.Errorf("expected no stored parent, got %d", parent)
+	}
+}
+
+func TestTagService_MergeTags(t *testing.T) {
+	service := newGoTagService(t)
+
+	// Execute: duplicate sources are merged once
+	tag, err := service.MergeTags(context.Background(), []string{"3", "4", "3"}, "1")
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tag.ID != "1" {
+		t.Errorf("expected target tag '1', got '%s'", tag.ID)
+	}
+
+	expected := map[string]int{"Go": 0, "Concurrency": 1}
+	if parents := tagParents(t, service); !maps.Equal(parents, expected) {
+		t.Errorf("expected tags %v, got %v", expected, parents)
+	}
+}
+
+func TestTagService_MergeTags_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		sourceIDs []string
+		targetID  string
+		code      apperrors.Code
+	}{
+		{name: "no sources", sourceIDs: nil, targetID: "1", code: apperrors.CodeInvalidArgument},
+		{name: "into itself", sourceIDs: []string{"1"}, targetID: "1", code: apperrors.CodeInvalidArgument},
+		{name: "into a descendant", sourceIDs: []string{"1"}, targetID: "3", code: apperrors.CodeInvalidArgument},
+		{name: "missing source", sourceIDs: []string{"9"}, targetID: "1", code: apperrors.CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newGoTagService(t)
+
+			// Execute
+			_, err := service.MergeTags(context.Background(), tt.sourceIDs, tt.targetID)
+
+			// Assert
+			if code := apperrors.As(err).Code; code != tt.code {
+				t.Errorf("expected code '%s', got '%s'", tt.code, code)
+			}
+
+			if count := len(tagParents(t, service)); count != 4 {
+				t.Errorf("expected 4 tags to remain, got %d", count)
+			}
+		})
+	}
+}
+
+func TestTagService_SuggestTags(t *testing.T) {
+	service := newGoTagService(t)
+
+	// Execute: the prefix is trimmed and matched ignoring case
+	tags, err := service.SuggestTags(context.Background(), " go ", nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 1 || tags[0].ID != "1" {
+		t.Errorf("unexpected tags: %v", tags)
+	}
+}
+
+func TestTagService_SuggestTags_InvalidLimit(t *testing.T) {
+	service := newGoTagService(t)
+
+	for _, limit := range []int{0, maxSuggestionLimit + 1} {
+		// Execute
+		_, err := service.SuggestTags(context.Background(), "go", intPtr(limit))
+
+		// Assert
+		if code := apperrors.As(err).Code; code != apperrors.CodeInvalidArgument {
+			t.Errorf("limit %d: expected code '%s', got '%s'", limit, apperrors.CodeInvalidArgument, code)
+		}
+	}
+}
+
+func TestTagService_SuggestTagsForQuestion(t *testing.T) {
+	service := newMemoryTagService()
+	ctx := context.Background()
+
+	// Questions tagged the way the suggestion should follow
+	tagged := []struct {
+		content string
+		tags    []string
+	}{
+		{content: "What is the capital of France?", tags: []string{"europe", "geography"}},
+		{content: "What is the capital of Japan?", tags: []string{"asia", "geography"}},
+		{content: "Capitalism started where?", tags: []string{"economics"}},
+	}
+	for _, q := range tagged {
+		questionID, err := service.QuestionRepo.Create(ctx, "SHORT_ANSWER", q.content, "PLAIN", nil, models.Payload{}, "answer", "EXACT", nil, "EASY")
+		if err != nil {
+			t.Fatalf("failed to create question: %v", err)
+		}
+
+		var tagIDs []string
+		for _, name := range q.tags {
+			tagID, err := service.Repo.Create(ctx, name)
+			if err != nil {
+				t.Fatalf("failed to create tag: %v", err)
+			}
+			tagIDs = append(tagIDs, strconv.Itoa(tagID))
+		}
+		if err := service.QuestionRepo.AssociateTags(ctx, questionID, tagIDs); err != nil {
+			t.Fatalf("failed to tag question: %v", err)
+		}
+	}
+
+	// Execute
+	tags, err := service.SuggestTagsForQuestion(ctx, "Which city is the capital of France?", intPtr(2))
+
+	// Assert: geography is voted by both questions, europe by the most similar one,
+	// and the question sharing no keyword is left out
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	if !slices.Equal(names, []string{"geography", "europe"}) {
+		t.Errorf("expected [geography europe], got %v", names)
+	}
+}
+
+func TestTagService_SuggestTagsForQuestion_NoKeywords(t *testing.T) {
+	service := newMemoryTagService()
+
+	// Execute
+	tags, err := service.SuggestTagsForQuestion(context.Background(), "Is it?", nil)
+
+	// Assert
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("expected no tags, got %v", tags)
+	}
+}
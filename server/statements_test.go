@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/client"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/sqlitedialect"
+
+	"quiz-log/blob"
+	"quiz-log/config"
+	"quiz-log/dataloader"
+	"quiz-log/db"
+	"quiz-log/db/sqlcount"
+	"quiz-log/graph"
+	"quiz-log/graph/model"
+	"quiz-log/graph/resolvers"
+	"quiz-log/pubsub"
+)
+
+// statementHarness serves the GraphQL API over an in-memory SQLite database,
+// counting the SQL statements every operation runs
+type statementHarness struct {
+	t        *testing.T
+	resolver *resolvers.Resolver
+	client   *client.Client
+	counter  *sqlcount.Counter
+}
+
+func newStatementHarness(t *testing.T) *statementHarness {
+	t.Helper()
+
+	// Leave loaders enough time to collect all keys of a level even on a busy machine,
+	// as a batch split in two would count as an extra statement
+	wait := dataloader.BatchWait
+	dataloader.BatchWait = 20 * time.Millisecond
+	t.Cleanup(func() { dataloader.BatchWait = wait })
+
+	// Borrow the registered SQLite driver to open counted connections
+	opener, err := sql.Open(db.DriverSQLite, "")
+	if err != nil {
+		t.Fatalf("failed to load the SQLite driver: %v", err)
+	}
+	sqliteDriver := opener.Driver()
+	opener.Close()
+
+	counter := &sqlcount.Counter{}
+	sqldb := sql.OpenDB(sqlcount.NewConnector(sqliteDriver, "file::memory:?_pragma=foreign_keys(1)", counter))
+	sqldb.SetMaxOpenConns(1)
+
+	dbConn := bun.NewDB(sqldb, sqlitedialect.New())
+	t.Cleanup(func() { dbConn.Close() })
+
+	if err := autoMigrate(context.Background(), sqldb, db.DriverSQLite); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	blobs, err := blob.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to open attachment storage: %v", err)
+	}
+
+	resolver := newResolver(dbConn, pubsub.NewMemoryBus(), blobs, config.DefaultMaxAttachmentSize)
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	srv.AddTransport(transport.POST{})
+
+	return &statementHarness{
+		t:        t,
+		resolver: resolver,
+		client:   client.New(dataloader.Middleware(newLoaders(resolver))(srv)),
+		counter:  counter,
+	}
+}
+
+// seed creates published quizzes of three tagged questions, each with an attempt answering them all
+func (h *statementHarness) seed(quizzes int) {
+	h.t.Helper()
+	ctx := context.Background()
+
+	tag, err := h.resolver.TagService.CreateTag(ctx, "seeded", nil)
+	if err != nil {
+		h.t.Fatalf("failed to create tag: %v", err)
+	}
+
+	for i := range quizzes {
+		quiz, err := h.resolver.QuizService.CreateQuiz(ctx, model.CreateQuizInput{
+			Title:  fmt.Sprintf("Quiz %d", i),
+			TagIDs: []string{tag.ID},
+		})
+		if err != nil {
+			h.t.Fatalf("failed to create quiz: %v", err)
+		}
+
+		var answers []*model.AnswerInput
+		for j := range 3 {
+			question, err := h.resolver.QuestionService.CreateQuestion(ctx, model.CreateQuestionInput{
+				QuizID:        &quiz.ID,
+				Type:          model.QuestionTypeShortAnswer,
+				Content:       fmt.Sprintf("Question %d of quiz %d", j, i),
+				CorrectAnswer: "right",
+				Difficulty:    model.DifficultyEasy,
+				TagIDs:        []string{tag.ID},
+			})
+			if err != nil {
+				h.t.Fatalf("failed to create question: %v", err)
+			}
+			answers = append(answers, &model.AnswerInput{QuestionID: question.ID, UserAnswer: "wrong"})
+		}
+
+		if _, err := h.resolver.QuizService.PublishQuiz(ctx, quiz.ID); err != nil {
+			h.t.Fatalf("failed to publish quiz: %v", err)
+		}
+		if _, err := h.resolver.AttemptService.SubmitAttempt(ctx, model.SubmitAttemptInput{QuizID: quiz.ID, Answers: answers}); err != nil {
+			h.t.Fatalf("failed to submit attempt: %v", err)
+		}
+	}
+}
+
+// statements runs an operation and returns the number of SQL statements it ran
+func (h *statementHarness) statements(query string, options ...client.Option) int {
+	h.t.Helper()
+
+	h.counter.Reset()
+	var resp map[string]any
+	if err := h.client.Post(query, &resp, options...); err != nil {
+		h.t.Fatalf("operation failed: %v", err)
+	}
+	return h.counter.Count()
+}
+
+// TestStatements_Queries checks that reading more records does not take more statements,
+// which would show a field loaded once per parent instead of through a dataloader
+func TestStatements_Queries(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{
+			name:  "quiz tree",
+			query: `{ quizzes { id tags { id quizCount questionCount lastUsedAt } questions { id tags { id } } attempts { id answers { id question { id tags { id } } } } } }`,
+		},
+		{
+			name:  "attempts",
+			query: `{ attempts { id answers { id question { id content } } } }`,
+		},
+		{
+			name:  "questions",
+			query: `{ questions { id contentHTML explanationHTML tags { id name } attachments { id url } } }`,
+		},
+		{
+			name:  "wrong questions",
+			query: `{ wrongQuestions { id tags { id } } }`,
+		},
+		{
+			name:  "tags",
+			query: `{ tags { id quizCount questionCount lastUsedAt children { id } } }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			small := newStatementHarness(t)
+			small.seed(1)
+
+			large := newStatementHarness(t)
+			large.seed(4)
+
+			// Execute
+			want := small.statements(tt.query)
+			got := large.statements(tt.query)
+
+			// Assert
+			if got != want {
+				t.Errorf("expected %d statements for 4 quizzes as for 1, got %d", want, got)
+			}
+		})
+	}
+}
+
+// TestStatements_TagTree checks that the parents and children of tags are loaded in one statement per level,
+// however many tags there are
+func TestStatements_TagTree(t *testing.T) {
+	// tree creates a parent tag with the given number of children, each with a child of its own
+	tree := func(h *statementHarness, children int) {
+		ctx := context.Background()
+
+		parent, err := h.resolver.TagService.CreateTag(ctx, "parent", nil)
+		if err != nil {
+			h.t.Fatalf("failed to create tag: %v", err)
+		}
+
+		for i := range children {
+			child, err := h.resolver.TagService.CreateTag(ctx, fmt.Sprintf("child %d", i), &parent.ID)
+			if err != nil {
+				h.t.Fatalf("failed to create tag: %v", err)
+			}
+			if _, err := h.resolver.TagService.CreateTag(ctx, fmt.Sprintf("grandchild %d", i), &child.ID); err != nil {
+				h.t.Fatalf("failed to create tag: %v", err)
+			}
+		}
+	}
+
+	small := newStatementHarness(t)
+	tree(small, 1)
+
+	large := newStatementHarness(t)
+	tree(large, 4)
+
+	query := `{ tags { id parent { id name parent { id } } children { id children { id } } } }`
+
+	// Execute
+	want := small.statements(query)
+	got := large.statements(query)
+
+	// Assert
+	if got != want {
+		t.Errorf("expected %d statements for 9 tags as for 3, got %d", want, got)
+	}
+}
+
+// TestStatements_SubmitAttempt checks that submitting an attempt takes one statement per answer, to store it
+func TestStatements_SubmitAttempt(t *testing.T) {
+	h := newStatementHarness(t)
+	h.seed(1)
+
+	var resp struct {
+		Questions []struct{ ID string }
+	}
+	h.client.MustPost(`{ questions { id } }`, &resp)
+
+	submit := func(answered int) int {
+		answers := make([]map[string]any, answered)
+		for i := range answers {
+			answers[i] = map[string]any{"questionID": resp.Questions[i].ID, "userAnswer": "wrong"}
+		}
+
+		return h.statements(
+			`mutation ($input: SubmitAttemptInput!) { submitAttempt(input: $input) { score wrongQuestions { id tags { id } } attempt { answers { id } } } }`,
+			client.Var("input", map[string]any{"quizID": "1", "answers": answers}),
+		)
+	}
+
+	// Execute
+	one := submit(1)
+	three := submit(3)
+
+	// Assert
+	if three-one != 2 {
+		t.Errorf("expected 2 more statements for 2 more answers, got %d for 1 answer and %d for 3", one, three)
+	}
+}
+
+// TestStatements_BulkQuestions checks that bulk question operations take as many statements for 12 questions as for 3
+func TestStatements_BulkQuestions(t *testing.T) {
+	tests := []struct {
+		name     string
+		mutation string
+	}{
+		{
+			name:     "update",
+			mutation: `mutation ($ids: [ID!]!) { bulkUpdateQuestions(ids: $ids, patch: {difficulty: HARD, explanation: "bulk"}) { id tags { id } } }`,
+		},
+		{
+			name:     "delete",
+			mutation: `mutation ($ids: [ID!]!) { bulkDeleteQuestions(ids: $ids) }`,
+		},
+		{
+			name:     "tag",
+			mutation: `mutation ($ids: [ID!]!) { bulkTagQuestions(ids: $ids, addTagIDs: ["2"], removeTagIDs: ["1"]) { id tags { id } } }`,
+		},
+		{
+			name:     "move",
+			mutation: `mutation ($ids: [ID!]!) { moveQuestions(ids: $ids, targetQuizID: "1") { id position tags { id } } }`,
+		},
+		{
+			name:     "reorder",
+			mutation: `mutation ($ids: [ID!]!) { reorderQuestions(quizID: "1", orderedIDs: $ids) { id position } }`,
+		},
+	}
+
+	// prepare tags every question and moves them all to the first quiz, returning their IDs from last to first
+	prepare := func(h *statementHarness) []string {
+		var resp struct {
+			Questions []struct{ ID string }
+		}
+		h.client.MustPost(`{ questions { id } }`, &resp)
+
+		var ids []string
+		for _, q := range slices.Backward(resp.Questions) {
+			ids = append(ids, q.ID)
+		}
+
+		var discard map[string]any
+		h.client.MustPost(`mutation ($ids: [ID!]!) { moveQuestions(ids: $ids, targetQuizID: "1") { id } }`, &discard, client.Var("ids", ids))
+		h.client.MustPost(`mutation { createTag(name: "bulk") { id } }`, &discard)
+		return ids
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			small := newStatementHarness(t)
+			small.seed(1)
+			smallIDs := prepare(small)
+
+			large := newStatementHarness(t)
+			large.seed(4)
+			largeIDs := prepare(large)
+
+			// Execute
+			want := small.statements(tt.mutation, client.Var("ids", smallIDs))
+			got := large.statements(tt.mutation, client.Var("ids", largeIDs))
+
+			// Assert
+			if got != want {
+				t.Errorf("expected %d statements for 12 questions as for 3, got %d", want, got)
+			}
+		})
+	}
+}
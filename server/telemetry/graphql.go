@@ -0,0 +1,127 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"quiz-log/apperrors"
+)
+
+// otherOperation labels the metrics of operations whose name is not known in advance
+const otherOperation = "other"
+
+// GraphQL records metrics and spans for every GraphQL operation and resolver
+type GraphQL struct {
+	// KnownOperations holds the operation names metrics are labelled with; clients choose
+	// their names freely, so any other operation is counted as "other"
+	KnownOperations map[string]bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = GraphQL{}
+
+func (GraphQL) ExtensionName() string {
+	return "Telemetry"
+}
+
+func (GraphQL) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse measures an operation, or each event of a subscription
+func (g GraphQL) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	opCtx := graphql.GetOperationContext(ctx)
+	name := operationName(opCtx)
+	operationType := "unknown"
+	if opCtx.Operation != nil {
+		operationType = string(opCtx.Operation.Operation)
+	}
+
+	ctx, span := tracer.Start(ctx, "graphql."+operationType+" "+name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.name", name),
+			attribute.String("graphql.operation.type", operationType),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	resp := next(ctx)
+	label := g.metricOperation(name)
+	observeOperation(label, operationType, time.Since(start))
+
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+		for _, err := range resp.Errors {
+			code, _ := err.Extensions["code"].(string)
+			if c, ok := err.Extensions["code"].(apperrors.Code); ok {
+				code = string(c)
+			}
+			if code == "" {
+				code = string(apperrors.CodeInternal)
+			}
+			countError(label, code)
+		}
+	}
+
+	return resp
+}
+
+// InterceptField measures resolvers; fields read straight from a struct are not worth a span
+func (GraphQL) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := tracer.Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(
+			attribute.String("graphql.field.object", fc.Object),
+			attribute.String("graphql.field.name", fc.Field.Name),
+			attribute.String("graphql.field.path", fc.Path().String()),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	res, err := next(ctx)
+	observeField(fc.Object, fc.Field.Name, time.Since(start))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return res, err
+}
+
+func operationName(opCtx *graphql.OperationContext) string {
+	if opCtx.OperationName != "" {
+		return opCtx.OperationName
+	}
+	if opCtx.Operation != nil && opCtx.Operation.Name != "" {
+		return opCtx.Operation.Name
+	}
+	return "anonymous"
+}
+
+// metricOperation returns the operation label of metrics, keeping their number of series bounded
+func (g GraphQL) metricOperation(name string) string {
+	if g.KnownOperations[name] {
+		return name
+	}
+	return otherOperation
+}
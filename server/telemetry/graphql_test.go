@@ -0,0 +1,19 @@
+package telemetry
+
+import "testing"
+
+func TestGraphQL_MetricOperation(t *testing.T) {
+	g := GraphQL{KnownOperations: map[string]bool{"QuizListQuery": true}}
+
+	tests := map[string]string{
+		"QuizListQuery": "QuizListQuery",
+		"RandomName123": "other",
+		"anonymous":     "other",
+	}
+
+	for name, expected := range tests {
+		if label := g.metricOperation(name); label != expected {
+			t.Errorf("expected '%s' for '%s', got '%s'", expected, name, label)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package telemetry
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "quizlog"
+
+var (
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "graphql_operation_duration_seconds",
+		Help:      "Duration of GraphQL operations.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "type"})
+
+	fieldDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "graphql_field_duration_seconds",
+		Help:      "Duration of GraphQL field resolvers.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"object", "field"})
+
+	operationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "graphql_errors_total",
+		Help:      "Errors returned by GraphQL operations, by error code.",
+	}, []string{"operation", "code"})
+
+	dataloaderBatchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "dataloader_batch_size",
+		Help:      "Number of keys loaded per dataloader batch.",
+		Buckets:   []float64{1, 2, 5, 10, 20, 50, 100, 200},
+	}, []string{"loader"})
+
+	sqlQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sql_query_duration_seconds",
+		Help:      "Duration of SQL statements, by statement kind.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"statement", "status"})
+)
+
+// RegisterDBStats exposes the connection pool statistics of a database
+func RegisterDBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveBatch records the number of keys a dataloader loaded at once
+func ObserveBatch(loader string, size int) {
+	dataloaderBatchSize.WithLabelValues(loader).Observe(float64(size))
+}
+
+func observeOperation(operation, operationType string, duration time.Duration) {
+	operationDuration.WithLabelValues(operation, operationType).Observe(duration.Seconds())
+}
+
+func observeField(object, field string, duration time.Duration) {
+	fieldDuration.WithLabelValues(object, field).Observe(duration.Seconds())
+}
+
+func countError(operation, code string) {
+	operationErrors.WithLabelValues(operation, code).Inc()
+}
+
+func observeQuery(statement string, err error, duration time.Duration) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	sqlQueryDuration.WithLabelValues(statement, status).Observe(duration.Seconds())
+}
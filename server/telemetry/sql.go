@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dbSystem is the db.system attribute of SQL spans, named as in the OpenTelemetry conventions
+var dbSystem atomic.Value
+
+// SetDBDriver sets the database driver SQL statements run on, "postgres" or "sqlite"
+func SetDBDriver(driver string) {
+	switch driver {
+	case "sqlite":
+		dbSystem.Store("sqlite")
+	default:
+		dbSystem.Store("postgresql")
+	}
+}
+
+// StartQuery starts measuring a SQL statement; call the returned function with its error once it completes
+func StartQuery(ctx context.Context, query string) (context.Context, func(err error)) {
+	statement := statementKind(query)
+	system, _ := dbSystem.Load().(string)
+	if system == "" {
+		system = "postgresql"
+	}
+
+	ctx, span := tracer.Start(ctx, "sql."+statement,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", system),
+			attribute.String("db.statement", query),
+		),
+	)
+
+	start := time.Now()
+	return ctx, func(err error) {
+		// Finding no row is an answer, not a failure
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+		}
+
+		observeQuery(statement, err, time.Since(start))
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// statementKind returns the lowercased first keyword of a query, such as select or insert
+func statementKind(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+
+	switch kind := strings.ToLower(fields[0]); kind {
+	case "select", "insert", "update", "delete", "with":
+		return kind
+	default:
+		return "other"
+	}
+}
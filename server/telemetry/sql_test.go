@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartQuery(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := context.Background()
+	okBefore := testutil.CollectAndCount(sqlQueryDuration)
+	SetDBDriver("sqlite")
+	defer SetDBDriver("postgres")
+
+	// Execute
+	_, done := StartQuery(ctx, "SELECT id FROM quizzes WHERE id = $1")
+	done(sql.ErrNoRows)
+
+	_, done = StartQuery(ctx, "INSERT INTO tags (name) VALUES ($1)")
+	done(errors.New("duplicate key"))
+
+	// Assert
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	if spans[0].Name() != "sql.select" || spans[0].Status().Code == codes.Error {
+		t.Errorf("expected successful sql.select span, got %s with status %v", spans[0].Name(), spans[0].Status().Code)
+	}
+
+	var system string
+	for _, attr := range spans[0].Attributes() {
+		if attr.Key == "db.system" {
+			system = attr.Value.AsString()
+		}
+	}
+	if system != "sqlite" {
+		t.Errorf("expected db.system 'sqlite', got '%s'", system)
+	}
+
+	if spans[1].Name() != "sql.insert" || spans[1].Status().Code != codes.Error {
+		t.Errorf("expected failed sql.insert span, got %s with status %v", spans[1].Name(), spans[1].Status().Code)
+	}
+
+	if count := testutil.CollectAndCount(sqlQueryDuration); count != okBefore+2 {
+		t.Errorf("expected %d query series, got %d", okBefore+2, count)
+	}
+}
+
+func TestStatementKind(t *testing.T) {
+	tests := map[string]string{
+		"SELECT * FROM quizzes":        "select",
+		"  update quizzes SET title=1": "update",
+		"WITH t AS (SELECT 1) SELECT":  "with",
+		"TRUNCATE quizzes":             "other",
+		"":                             "unknown",
+	}
+
+	for query, expected := range tests {
+		if kind := statementKind(query); kind != expected {
+			t.Errorf("expected '%s' for %q, got '%s'", expected, query, kind)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"quiz-log/grading"
+	"quiz-log/graph/model"
+	"quiz-log/models"
+)
+
+// Question holds the question fields checked by ValidateQuestion
+type Question struct {
+	Type          string
+	Content       string
+	ContentFormat string
+	Options       []string
+	Pairs         []models.MatchingPair
+	Numeric       *models.NumericAnswer
+	Blanks        []models.ClozeBlank
+	CorrectAnswer string
+	Grading       string
+	Difficulty    string
+}
+
+// trueFalseAnswers are the only answers a TRUE_FALSE question accepts
+var trueFalseAnswers = []string{"true", "false"}
+
+// partialCreditTypes are the question types that can be graded PARTIAL
+var partialCreditTypes = []model.QuestionType{model.QuestionTypeOrdering, model.QuestionTypeMatching, model.QuestionTypeCloze}
+
+// ValidateQuestion checks a question against the rules of its type.
+// Paths of the returned errors are relative to the question input.
+func ValidateQuestion(q Question) Errors {
+	var errs Errors
+
+	if strings.TrimSpace(q.Content) == "" {
+		errs.add("content must not be empty", "content")
+	}
+
+	// An empty format stands for the PLAIN default
+	if q.ContentFormat != "" && !model.ContentFormat(q.ContentFormat).IsValid() {
+		errs.add("content format must be one of PLAIN, MARKDOWN", "contentFormat")
+	}
+
+	if !model.Difficulty(q.Difficulty).IsValid() {
+		errs.add("difficulty must be one of EASY, MEDIUM, HARD", "difficulty")
+	}
+
+	switch model.QuestionType(q.Type) {
+	case model.QuestionTypeMultipleChoice:
+		validateMultipleChoice(q, &errs)
+	case model.QuestionTypeTrueFalse:
+		validateTrueFalse(q, &errs)
+	case model.QuestionTypeShortAnswer:
+		validateShortAnswer(q, &errs)
+	case model.QuestionTypeOrdering:
+		validateOrdering(q, &errs)
+	case model.QuestionTypeMatching:
+		validateMatching(q, &errs)
+	case model.QuestionTypeNumeric:
+		validateNumeric(q, &errs)
+	case model.QuestionTypeCloze:
+		validateCloze(q, &errs)
+	default:
+		errs.add("type must be one of MULTIPLE_CHOICE, TRUE_FALSE, SHORT_ANSWER, ORDERING, MATCHING, NUMERIC, CLOZE", "type")
+	}
+
+	if len(q.Pairs) > 0 && model.QuestionType(q.Type) != model.QuestionTypeMatching {
+		errs.add("only matching questions take pairs", "pairs")
+	}
+	if q.Numeric != nil && model.QuestionType(q.Type) != model.QuestionTypeNumeric {
+		errs.add("only numeric questions take a tolerance and units", "numeric")
+	}
+	if len(q.Blanks) > 0 && model.QuestionType(q.Type) != model.QuestionTypeCloze {
+		errs.add("only cloze questions take blanks", "blanks")
+	}
+
+	// An empty grading stands for the EXACT default
+	switch model.Grading(q.Grading) {
+	case "", model.GradingExact:
+	case model.GradingPartial:
+		if !slices.Contains(partialCreditTypes, model.QuestionType(q.Type)) {
+			errs.add("partial credit is only available for ORDERING, MATCHING and CLOZE questions", "grading")
+		}
+	default:
+		errs.add("grading must be one of EXACT, PARTIAL", "grading")
+	}
+
+	return errs
+}
+
+func validateMultipleChoice(q Question, errs *Errors) {
+	if len(q.Options) < 2 {
+		errs.add("multiple choice questions need at least two options", "options")
+	}
+
+	for i, option := range q.Options {
+		if strings.TrimSpace(option) == "" {
+			errs.add("option must not be empty", "options", i)
+		} else if slices.Index(q.Options, option) < i {
+			errs.add("option is a duplicate", "options", i)
+		}
+	}
+
+	if strings.TrimSpace(q.CorrectAnswer) == "" {
+		errs.add("correct answer must not be empty", "correctAnswer")
+	} else if len(q.Options) > 0 && !slices.Contains(q.Options, q.CorrectAnswer) {
+		errs.add("correct answer must be one of the options", "correctAnswer")
+	}
+}
+
+func validateTrueFalse(q Question, errs *Errors) {
+	for i, option := range q.Options {
+		if !slices.Contains(trueFalseAnswers, option) {
+			errs.add("option must be true or false", "options", i)
+		}
+	}
+
+	if !slices.Contains(trueFalseAnswers, q.CorrectAnswer) {
+		errs.add("correct answer must be true or false", "correctAnswer")
+	}
+}
+
+func validateShortAnswer(q Question, errs *Errors) {
+	if len(q.Options) > 0 {
+		errs.add("short answer questions do not take options", "options")
+	}
+
+	if strings.TrimSpace(q.CorrectAnswer) == "" {
+		errs.add("correct answer must not be empty", "correctAnswer")
+	}
+}
+
+func validateOrdering(q Question, errs *Errors) {
+	if len(q.Options) < 2 {
+		errs.add("ordering questions need at least two items", "options")
+	}
+
+	for i, option := range q.Options {
+		if strings.TrimSpace(option) == "" {
+			errs.add("option must not be empty", "options", i)
+		} else if slices.Index(q.Options, option) < i {
+			errs.add("option is a duplicate", "options", i)
+		}
+	}
+
+	if _, err := grading.ParseOrder(q.CorrectAnswer, len(q.Options)); err != nil {
+		errs.add("correct answer must give the option indexes in order, such as 2,0,1: "+err.Error(), "correctAnswer")
+	}
+}
+
+func validateMatching(q Question, errs *Errors) {
+	if len(q.Options) > 0 {
+		errs.add("matching questions take pairs, not options", "options")
+	}
+
+	if len(q.Pairs) < 2 {
+		errs.add("matching questions need at least two pairs", "pairs")
+	}
+
+	for i, pair := range q.Pairs {
+		if strings.TrimSpace(pair.Prompt) == "" {
+			errs.add("prompt must not be empty", "pairs", i, "prompt")
+		} else if slices.IndexFunc(q.Pairs, func(p models.MatchingPair) bool { return p.Prompt == pair.Prompt }) < i {
+			errs.add("prompt is a duplicate", "pairs", i, "prompt")
+		}
+
+		if strings.TrimSpace(pair.Answer) == "" {
+			errs.add("answer must not be empty", "pairs", i, "answer")
+		} else if slices.IndexFunc(q.Pairs, func(p models.MatchingPair) bool { return p.Answer == pair.Answer }) < i {
+			errs.add("answer is a duplicate", "pairs", i, "answer")
+		}
+	}
+
+	if q.CorrectAnswer != "" {
+		errs.add("matching questions take their answers from pairs", "correctAnswer")
+	}
+}
+
+func validateNumeric(q Question, errs *Errors) {
+	if len(q.Options) > 0 {
+		errs.add("numeric questions do not take options", "options")
+	}
+
+	if _, err := grading.ParseNumber(q.CorrectAnswer); err != nil {
+		errs.add("correct answer must be a number, such as 9.81 or 6.02e23", "correctAnswer")
+	}
+
+	if q.Numeric == nil {
+		return
+	}
+
+	if q.Numeric.Tolerance < 0 || math.IsNaN(q.Numeric.Tolerance) || math.IsInf(q.Numeric.Tolerance, 0) {
+		errs.add("tolerance must be a number of at least 0", "numeric", "tolerance")
+	}
+
+	if !model.ToleranceMode(q.Numeric.ToleranceMode).IsValid() {
+		errs.add("tolerance mode must be one of ABSOLUTE, RELATIVE", "numeric", "toleranceMode")
+	}
+
+	for i, unit := range q.Numeric.Units {
+		if strings.TrimSpace(unit) == "" {
+			errs.add("unit must not be empty", "numeric", "units", i)
+		} else if slices.Index(q.Numeric.Units, unit) < i {
+			errs.add("unit is a duplicate", "numeric", "units", i)
+		}
+	}
+}
+
+func validateCloze(q Question, errs *Errors) {
+	if len(q.Options) > 0 {
+		errs.add("cloze questions take blanks, not options", "options")
+	}
+
+	if len(q.Blanks) == 0 {
+		errs.add("cloze questions need at least one blank", "blanks")
+	}
+
+	// Every blank is marked exactly once in the content, and every marker has its blank
+	markers := grading.ClozeMarkers(q.Content)
+	for i, marker := range markers {
+		switch {
+		case marker < 1 || marker > len(q.Blanks):
+			errs.add(fmt.Sprintf("content marks blank {{%d}}, which is not defined", marker), "content")
+		case slices.Index(markers, marker) < i:
+			errs.add(fmt.Sprintf("content marks blank {{%d}} more than once", marker), "content")
+		}
+	}
+
+	for i, blank := range q.Blanks {
+		if !slices.Contains(markers, i+1) {
+			errs.add(fmt.Sprintf("blank is not marked in the content with {{%d}}", i+1), "blanks", i)
+		}
+
+		if !model.BlankStrategy(blank.Strategy).IsValid() {
+			errs.add("strategy must be one of EXACT, IGNORE_CASE, NUMERIC", "blanks", i, "strategy")
+		}
+
+		if len(blank.Accepted) == 0 {
+			errs.add("blank must accept at least one answer", "blanks", i, "accepted")
+		}
+
+		for j, accepted := range blank.Accepted {
+			if strings.TrimSpace(accepted) == "" {
+				errs.add("accepted answer must not be empty", "blanks", i, "accepted", j)
+			} else if slices.Index(blank.Accepted, accepted) < j {
+				errs.add("accepted answer is a duplicate", "blanks", i, "accepted", j)
+			} else if model.BlankStrategy(blank.Strategy) == model.BlankStrategyNumeric {
+				if _, err := grading.ParseNumber(accepted); err != nil {
+					errs.add("accepted answer must be a number when compared as one", "blanks", i, "accepted", j)
+				}
+			}
+		}
+	}
+
+	if q.CorrectAnswer != "" {
+		errs.add("cloze questions take their answers from blanks", "correctAnswer")
+	}
+}
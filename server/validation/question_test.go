@@ -0,0 +1,206 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	"quiz-log/models"
+)
+
+func TestValidateQuestion(t *testing.T) {
+	tests := []struct {
+		name     string
+		question Question
+		paths    [][]any
+	}{
+		{
+			name:     "valid multiple choice",
+			question: Question{Type: "MULTIPLE_CHOICE", Content: "Q", Options: []string{"A", "B"}, CorrectAnswer: "A", Difficulty: "EASY"},
+		},
+		{
+			name:     "valid true/false",
+			question: Question{Type: "TRUE_FALSE", Content: "Q", CorrectAnswer: "true", Difficulty: "MEDIUM"},
+		},
+		{
+			name:     "valid short answer",
+			question: Question{Type: "SHORT_ANSWER", Content: "Q", CorrectAnswer: "A", Difficulty: "HARD"},
+		},
+		{
+			name:     "empty content and unknown difficulty",
+			question: Question{Type: "SHORT_ANSWER", Content: " ", CorrectAnswer: "A", Difficulty: "IMPOSSIBLE"},
+			paths:    [][]any{{"content"}, {"difficulty"}},
+		},
+		{
+			name:     "unknown content format",
+			question: Question{Type: "SHORT_ANSWER", Content: "Q", ContentFormat: "HTML", CorrectAnswer: "A", Difficulty: "EASY"},
+			paths:    [][]any{{"contentFormat"}},
+		},
+		{
+			name:     "unknown type",
+			question: Question{Type: "ESSAY", Content: "Q", CorrectAnswer: "A", Difficulty: "EASY"},
+			paths:    [][]any{{"type"}},
+		},
+		{
+			name:     "multiple choice with too few options",
+			question: Question{Type: "MULTIPLE_CHOICE", Content: "Q", Options: []string{"A"}, CorrectAnswer: "A", Difficulty: "EASY"},
+			paths:    [][]any{{"options"}},
+		},
+		{
+			name:     "multiple choice with empty and duplicate options",
+			question: Question{Type: "MULTIPLE_CHOICE", Content: "Q", Options: []string{"A", "", "A"}, CorrectAnswer: "A", Difficulty: "EASY"},
+			paths:    [][]any{{"options", 1}, {"options", 2}},
+		},
+		{
+			name:     "multiple choice answer not among options",
+			question: Question{Type: "MULTIPLE_CHOICE", Content: "Q", Options: []string{"A", "B"}, CorrectAnswer: "C", Difficulty: "EASY"},
+			paths:    [][]any{{"correctAnswer"}},
+		},
+		{
+			name:     "true/false with invalid option and answer",
+			question: Question{Type: "TRUE_FALSE", Content: "Q", Options: []string{"true", "maybe"}, CorrectAnswer: "maybe", Difficulty: "EASY"},
+			paths:    [][]any{{"options", 1}, {"correctAnswer"}},
+		},
+		{
+			name:     "short answer with options and no answer",
+			question: Question{Type: "SHORT_ANSWER", Content: "Q", Options: []string{"A"}, Difficulty: "EASY"},
+			paths:    [][]any{{"options"}, {"correctAnswer"}},
+		},
+		{
+			name:     "valid ordering with partial credit",
+			question: Question{Type: "ORDERING", Content: "Q", Options: []string{"B", "A", "C"}, CorrectAnswer: "1,0,2", Grading: "PARTIAL", Difficulty: "EASY"},
+		},
+		{
+			name:     "ordering with too few items",
+			question: Question{Type: "ORDERING", Content: "Q", Options: []string{"A"}, CorrectAnswer: "0", Difficulty: "EASY"},
+			paths:    [][]any{{"options"}},
+		},
+		{
+			name:     "ordering answer not a permutation",
+			question: Question{Type: "ORDERING", Content: "Q", Options: []string{"A", "B", "C"}, CorrectAnswer: "0,1,1", Difficulty: "EASY"},
+			paths:    [][]any{{"correctAnswer"}},
+		},
+		{
+			name:     "ordering answer given as items",
+			question: Question{Type: "ORDERING", Content: "Q", Options: []string{"A", "B"}, CorrectAnswer: "A,B", Difficulty: "EASY"},
+			paths:    [][]any{{"correctAnswer"}},
+		},
+		{
+			name:     "valid matching",
+			question: Question{Type: "MATCHING", Content: "Q", Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}, {Prompt: "b", Answer: "2"}}, Grading: "PARTIAL", Difficulty: "EASY"},
+		},
+		{
+			name:     "matching with one pair",
+			question: Question{Type: "MATCHING", Content: "Q", Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}}, Difficulty: "EASY"},
+			paths:    [][]any{{"pairs"}},
+		},
+		{
+			name:     "matching with duplicate and empty sides",
+			question: Question{Type: "MATCHING", Content: "Q", Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}, {Prompt: "a", Answer: " "}, {Prompt: "c", Answer: "1"}}, Difficulty: "EASY"},
+			paths:    [][]any{{"pairs", 1, "prompt"}, {"pairs", 1, "answer"}, {"pairs", 2, "answer"}},
+		},
+		{
+			name:     "matching with options and an answer",
+			question: Question{Type: "MATCHING", Content: "Q", Options: []string{"1", "2"}, Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}, {Prompt: "b", Answer: "2"}}, CorrectAnswer: "a=1", Difficulty: "EASY"},
+			paths:    [][]any{{"options"}, {"correctAnswer"}},
+		},
+		{
+			name:     "pairs on multiple choice",
+			question: Question{Type: "MULTIPLE_CHOICE", Content: "Q", Options: []string{"A", "B"}, Pairs: []models.MatchingPair{{Prompt: "a", Answer: "1"}}, CorrectAnswer: "A", Difficulty: "EASY"},
+			paths:    [][]any{{"pairs"}},
+		},
+		{
+			name:     "valid numeric",
+			question: Question{Type: "NUMERIC", Content: "Q", CorrectAnswer: "9.81", Numeric: &models.NumericAnswer{Tolerance: 0.1, ToleranceMode: "ABSOLUTE", Units: []string{"m/s^2"}}, Difficulty: "EASY"},
+		},
+		{
+			name:     "numeric without a number",
+			question: Question{Type: "NUMERIC", Content: "Q", Options: []string{"1"}, CorrectAnswer: "about ten", Difficulty: "EASY"},
+			paths:    [][]any{{"options"}, {"correctAnswer"}},
+		},
+		{
+			name:     "numeric with a bad tolerance and units",
+			question: Question{Type: "NUMERIC", Content: "Q", CorrectAnswer: "1", Numeric: &models.NumericAnswer{Tolerance: -1, ToleranceMode: "LOOSE", Units: []string{"m", " ", "m"}}, Difficulty: "EASY"},
+			paths:    [][]any{{"numeric", "tolerance"}, {"numeric", "toleranceMode"}, {"numeric", "units", 1}, {"numeric", "units", 2}},
+		},
+		{
+			name:     "partial credit on numeric",
+			question: Question{Type: "NUMERIC", Content: "Q", CorrectAnswer: "1", Grading: "PARTIAL", Difficulty: "EASY"},
+			paths:    [][]any{{"grading"}},
+		},
+		{
+			name:     "tolerance on short answer",
+			question: Question{Type: "SHORT_ANSWER", Content: "Q", CorrectAnswer: "1", Numeric: &models.NumericAnswer{ToleranceMode: "ABSOLUTE"}, Difficulty: "EASY"},
+			paths:    [][]any{{"numeric"}},
+		},
+		{
+			name:     "valid cloze",
+			question: Question{Type: "CLOZE", Content: "{{1}} is the capital of {{2}}", Blanks: []models.ClozeBlank{{Accepted: []string{"Paris"}, Strategy: "EXACT"}, {Accepted: []string{"France"}, Strategy: "IGNORE_CASE"}}, Grading: "PARTIAL", Difficulty: "EASY"},
+		},
+		{
+			name:     "cloze without blanks",
+			question: Question{Type: "CLOZE", Content: "Nothing to fill in", Options: []string{"A"}, CorrectAnswer: "A", Difficulty: "EASY"},
+			paths:    [][]any{{"options"}, {"blanks"}, {"correctAnswer"}},
+		},
+		{
+			name:     "cloze with markers and blanks out of step",
+			question: Question{Type: "CLOZE", Content: "{{1}}, {{1}} and {{3}}", Blanks: []models.ClozeBlank{{Accepted: []string{"a"}, Strategy: "EXACT"}, {Accepted: []string{"b"}, Strategy: "EXACT"}}, Difficulty: "EASY"},
+			paths:    [][]any{{"content"}, {"content"}, {"blanks", 1}},
+		},
+		{
+			name:     "cloze with bad blanks",
+			question: Question{Type: "CLOZE", Content: "{{1}} {{2}}", Blanks: []models.ClozeBlank{{Accepted: []string{"a", " ", "a"}, Strategy: "FUZZY"}, {Accepted: []string{"ten"}, Strategy: "NUMERIC"}}, Difficulty: "EASY"},
+			paths:    [][]any{{"blanks", 0, "strategy"}, {"blanks", 0, "accepted", 1}, {"blanks", 0, "accepted", 2}, {"blanks", 1, "accepted", 0}},
+		},
+		{
+			name:     "cloze blank accepting nothing",
+			question: Question{Type: "CLOZE", Content: "{{1}}", Blanks: []models.ClozeBlank{{Strategy: "EXACT"}}, Difficulty: "EASY"},
+			paths:    [][]any{{"blanks", 0, "accepted"}},
+		},
+		{
+			name:     "blanks on short answer",
+			question: Question{Type: "SHORT_ANSWER", Content: "{{1}}", CorrectAnswer: "a", Blanks: []models.ClozeBlank{{Accepted: []string{"a"}, Strategy: "EXACT"}}, Difficulty: "EASY"},
+			paths:    [][]any{{"blanks"}},
+		},
+		{
+			name:     "partial credit on multiple choice",
+			question: Question{Type: "MULTIPLE_CHOICE", Content: "Q", Options: []string{"A", "B"}, CorrectAnswer: "A", Grading: "PARTIAL", Difficulty: "EASY"},
+			paths:    [][]any{{"grading"}},
+		},
+		{
+			name:     "unknown grading",
+			question: Question{Type: "ORDERING", Content: "Q", Options: []string{"A", "B"}, CorrectAnswer: "0,1", Grading: "LENIENT", Difficulty: "EASY"},
+			paths:    [][]any{{"grading"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateQuestion(tt.question)
+
+			var paths [][]any
+			for _, fieldErr := range errs {
+				paths = append(paths, fieldErr.Path)
+			}
+
+			if !reflect.DeepEqual(paths, tt.paths) {
+				t.Errorf("expected paths %v, got %v", tt.paths, paths)
+			}
+		})
+	}
+}
+
+func TestErrors_Prefix(t *testing.T) {
+	var errs Errors
+	errs.add("option must not be empty", "options", 2)
+
+	prefixed := errs.Prefix("input")
+
+	if prefixed.Error() != "input.options[2]: option must not be empty" {
+		t.Errorf("unexpected message '%s'", prefixed.Error())
+	}
+
+	// The original paths are left untouched
+	if len(errs[0].Path) != 2 {
+		t.Errorf("expected original path to have 2 elements, got %d", len(errs[0].Path))
+	}
+}
@@ -0,0 +1,60 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError describes why a single input field is invalid
+type FieldError struct {
+	Path    []any  `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", formatPath(e.Path), e.Message)
+}
+
+// Errors collects every invalid field of an input
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Prefix returns the errors with their paths nested under the given path
+func (e Errors) Prefix(path ...any) Errors {
+	prefixed := make(Errors, len(e))
+	for i, fieldErr := range e {
+		prefixed[i] = &FieldError{
+			Path:    append(append([]any{}, path...), fieldErr.Path...),
+			Message: fieldErr.Message,
+		}
+	}
+	return prefixed
+}
+
+func (e *Errors) add(message string, path ...any) {
+	*e = append(*e, &FieldError{Path: path, Message: message})
+}
+
+// formatPath renders a path like input.options[2]
+func formatPath(path []any) string {
+	var b strings.Builder
+	for _, p := range path {
+		switch v := p.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", v)
+		default:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			fmt.Fprint(&b, v)
+		}
+	}
+	return b.String()
+}
@@ -9,6 +9,7 @@ export default defineConfig({
       '/query': {
         target: 'http://localhost:8080',
         changeOrigin: true,
+        ws: true,
       },
     },
   },